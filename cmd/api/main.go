@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	_ "time/tzdata" // Embed zone data; the alpine runtime image ships none.
 
 	"github.com/manish-npx/todo-go-echo/internal/app"
 )
@@ -15,10 +15,13 @@ import (
 	"github.com/manish-npx/todo-go-echo/internal/handlers"
 	"github.com/manish-npx/todo-go-echo/internal/logger"
 	"github.com/manish-npx/todo-go-echo/internal/middleware"
+	"github.com/manish-npx/todo-go-echo/internal/reminder"
 	"github.com/manish-npx/todo-go-echo/internal/repository"
 	"github.com/manish-npx/todo-go-echo/internal/routes"
 	"github.com/manish-npx/todo-go-echo/internal/service"
+	"github.com/manish-npx/todo-go-echo/internal/storage"
 	"github.com/manish-npx/todo-go-echo/internal/validator"
+	"github.com/manish-npx/todo-go-echo/internal/workflow"
 	"gorm.io/gorm"
 )
 
@@ -27,6 +30,8 @@ type App struct {
 	Config *config.Config
 	Echo   *echo.Echo
 	GormDB *gorm.DB
+	// Reminders is nil unless reminders are enabled.
+	Reminders *reminder.Scheduler
 }
 
 // New builds the full application graph (config, db, handlers, routes, middleware).
@@ -45,13 +50,49 @@ func New(configPath string) (*App, error) {
 		return nil, fmt.Errorf("gorm bootstrap failed: %w", err)
 	}
 	todoRepo := repository.NewTodoRepository(gormDB)
+	todoListRepo := repository.NewTodoListRepository(gormDB)
+	todoCommentRepo := repository.NewTodoCommentRepository(gormDB)
+	todoAttachmentRepo := repository.NewTodoAttachmentRepository(gormDB)
+	tagRepo := repository.NewTagRepository(gormDB)
 	categoryRepo := repository.NewCategoryRepository(gormDB)
 	blogRepo := repository.NewBlogRepository(gormDB)
 	userRepo := repository.NewUserRepository(gormDB)
+	appPasswordRepo := repository.NewAppPasswordRepository(gormDB)
+	timeEntryRepo := repository.NewTimeEntryRepository(gormDB)
 
-	todoService := service.NewTodoService(todoRepo)
+	todoWorkflow, err := workflow.New(cfg.Todos.Workflow)
+	if err != nil {
+		return nil, fmt.Errorf("todo workflow setup failed: %w", err)
+	}
+	todoService := service.NewTodoService(todoRepo, todoWorkflow, cfg.Todos)
 	todoHandler := handlers.NewTodoHandler(todoService)
 
+	requestValidator := validator.New()
+	todoQuickAddService := service.NewTodoQuickAddService(todoService, requestValidator)
+	todoQuickAddHandler := handlers.NewTodoQuickAddHandler(todoQuickAddService)
+
+	todoCommentService := service.NewTodoCommentService(todoCommentRepo, todoRepo)
+	todoCommentHandler := handlers.NewTodoCommentHandler(todoCommentService)
+
+	todoDependencyService := service.NewTodoDependencyService(todoRepo, todoService)
+	todoDependencyHandler := handlers.NewTodoDependencyHandler(todoDependencyService)
+
+	attachmentStore, err := storage.New(cfg.Attachments)
+	if err != nil {
+		return nil, fmt.Errorf("attachment storage setup failed: %w", err)
+	}
+	todoAttachmentService := service.NewTodoAttachmentService(todoAttachmentRepo, todoRepo, todoService, attachmentStore, cfg.Attachments)
+	todoAttachmentHandler := handlers.NewTodoAttachmentHandler(todoAttachmentService)
+
+	timeEntryService := service.NewTimeEntryService(timeEntryRepo, todoRepo)
+	timeEntryHandler := handlers.NewTimeEntryHandler(timeEntryService)
+
+	todoListService := service.NewTodoListService(todoListRepo, todoRepo, todoService, userRepo)
+	todoListHandler := handlers.NewTodoListHandler(todoListService)
+
+	tagService := service.NewTagService(tagRepo)
+	tagHandler := handlers.NewTagHandler(tagService)
+
 	categoryService := service.NewCategoryService(categoryRepo)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
@@ -61,30 +102,59 @@ func New(configPath string) (*App, error) {
 	userService := service.NewUserService(userRepo, cfg.JWT.Secret)
 	userHandler := handlers.NewUserHandler(userService)
 
+	appPasswordService := service.NewAppPasswordService(appPasswordRepo)
+	appPasswordHandler := handlers.NewAppPasswordHandler(appPasswordService)
+
+	calDAVService := service.NewCalDAVService(todoRepo, todoService, userRepo, appPasswordRepo)
+	calDAVHandler := handlers.NewCalDAVHandler(calDAVService)
+
+	var reminders *reminder.Scheduler
+	if cfg.Reminders.Enabled {
+		notifier, err := reminder.New(cfg.Reminders)
+		if err != nil {
+			return nil, fmt.Errorf("reminder notifier setup failed: %w", err)
+		}
+		reminders = reminder.NewScheduler(repository.NewReminderRepository(gormDB), notifier, cfg.Reminders)
+	}
+
 	e := echo.New()
-	e.Validator = validator.New()
+	e.Validator = requestValidator
 	e.HTTPErrorHandler = middleware.ErrorHandler
 	middleware.Setup(e)
 
 	routes.RegisterRoutes(e, routes.RouteHandlers{
-		TodoHandler:     todoHandler,
-		CategoryHandler: categoryHandler,
-		BlogHandler:     blogHandler,
-		UserHandler:     userHandler,
-		JWTSecret:       cfg.JWT.Secret,
+		TodoHandler:           todoHandler,
+		TodoListHandler:       todoListHandler,
+		TodoCommentHandler:    todoCommentHandler,
+		TodoDependencyHandler: todoDependencyHandler,
+		TodoAttachmentHandler: todoAttachmentHandler,
+		TimeEntryHandler:      timeEntryHandler,
+		TodoQuickAddHandler:   todoQuickAddHandler,
+		TagHandler:            tagHandler,
+		CategoryHandler:       categoryHandler,
+		BlogHandler:           blogHandler,
+		UserHandler:           userHandler,
+		AppPasswordHandler:    appPasswordHandler,
+		CalDAVHandler:         calDAVHandler,
+		JWTSecret:             cfg.JWT.Secret,
 	})
 
 	e.Static("/", "dist")
 
 	return &App{
-		Config: cfg,
-		Echo:   e,
-		GormDB: gormDB,
+		Config:    cfg,
+		Echo:      e,
+		GormDB:    gormDB,
+		Reminders: reminders,
 	}, nil
 }
 
-// Run starts server with graceful shutdown.
+// Run starts server and reminder scheduler with graceful shutdown.
 func (a *App) Run() {
+	if a.Reminders != nil {
+		a.Reminders.Start()
+	}
+
 	go func() {
 		if err := a.Echo.Start(":" + a.Config.Server.Port); err != nil && err != http.ErrServerClosed {
 			a.Echo.Logger.Fatal("shutting down")
@@ -110,6 +180,11 @@ func (a *App) Run() {
 
 // Close releases app resources.
 func (a *App) Close() {
+	// Reminders stop first; they need the database until they are done.
+	if a.Reminders != nil {
+		a.Reminders.Stop()
+	}
+
 	if a.GormDB != nil {
 		sqlDB, err := a.GormDB.DB()
 		if err == nil {
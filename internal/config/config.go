@@ -33,12 +33,78 @@ type ORMConfig struct {
 	AutoMigrate bool `yaml:"auto_migrate"`
 }
 
+// TodoConfig holds todo business rules.
+type TodoConfig struct {
+	AutoCompleteParent bool           `yaml:"auto_complete_parent"` // complete a parent once all subtasks are done
+	Workflow           WorkflowConfig `yaml:"workflow"`
+}
+
+// WorkflowConfig lists the todo statuses in board order and the moves allowed
+// between them. Empty means todo, in_progress, blocked and done.
+type WorkflowConfig struct {
+	Statuses    []string            `yaml:"statuses"`    // must include todo and done
+	Transitions map[string][]string `yaml:"transitions"` // status -> statuses it may move to
+}
+
+// TrashConfig controls how long soft-deleted todos and blogs are kept.
+type TrashConfig struct {
+	RetentionDays int `yaml:"retention_days"` // purge items deleted longer ago than this; 0 means 30
+}
+
+// AttachmentConfig selects where uploaded files are kept and how big they may be.
+type AttachmentConfig struct {
+	Storage   string   `yaml:"storage"`     // local (default) or s3
+	LocalDir  string   `yaml:"local_dir"`   // root directory of the local storage; default data/attachments
+	MaxSizeMB int      `yaml:"max_size_mb"` // per file; 0 means 10
+	S3        S3Config `yaml:"s3"`
+}
+
+// S3Config points at an S3-compatible bucket, such as AWS S3 or MinIO.
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"` // e.g. https://s3.eu-central-1.amazonaws.com or http://minio:9000
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+// ReminderConfig controls the background job that sends todo reminders.
+type ReminderConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	Notifier        string        `yaml:"notifier"`         // log (default), smtp or webhook
+	IntervalSeconds int           `yaml:"interval_seconds"` // how often to look for due reminders; 0 means 60
+	MaxAttempts     int           `yaml:"max_attempts"`     // tries per reminder; 0 means 5
+	BackoffSeconds  int           `yaml:"backoff_seconds"`  // wait before the first retry, doubled after each; 0 means 2
+	SMTP            SMTPConfig    `yaml:"smtp"`
+	Webhook         WebhookConfig `yaml:"webhook"`
+}
+
+// SMTPConfig points at the mail server reminders are sent through. STARTTLS
+// is used when the server offers it.
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"` // 0 means 587
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"` // e.g. "Todo <todo@example.com>"
+}
+
+// WebhookConfig is where reminders are POSTed as JSON.
+type WebhookConfig struct {
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret"` // signs the body with HMAC-SHA256 when set
+}
+
 // Config represents the entire application configuration
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	ORM      ORMConfig      `yaml:"orm"`
-	JWT      JWTConfig      `yaml:"jwt"`
+	Server      ServerConfig     `yaml:"server"`
+	Database    DatabaseConfig   `yaml:"database"`
+	ORM         ORMConfig        `yaml:"orm"`
+	JWT         JWTConfig        `yaml:"jwt"`
+	Todos       TodoConfig       `yaml:"todos"`
+	Trash       TrashConfig      `yaml:"trash"`
+	Attachments AttachmentConfig `yaml:"attachments"`
+	Reminders   ReminderConfig   `yaml:"reminders"`
 }
 
 // LoadConfig reads and parses the YAML configuration file
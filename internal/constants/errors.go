@@ -4,8 +4,14 @@ const (
 	ErrInvalidID  = "Invalid ID"
 	ErrValidation = "Validation failed"
 	ErrInternal   = "Internal server error"
+	ErrTimeout    = "Request timed out"
+	ErrCanceled   = "Request canceled"
 )
 
+// StatusClientClosedRequest is the non-standard status nginx logs when the
+// client goes away before the response is ready.
+const StatusClientClosedRequest = 499
+
 const (
 	CodeNotFound  = "NOT_FOUND"
 	CodeInvalid   = "INVALID_INPUT"
@@ -7,6 +7,70 @@ const (
 	MsgTodoFetched  = "Todo fetched successfully"
 	MsgTodosFetched = "Todos fetched successfully"
 
+	MsgTodoRestored      = "Todo restored successfully"
+	MsgTodosTrashFetched = "Trashed todos fetched successfully"
+
+	MsgTodoQuickAddParsed = "Text parsed; nothing was created"
+
+	MsgTodoMoved          = "Todo moved successfully"
+	MsgTodoHistoryFetched = "Todo history fetched successfully"
+	MsgTodoStatsFetched   = "Todo stats fetched successfully"
+	MsgTodoBoardFetched   = "Todo board fetched successfully"
+	MsgTodoAssigned       = "Todo assigned successfully"
+	MsgTodoUnassigned     = "Todo unassigned successfully"
+
+	MsgCommentCreated  = "Comment created successfully"
+	MsgCommentUpdated  = "Comment updated successfully"
+	MsgCommentDeleted  = "Comment deleted successfully"
+	MsgCommentsFetched = "Comments fetched successfully"
+
+	MsgDependencyAdded     = "Dependency added successfully"
+	MsgDependencyRemoved   = "Dependency removed successfully"
+	MsgDependenciesFetched = "Dependencies fetched successfully"
+
+	MsgTimerStarted       = "Timer started successfully"
+	MsgTimerStopped       = "Timer stopped successfully"
+	MsgTimerFetched       = "Running timer fetched successfully"
+	MsgTimeEntryCreated   = "Time entry created successfully"
+	MsgTimeEntryDeleted   = "Time entry deleted successfully"
+	MsgTimeEntriesFetched = "Time entries fetched successfully"
+	MsgTimeReportFetched  = "Time report fetched successfully"
+
+	MsgAttachmentUploaded = "Attachment uploaded successfully"
+	MsgAttachmentDeleted  = "Attachment deleted successfully"
+	MsgAttachmentsFetched = "Attachments fetched successfully"
+
+	MsgTodosImported      = "Todos imported successfully"
+	MsgTodosImportChecked = "Import checked; nothing was written"
+
+	MsgTodosBulkApplied    = "Bulk operation applied successfully"
+	MsgTodosBulkRolledBack = "Bulk operation rolled back"
+
+	MsgTodoSeriesFetched = "Todo series fetched successfully"
+	MsgTodoSeriesUpdated = "Todo series updated successfully"
+	MsgTodoSeriesStopped = "Todo series stopped successfully"
+
+	MsgListCreated    = "List created successfully"
+	MsgListUpdated    = "List updated successfully"
+	MsgListDeleted    = "List deleted successfully"
+	MsgListFetched    = "List fetched successfully"
+	MsgListsFetched   = "Lists fetched successfully"
+	MsgListArchived   = "List archived successfully"
+	MsgListUnarchived = "List unarchived successfully"
+	MsgListCounted    = "List open todos counted successfully"
+	MsgTodosMoved     = "Todos moved successfully"
+
+	MsgListMemberAdded    = "List member added successfully"
+	MsgListMemberUpdated  = "List member updated successfully"
+	MsgListMemberRemoved  = "List member removed successfully"
+	MsgListMembersFetched = "List members fetched successfully"
+
+	MsgTagCreated  = "Tag created successfully"
+	MsgTagUpdated  = "Tag updated successfully"
+	MsgTagDeleted  = "Tag deleted successfully"
+	MsgTagFetched  = "Tag fetched successfully"
+	MsgTagsFetched = "Tags fetched successfully"
+
 	MsgCategoryCreated   = "Category created successfully"
 	MsgCategoryUpdated   = "Category updated successfully"
 	MsgCategoryDeleted   = "Category deleted successfully"
@@ -20,9 +84,16 @@ const (
 	MsgBlogFetched   = "Blog fetched successfully"
 	MsgBlogsFetched  = "Blogs fetched successfully"
 
+	MsgBlogRestored      = "Blog restored successfully"
+	MsgBlogsTrashFetched = "Trashed blogs fetched successfully"
+
 	MsgUserRegistered     = "User registered successfully"
 	MsgUserCreated        = "User created successfully"
 	MsgUserProfileFetched = "User profile fetched successfully"
 	MsgUsersFetched       = "Users fetched successfully"
 	MsgLoginSuccess       = "Login successful"
+
+	MsgAppPasswordCreated  = "App password created; it won't be shown again"
+	MsgAppPasswordDeleted  = "App password deleted successfully"
+	MsgAppPasswordsFetched = "App passwords fetched successfully"
 )
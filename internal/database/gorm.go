@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/manish-npx/todo-go-echo/internal/config"
 	"github.com/manish-npx/todo-go-echo/internal/models"
@@ -12,12 +13,17 @@ import (
 // NewGormConnection creates an optional GORM connection.
 // The current app still uses sql repositories for runtime CRUD paths.
 func NewGormConnection(cfg config.DatabaseConfig, ormCfg config.ORMConfig) (*gorm.DB, error) {
+	// TIMESTAMP columns hold UTC: the session time zone covers column
+	// defaults such as CURRENT_TIMESTAMP and NowFunc covers gorm's own
+	// created_at and updated_at.
 	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
 	)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to open gorm connection: %w", err)
 	}
@@ -31,7 +37,7 @@ func NewGormConnection(cfg config.DatabaseConfig, ormCfg config.ORMConfig) (*gor
 			return nil, fmt.Errorf("failed normalizing categories name constraint: %w", err)
 		}
 
-		if err := db.AutoMigrate(&models.User{}, &models.Category{}, &models.Blog{}, &models.Todo{}); err != nil {
+		if err := db.AutoMigrate(&models.User{}, &models.Category{}, &models.Blog{}, &models.Tag{}, &models.TodoList{}, &models.ListMember{}, &models.Todo{}, &models.TodoEvent{}, &models.TodoComment{}, &models.TodoAttachment{}, &models.TodoDependency{}, &models.TimeEntry{}, &models.AppPassword{}); err != nil {
 			return nil, fmt.Errorf("failed gorm automigrate: %w", err)
 		}
 	}
@@ -5,9 +5,27 @@ type APIResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
 	Data    any    `json:"data,omitempty"`
+	Meta    any    `json:"meta,omitempty"`
 	Error   any    `json:"error,omitempty"`
 }
 
+// PageMeta describes cursor pagination for list responses.
+type PageMeta struct {
+	NextCursor    string `json:"next_cursor,omitempty"`
+	HasMore       bool   `json:"has_more"`
+	TotalEstimate int64  `json:"total_estimate"`
+}
+
+// OffsetPageMeta describes a list that pages by offset or cursor and knows
+// its exact total.
+type OffsetPageMeta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
 // SuccessResponse helper
 func SuccessResponse(message string, data any) APIResponse {
 	return APIResponse{
@@ -17,6 +35,16 @@ func SuccessResponse(message string, data any) APIResponse {
 	}
 }
 
+// PaginatedResponse helper
+func PaginatedResponse(message string, data any, meta any) APIResponse {
+	return APIResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	}
+}
+
 // ErrorResponse helper
 func ErrorResponse(message string, err any) APIResponse {
 	return APIResponse{
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+)
+
+type AppPasswordHandler struct {
+	service service.AppPasswordService
+}
+
+func NewAppPasswordHandler(service service.AppPasswordService) *AppPasswordHandler {
+	return &AppPasswordHandler{service: service}
+}
+
+// GetAppPasswords handles GET /api/v1/users/me/app-passwords.
+func (h *AppPasswordHandler) GetAppPasswords(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	passwords, err := h.service.GetAll(ctx, userID)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgAppPasswordsFetched, passwords))
+}
+
+// CreateAppPassword handles POST /api/v1/users/me/app-passwords. The
+// response is the only time the password itself is returned.
+func (h *AppPasswordHandler) CreateAppPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	var req models.CreateAppPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	password, err := h.service.Create(ctx, userID, req)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgAppPasswordCreated, password))
+}
+
+// DeleteAppPassword handles DELETE /api/v1/users/me/app-passwords/:id.
+func (h *AppPasswordHandler) DeleteAppPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("App password not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgAppPasswordDeleted, nil))
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -20,21 +21,30 @@ func NewBlogHandler(service service.BlogService) *BlogHandler {
 	return &BlogHandler{service: service}
 }
 
-// GetBlogs handles GET /api/v1/blogs.
+// GetBlogs handles GET /api/v1/blogs?category=&author=&status=&from=&to=&sort=&order=&limit=&offset=&cursor=.
 func (h *BlogHandler) GetBlogs(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	categoryID := c.QueryParam("category")
-	author := c.QueryParam("author")
-	status := c.QueryParam("status")
-
-	blogs, err := h.service.GetBlogs(ctx, categoryID, author, status)
+	query, err := parseBlogListQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
 
+	page, err := h.service.GetBlogs(ctx, query)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		if errors.Is(err, service.ErrInvalidBlogQuery) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
 	}
 
-	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgBlogsFetched, blogs))
+	return c.JSON(http.StatusOK, dto.PaginatedResponse(constants.MsgBlogsFetched, page.Blogs, dto.OffsetPageMeta{
+		Total:      page.Total,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}))
 }
 
 // GetBlog handles GET /api/v1/blogs/:id.
@@ -48,7 +58,7 @@ func (h *BlogHandler) GetBlog(c echo.Context) error {
 
 	blog, err := h.service.GetByID(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 	if blog == nil {
 		return c.JSON(http.StatusNotFound, dto.ErrorResponse("Blog not found", nil))
@@ -75,7 +85,7 @@ func (h *BlogHandler) CreateBlog(c echo.Context) error {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusBadRequest, dto.ErrorResponse("Invalid category ID", nil))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgBlogCreated, blog))
@@ -104,7 +114,7 @@ func (h *BlogHandler) UpdateBlog(c echo.Context) error {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Blog not found", nil))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgBlogUpdated, blog))
@@ -123,12 +133,44 @@ func (h *BlogHandler) DeleteBlog(c echo.Context) error {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Blog not found", nil))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgBlogDeleted, nil))
 }
 
+// GetBlogTrash handles GET /api/v1/blogs/trash.
+func (h *BlogHandler) GetBlogTrash(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	blogs, err := h.service.Trash(ctx)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgBlogsTrashFetched, blogs))
+}
+
+// RestoreBlog handles POST /api/v1/blogs/:id/restore.
+func (h *BlogHandler) RestoreBlog(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	blog, err := h.service.Restore(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Blog not found in trash", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgBlogRestored, blog))
+}
+
 // SearchBlogs handles GET /api/v1/blogs/search?q=term.
 func (h *BlogHandler) SearchBlogs(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -140,7 +182,7 @@ func (h *BlogHandler) SearchBlogs(c echo.Context) error {
 
 	blogs, err := h.service.Search(ctx, query)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgBlogsFetched, blogs))
@@ -160,8 +202,47 @@ func (h *BlogHandler) PublishBlog(c echo.Context) error {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Blog not found", nil))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgBlogPublished, blog))
 }
+
+// parseBlogListQuery reads the list filters; they all apply together.
+func parseBlogListQuery(c echo.Context) (models.BlogListQuery, error) {
+	query := models.BlogListQuery{
+		Author: c.QueryParam("author"),
+		Status: c.QueryParam("status"),
+		From:   c.QueryParam("from"),
+		To:     c.QueryParam("to"),
+		Sort:   c.QueryParam("sort"),
+		Order:  c.QueryParam("order"),
+		Cursor: c.QueryParam("cursor"),
+	}
+
+	if raw := c.QueryParam("category"); raw != "" {
+		categoryID, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, errors.New("invalid category ID")
+		}
+		query.CategoryID = &categoryID
+	}
+
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, errors.New("limit must be a number")
+		}
+		query.Limit = limit
+	}
+
+	if raw := c.QueryParam("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, errors.New("offset must be a number")
+		}
+		query.Offset = offset
+	}
+
+	return query, nil
+}
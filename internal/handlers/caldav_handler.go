@@ -0,0 +1,461 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+)
+
+// CalDAVPrefix is where the CalDAV server is mounted.
+const CalDAVPrefix = "/caldav"
+
+// calDAVUserIDKey holds the user ID set by Authenticate.
+const calDAVUserIDKey = "caldav_user_id"
+
+// maxCalendarObjectSize bounds PUT bodies; a single VTODO is a few hundred bytes.
+const maxCalendarObjectSize = 1 << 20
+
+const (
+	calDAVPrincipalPath  = CalDAVPrefix + "/principal/"
+	calDAVHomePath       = CalDAVPrefix + "/calendars/"
+	calDAVCollectionPath = calDAVHomePath + "todos/"
+	calDAVContentType    = "text/calendar; charset=utf-8"
+	calDAVAllow          = "OPTIONS, PROPFIND, REPORT, GET, HEAD, PUT, DELETE"
+)
+
+// calDAVResource is what a request path points at.
+type calDAVResource int
+
+const (
+	calDAVRoot calDAVResource = iota
+	calDAVPrincipal
+	calDAVHome
+	calDAVCollection
+	calDAVObject
+)
+
+// CalDAVHandler serves the user's todos as a single VTODO calendar at
+// /caldav/calendars/todos/ for apps such as Apple Reminders, Thunderbird
+// and DAVx⁵ (RFC 4791, with sync-collection from RFC 6578).
+type CalDAVHandler struct {
+	service service.CalDAVService
+}
+
+func NewCalDAVHandler(service service.CalDAVService) *CalDAVHandler {
+	return &CalDAVHandler{service: service}
+}
+
+// Authenticate is the Basic auth validator for the CalDAV routes. It accepts
+// the account password or an app password.
+func (h *CalDAVHandler) Authenticate(username, password string, c echo.Context) (bool, error) {
+	userID, err := h.service.Authenticate(c.Request().Context(), username, password)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	c.Set(calDAVUserIDKey, userID)
+	return true, nil
+}
+
+// WellKnown handles /.well-known/caldav (RFC 6764) so clients given only a
+// host name find the server.
+func (h *CalDAVHandler) WellKnown(c echo.Context) error {
+	return c.Redirect(http.StatusMovedPermanently, CalDAVPrefix+"/")
+}
+
+// Handle dispatches every method under /caldav.
+func (h *CalDAVHandler) Handle(c echo.Context) error {
+	userID, ok := c.Get(calDAVUserIDKey).(int)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", "missing credentials"))
+	}
+
+	resource, name, ok := parseCalDAVPath(c.Request().URL.Path)
+	if !ok {
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	switch c.Request().Method {
+	case http.MethodOptions:
+		c.Response().Header().Set("DAV", "1, 3, calendar-access")
+		c.Response().Header().Set(echo.HeaderAllow, calDAVAllow)
+		return c.NoContent(http.StatusOK)
+	case echo.PROPFIND:
+		return h.propfind(c, userID, resource, name)
+	case echo.REPORT:
+		if resource != calDAVCollection {
+			return c.NoContent(http.StatusMethodNotAllowed)
+		}
+		return h.report(c, userID)
+	case http.MethodGet, http.MethodHead:
+		if resource != calDAVObject {
+			return c.NoContent(http.StatusMethodNotAllowed)
+		}
+		return h.get(c, userID, name)
+	case http.MethodPut:
+		if resource != calDAVObject {
+			return c.NoContent(http.StatusMethodNotAllowed)
+		}
+		return h.put(c, userID, name)
+	case http.MethodDelete:
+		if resource != calDAVObject {
+			return c.NoContent(http.StatusMethodNotAllowed)
+		}
+		return h.delete(c, userID, name)
+	}
+	c.Response().Header().Set(echo.HeaderAllow, calDAVAllow)
+	return c.NoContent(http.StatusMethodNotAllowed)
+}
+
+func (h *CalDAVHandler) propfind(c echo.Context, userID int, resource calDAVResource, name string) error {
+	var req davPropfind
+	if err := decodeDAVBody(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	names := req.Prop.names()
+	allProps := len(names) == 0
+
+	depth := c.Request().Header.Get("Depth")
+	withChildren := depth != "0"
+
+	render := h.propContext(c, userID)
+	var responses []davResponse
+	switch resource {
+	case calDAVObject:
+		object, err := h.service.Get(c.Request().Context(), userID, name)
+		if err != nil {
+			return h.writeError(c, err)
+		}
+		responses = append(responses, render.objectResponse(object, names, allProps))
+	case calDAVCollection:
+		res, err := render.collectionResponse(c.Request().Context(), names, allProps)
+		if err != nil {
+			return h.writeError(c, err)
+		}
+		responses = append(responses, res)
+		if withChildren {
+			objects, err := h.service.List(c.Request().Context(), userID)
+			if err != nil {
+				return h.writeError(c, err)
+			}
+			for i := range objects {
+				responses = append(responses, render.objectResponse(&objects[i], names, allProps))
+			}
+		}
+	default:
+		responses = append(responses, render.resourceResponse(resource, names, allProps))
+		if resource == calDAVHome && withChildren {
+			res, err := render.collectionResponse(c.Request().Context(), names, allProps)
+			if err != nil {
+				return h.writeError(c, err)
+			}
+			responses = append(responses, res)
+		}
+	}
+	return writeMultistatus(c, responses, "")
+}
+
+func (h *CalDAVHandler) report(c echo.Context, userID int) error {
+	var req davReport
+	if err := decodeDAVBody(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	names := req.Prop.names()
+	allProps := len(names) == 0
+	render := h.propContext(c, userID)
+
+	switch req.XMLName {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		objects, err := h.service.List(c.Request().Context(), userID)
+		if err != nil {
+			return h.writeError(c, err)
+		}
+		responses := make([]davResponse, 0, len(objects))
+		for i := range objects {
+			ok, err := matchCalendar(req.Filter, objects[i].VTodo)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+			}
+			if ok {
+				responses = append(responses, render.objectResponse(&objects[i], names, allProps))
+			}
+		}
+		return writeMultistatus(c, responses, "")
+
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		responses := make([]davResponse, 0, len(req.Hrefs))
+		for _, href := range req.Hrefs {
+			path, err := url.PathUnescape(strings.TrimSpace(href))
+			if err != nil {
+				path = href
+			}
+			resource, name, ok := parseCalDAVPath(path)
+			if !ok || resource != calDAVObject {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			object, err := h.service.Get(c.Request().Context(), userID, name)
+			if errors.Is(err, sql.ErrNoRows) {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			if err != nil {
+				return h.writeError(c, err)
+			}
+			responses = append(responses, render.objectResponse(object, names, allProps))
+		}
+		return writeMultistatus(c, responses, "")
+
+	case xml.Name{Space: nsDAV, Local: "sync-collection"}:
+		objects, next, err := h.service.Changes(c.Request().Context(), userID, strings.TrimSpace(req.SyncToken))
+		if errors.Is(err, service.ErrInvalidSyncToken) {
+			// RFC 6578 section 3.2: the client falls back to a full sync.
+			return c.Blob(http.StatusForbidden, echo.MIMEApplicationXMLCharsetUTF8,
+				[]byte(xml.Header+`<d:error xmlns:d="DAV:"><d:valid-sync-token/></d:error>`))
+		}
+		if err != nil {
+			return h.writeError(c, err)
+		}
+		responses := make([]davResponse, 0, len(objects))
+		for i := range objects {
+			if objects[i].Deleted {
+				responses = append(responses, davResponse{href: calDAVObjectHref(objects[i].Name), status: http.StatusNotFound})
+				continue
+			}
+			responses = append(responses, render.objectResponse(&objects[i], names, allProps))
+		}
+		return writeMultistatus(c, responses, next)
+	}
+
+	return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", "unsupported report "+req.XMLName.Local))
+}
+
+func (h *CalDAVHandler) get(c echo.Context, userID int, name string) error {
+	object, err := h.service.Get(c.Request().Context(), userID, name)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+	c.Response().Header().Set("ETag", object.ETag)
+	if c.Request().Method == http.MethodHead {
+		c.Response().Header().Set(echo.HeaderContentType, calDAVContentType)
+		return c.NoContent(http.StatusOK)
+	}
+	return c.Blob(http.StatusOK, calDAVContentType, object.Data)
+}
+
+// put stores a VTODO. No ETag is returned: the stored object is re-encoded
+// from the todo, so it differs from the body and clients have to fetch it
+// (RFC 4791 section 5.3.4).
+func (h *CalDAVHandler) put(c echo.Context, userID int, name string) error {
+	if contentType := c.Request().Header.Get(echo.HeaderContentType); contentType != "" &&
+		!strings.HasPrefix(strings.ToLower(contentType), "text/calendar") {
+		return c.JSON(http.StatusUnsupportedMediaType, dto.ErrorResponse(constants.ErrValidation, "body must be text/calendar"))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxCalendarObjectSize+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	if len(data) > maxCalendarObjectSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse(constants.ErrValidation, "calendar object is too large"))
+	}
+
+	_, created, err := h.service.Put(c.Request().Context(), userID, name, data, calDAVPrecondition(c))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+	if created {
+		return c.NoContent(http.StatusCreated)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) delete(c echo.Context, userID int, name string) error {
+	if err := h.service.Delete(c.Request().Context(), userID, name, calDAVPrecondition(c)); err != nil {
+		return h.writeError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) writeError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return c.NoContent(http.StatusNotFound)
+	case errors.Is(err, service.ErrPreconditionFailed):
+		return c.JSON(http.StatusPreconditionFailed, dto.ErrorResponse("Precondition failed", err.Error()))
+	case errors.Is(err, service.ErrInvalidTransition):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse("Status change not allowed", err.Error()))
+	case errors.Is(err, service.ErrBlocked):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse("Todo is blocked", err.Error()))
+	case errors.Is(err, service.ErrInvalidResourceName):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	case errors.Is(err, service.ErrInvalidCalendarData), service.IsInvalidTodoInput(err):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	return internalError(c, err)
+}
+
+// calDAVPropContext renders properties for the authenticated user.
+type calDAVPropContext struct {
+	handler *CalDAVHandler
+	userID  int
+	email   string
+}
+
+func (h *CalDAVHandler) propContext(c echo.Context, userID int) *calDAVPropContext {
+	email, _, _ := c.Request().BasicAuth()
+	return &calDAVPropContext{handler: h, userID: userID, email: email}
+}
+
+// resourceResponse covers the root, the principal and the calendar home.
+func (p *calDAVPropContext) resourceResponse(resource calDAVResource, names []xml.Name, allProps bool) davResponse {
+	href := CalDAVPrefix + "/"
+	props := map[xml.Name]string{
+		propCurrentUserPrincipal: davHref(calDAVPrincipalPath),
+		propResourceType:         "<d:collection/>",
+	}
+	switch resource {
+	case calDAVPrincipal:
+		href = calDAVPrincipalPath
+		props[propResourceType] = "<d:principal/>"
+		props[propDisplayName] = escapeXML(p.email)
+		props[propPrincipalURL] = davHref(calDAVPrincipalPath)
+		props[propCalendarHomeSet] = davHref(calDAVHomePath)
+		props[propCalendarUserAddress] = davHref("mailto:" + p.email)
+	case calDAVHome:
+		href = calDAVHomePath
+		props[propOwner] = davHref(calDAVPrincipalPath)
+	}
+	return selectProps(href, props, names, allProps)
+}
+
+func (p *calDAVPropContext) collectionResponse(ctx context.Context, names []xml.Name, allProps bool) (davResponse, error) {
+	token, err := p.handler.service.SyncToken(ctx, p.userID)
+	if err != nil {
+		return davResponse{}, err
+	}
+	props := map[xml.Name]string{
+		propResourceType:         "<d:collection/><c:calendar/>",
+		propDisplayName:          "Todos",
+		propCurrentUserPrincipal: davHref(calDAVPrincipalPath),
+		propOwner:                davHref(calDAVPrincipalPath),
+		propPrivilegeSet:         calDAVPrivileges,
+		propSupportedComponents:  `<c:comp name="VTODO"/>`,
+		propSupportedReportSet: "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><d:sync-collection/></d:report></d:supported-report>",
+		propGetCTag:   escapeXML(token),
+		propSyncToken: escapeXML(token),
+	}
+	return selectProps(calDAVCollectionPath, props, names, allProps), nil
+}
+
+func (p *calDAVPropContext) objectResponse(object *service.CalDAVObject, names []xml.Name, allProps bool) davResponse {
+	props := map[xml.Name]string{
+		propResourceType:   "",
+		propGetETag:        escapeXML(object.ETag),
+		propGetContentType: calDAVContentType + "; component=VTODO",
+		propPrivilegeSet:   calDAVPrivileges,
+	}
+	// allprop leaves out calendar-data, as RFC 4791 section 9.6 asks.
+	if !allProps {
+		props[propCalendarData] = escapeXML(string(object.Data))
+	}
+	return selectProps(calDAVObjectHref(object.Name), props, names, allProps)
+}
+
+const calDAVPrivileges = "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+	"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege>" +
+	"<d:privilege><d:unbind/></d:privilege>"
+
+// selectProps splits the requested properties into found and missing; with
+// allProps every known property is returned.
+func selectProps(href string, props map[xml.Name]string, names []xml.Name, allProps bool) davResponse {
+	res := davResponse{href: href}
+	if allProps {
+		for _, name := range calDAVPropOrder {
+			if inner, ok := props[name]; ok {
+				res.found = append(res.found, davPropValue{name: name, inner: inner})
+			}
+		}
+		return res
+	}
+	for _, name := range names {
+		if inner, ok := props[name]; ok {
+			res.found = append(res.found, davPropValue{name: name, inner: inner})
+		} else {
+			res.missing = append(res.missing, name)
+		}
+	}
+	return res
+}
+
+// calDAVPropOrder fixes the order of allprop responses.
+var calDAVPropOrder = []xml.Name{
+	propResourceType, propDisplayName, propCurrentUserPrincipal, propPrincipalURL, propOwner,
+	propCalendarHomeSet, propCalendarUserAddress, propSupportedComponents, propSupportedReportSet,
+	propPrivilegeSet, propGetCTag, propSyncToken, propGetETag, propGetContentType, propCalendarData,
+}
+
+// parseCalDAVPath maps a request path to a resource and, for calendar
+// objects, the object's name.
+func parseCalDAVPath(path string) (calDAVResource, string, bool) {
+	rest, ok := strings.CutPrefix(path, CalDAVPrefix)
+	if !ok {
+		return 0, "", false
+	}
+	switch strings.TrimSuffix(rest, "/") {
+	case "":
+		return calDAVRoot, "", true
+	case "/principal":
+		return calDAVPrincipal, "", true
+	case "/calendars":
+		return calDAVHome, "", true
+	case "/calendars/todos":
+		return calDAVCollection, "", true
+	}
+	name, ok := strings.CutPrefix(rest, "/calendars/todos/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return 0, "", false
+	}
+	return calDAVObject, name, true
+}
+
+func calDAVObjectHref(name string) string {
+	return calDAVCollectionPath + url.PathEscape(name)
+}
+
+func calDAVPrecondition(c echo.Context) service.CalDAVPrecondition {
+	return service.CalDAVPrecondition{
+		IfMatch:     strings.TrimPrefix(c.Request().Header.Get("If-Match"), "W/"),
+		IfNoneMatch: c.Request().Header.Get("If-None-Match"),
+	}
+}
+
+// decodeDAVBody reads an XML request body; an empty body leaves v unchanged.
+func decodeDAVBody(c echo.Context, v any) error {
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, maxCalendarObjectSize))
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil
+	}
+	return xml.Unmarshal(data, v)
+}
+
+func writeMultistatus(c echo.Context, responses []davResponse, syncToken string) error {
+	return c.Blob(http.StatusMultiStatus, echo.MIMEApplicationXMLCharsetUTF8, multistatus(responses, syncToken))
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/ical"
+)
+
+// XML namespaces of WebDAV, CalDAV and the calendarserver extensions.
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+var davPrefixes = map[string]string{nsDAV: "d", nsCalDAV: "c", nsCS: "cs"}
+
+var (
+	propResourceType         = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName          = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUserPrincipal = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL         = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propOwner                = xml.Name{Space: nsDAV, Local: "owner"}
+	propPrivilegeSet         = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propSupportedReportSet   = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propSyncToken            = xml.Name{Space: nsDAV, Local: "sync-token"}
+	propGetETag              = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType       = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propCalendarHomeSet      = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propCalendarUserAddress  = xml.Name{Space: nsCalDAV, Local: "calendar-user-address-set"}
+	propSupportedComponents  = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData         = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propGetCTag              = xml.Name{Space: nsCS, Local: "getctag"}
+)
+
+// davPropfind is a PROPFIND body; an empty body means allprop.
+type davPropfind struct {
+	XMLName  xml.Name  `xml:"DAV: propfind"`
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     davProp   `xml:"DAV: prop"`
+}
+
+type davProp struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+func (p davProp) names() []xml.Name {
+	names := make([]xml.Name, len(p.Names))
+	for i, name := range p.Names {
+		names[i] = name.XMLName
+	}
+	return names
+}
+
+// davReport holds the parts of the three supported REPORT bodies; XMLName
+// tells which one was sent.
+type davReport struct {
+	XMLName   xml.Name
+	Prop      davProp        `xml:"DAV: prop"`
+	Filter    *calCompFilter `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+	Hrefs     []string       `xml:"DAV: href"`
+	SyncToken string         `xml:"DAV: sync-token"`
+}
+
+type calCompFilter struct {
+	Name         string          `xml:"name,attr"`
+	IsNotDefined *struct{}       `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TimeRange    *calTimeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	PropFilters  []calPropFilter `xml:"urn:ietf:params:xml:ns:caldav prop-filter"`
+	CompFilters  []calCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type calTimeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+type calPropFilter struct {
+	Name         string        `xml:"name,attr"`
+	IsNotDefined *struct{}     `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TextMatch    *calTextMatch `xml:"urn:ietf:params:xml:ns:caldav text-match"`
+}
+
+type calTextMatch struct {
+	Value           string `xml:",chardata"`
+	NegateCondition string `xml:"negate-condition,attr"`
+}
+
+// matchCalendar evaluates a calendar-query filter against a VTODO. The
+// filter's root is the VCALENDAR; the only component inside is the VTODO.
+func matchCalendar(filter *calCompFilter, vtodo *ical.Todo) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+	if !strings.EqualFold(filter.Name, "VCALENDAR") {
+		return false, fmt.Errorf("filter must start with the VCALENDAR component")
+	}
+	if filter.IsNotDefined != nil {
+		return false, nil
+	}
+	for _, comp := range filter.CompFilters {
+		ok, err := matchTodoComponent(comp, vtodo)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchTodoComponent(filter calCompFilter, vtodo *ical.Todo) (bool, error) {
+	if !strings.EqualFold(filter.Name, "VTODO") {
+		// There are no other components, so only is-not-defined holds.
+		return filter.IsNotDefined != nil, nil
+	}
+	if filter.IsNotDefined != nil {
+		return false, nil
+	}
+	if filter.TimeRange != nil {
+		ok, err := matchTimeRange(filter.TimeRange, vtodo)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	for _, prop := range filter.PropFilters {
+		if !matchProperty(prop, vtodo) {
+			return false, nil
+		}
+	}
+	for _, comp := range filter.CompFilters {
+		// VALARMs and the like aren't stored.
+		if comp.IsNotDefined == nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchTimeRange compares the range with DUE, the only time a todo has. As
+// in RFC 4791 section 9.9, a todo without one overlaps every range.
+func matchTimeRange(timeRange *calTimeRange, vtodo *ical.Todo) (bool, error) {
+	start, err := parseCalDAVTime(timeRange.Start)
+	if err != nil {
+		return false, err
+	}
+	end, err := parseCalDAVTime(timeRange.End)
+	if err != nil {
+		return false, err
+	}
+	if vtodo.Due == nil {
+		return true, nil
+	}
+	due := *vtodo.Due
+	return (start.IsZero() || due.After(start)) && (end.IsZero() || !due.After(end)), nil
+}
+
+func parseCalDAVTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("20060102T150405Z", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time-range must use UTC date-times such as 20260101T000000Z")
+	}
+	return t, nil
+}
+
+// matchProperty supports is-not-defined and text-match with the default
+// i;ascii-casemap collation.
+func matchProperty(filter calPropFilter, vtodo *ical.Todo) bool {
+	value, ok := vtodo.Property(filter.Name)
+	switch {
+	case filter.IsNotDefined != nil:
+		return !ok
+	case !ok:
+		return false
+	case filter.TextMatch != nil:
+		found := strings.Contains(strings.ToLower(value), strings.ToLower(filter.TextMatch.Value))
+		return found != (filter.TextMatch.NegateCondition == "yes")
+	}
+	return true
+}
+
+// davResponse is one <response> of a multistatus: either a status for the
+// whole resource or the found and missing properties.
+type davResponse struct {
+	href    string
+	status  int
+	found   []davPropValue
+	missing []xml.Name
+}
+
+// davPropValue holds a property with its value as ready-made inner XML.
+type davPropValue struct {
+	name  xml.Name
+	inner string
+}
+
+// multistatus renders a 207 body. Namespaces outside davPrefixes are
+// declared on the element that uses them.
+func multistatus(responses []davResponse, syncToken string) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<d:multistatus xmlns:d=%q xmlns:c=%q xmlns:cs=%q>`, nsDAV, nsCalDAV, nsCS)
+	for _, res := range responses {
+		b.WriteString("<d:response><d:href>")
+		xml.EscapeText(&b, []byte(res.href))
+		b.WriteString("</d:href>")
+		if res.status != 0 {
+			writeStatus(&b, res.status)
+		}
+		if len(res.found) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, prop := range res.found {
+				writeElement(&b, prop.name, prop.inner)
+			}
+			b.WriteString("</d:prop>")
+			writeStatus(&b, http.StatusOK)
+			b.WriteString("</d:propstat>")
+		}
+		if len(res.missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range res.missing {
+				writeElement(&b, name, "")
+			}
+			b.WriteString("</d:prop>")
+			writeStatus(&b, http.StatusNotFound)
+			b.WriteString("</d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	if syncToken != "" {
+		b.WriteString("<d:sync-token>")
+		xml.EscapeText(&b, []byte(syncToken))
+		b.WriteString("</d:sync-token>")
+	}
+	b.WriteString("</d:multistatus>")
+	return b.Bytes()
+}
+
+func writeElement(b *bytes.Buffer, name xml.Name, inner string) {
+	tag, declaration := name.Local, ""
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		declaration = fmt.Sprintf(` xmlns:x=%q`, name.Space)
+	}
+	if inner == "" {
+		fmt.Fprintf(b, "<%s%s/>", tag, declaration)
+		return
+	}
+	fmt.Fprintf(b, "<%s%s>%s</%s>", tag, declaration, inner, tag)
+}
+
+func writeStatus(b *bytes.Buffer, status int) {
+	fmt.Fprintf(b, "<d:status>HTTP/1.1 %d %s</d:status>", status, http.StatusText(status))
+}
+
+func davHref(href string) string {
+	return "<d:href>" + escapeXML(href) + "</d:href>"
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
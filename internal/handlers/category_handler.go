@@ -26,8 +26,7 @@ func (h *CategoryHandler) GetCategories(c echo.Context) error {
 
 	categories, err := h.service.GetAll(ctx)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError,
-			dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK,
@@ -53,8 +52,7 @@ func (h *CategoryHandler) CreateCategory(c echo.Context) error {
 
 	category, err := h.service.Create(ctx, req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError,
-			dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated,
@@ -73,8 +71,7 @@ func (h *CategoryHandler) GetCategory(c echo.Context) error {
 
 	category, err := h.service.GetByID(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError,
-			dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 	if category == nil {
 		return c.JSON(http.StatusNotFound,
@@ -107,8 +104,7 @@ func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
 			return c.JSON(http.StatusNotFound,
 				dto.ErrorResponse("Category not found", nil))
 		}
-		return c.JSON(http.StatusInternalServerError,
-			dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK,
@@ -130,8 +126,7 @@ func (h *CategoryHandler) DeleteCategory(c echo.Context) error {
 			return c.JSON(http.StatusNotFound,
 				dto.ErrorResponse("Category not found", nil))
 		}
-		return c.JSON(http.StatusInternalServerError,
-			dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK,
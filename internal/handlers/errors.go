@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+)
+
+// internalError answers an unexpected service error. A query cut short
+// because the request's context ended is not a server fault: a passed
+// deadline is 503 and a disconnected client 499.
+func internalError(c echo.Context, err error) error {
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+		// Drivers don't always wrap the context's error; ask the context.
+		if ctxErr := c.Request().Context().Err(); ctxErr != nil {
+			err = errors.Join(err, ctxErr)
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse(constants.ErrTimeout, err.Error()))
+	case errors.Is(err, context.Canceled):
+		return c.JSON(constants.StatusClientClosedRequest, dto.ErrorResponse(constants.ErrCanceled, err.Error()))
+	}
+	return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+)
+
+func TestInternalError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "plain error",
+			ctx:         context.Background(),
+			err:         errors.New("connection refused"),
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: constants.ErrInternal,
+		},
+		{
+			name:        "deadline exceeded",
+			ctx:         context.Background(),
+			err:         fmt.Errorf("query: %w", context.DeadlineExceeded),
+			wantStatus:  http.StatusServiceUnavailable,
+			wantMessage: constants.ErrTimeout,
+		},
+		{
+			name:        "client went away",
+			ctx:         context.Background(),
+			err:         context.Canceled,
+			wantStatus:  constants.StatusClientClosedRequest,
+			wantMessage: constants.ErrCanceled,
+		},
+		{
+			name:        "driver error after the client went away",
+			ctx:         canceled,
+			err:         errors.New("driver: bad connection"),
+			wantStatus:  constants.StatusClientClosedRequest,
+			wantMessage: constants.ErrCanceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			if err := internalError(c, tt.err); err != nil {
+				t.Fatalf("internalError() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("internalError() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var body dto.APIResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Success || body.Message != tt.wantMessage {
+				t.Fatalf("internalError() body = %+v, want message %q", body, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+)
+
+type TagHandler struct {
+	service service.TagService
+}
+
+func NewTagHandler(service service.TagService) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+// GetTags handles GET /api/v1/tags.
+func (h *TagHandler) GetTags(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	tags, err := h.service.GetAll(ctx, userID)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTagsFetched, tags))
+}
+
+// GetTag handles GET /api/v1/tags/:id.
+func (h *TagHandler) GetTag(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	tag, err := h.service.GetByID(ctx, userID, id)
+	if err != nil {
+		return internalError(c, err)
+	}
+	if tag == nil {
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse("Tag not found", nil))
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTagFetched, tag))
+}
+
+// CreateTag handles POST /api/v1/tags.
+func (h *TagHandler) CreateTag(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	var req models.CreateTagRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	// Trim before validating so a name of only spaces fails min=1.
+	req.Name = strings.TrimSpace(req.Name)
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	tag, err := h.service.Create(ctx, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrTagAlreadyExists) {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse("Tag already exists", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgTagCreated, tag))
+}
+
+// UpdateTag handles PUT /api/v1/tags/:id.
+func (h *TagHandler) UpdateTag(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.UpdateTagRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	tag, err := h.service.Update(ctx, userID, id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Tag not found", nil))
+		}
+		if errors.Is(err, service.ErrTagAlreadyExists) {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse("Tag already exists", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTagUpdated, tag))
+}
+
+// DeleteTag handles DELETE /api/v1/tags/:id.
+func (h *TagHandler) DeleteTag(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.Delete(ctx, userID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Tag not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTagDeleted, nil))
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+)
+
+// timeReportCSVHeader is the CSV layout of the report: one row per todo,
+// tag and day, told apart by group. key is the todo or tag ID, or the day.
+var timeReportCSVHeader = []string{"group", "key", "name", "seconds", "hours"}
+
+type TimeEntryHandler struct {
+	service service.TimeEntryService
+}
+
+func NewTimeEntryHandler(service service.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{service: service}
+}
+
+// GetTimeEntries handles GET /api/v1/todos/:id/time-entries.
+func (h *TimeEntryHandler) GetTimeEntries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	entries, err := h.service.List(ctx, userID, todoID)
+	if err != nil {
+		return timeEntryError(c, err, "Todo not found")
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTimeEntriesFetched, entries))
+}
+
+// CreateTimeEntry handles POST /api/v1/todos/:id/time-entries with
+// started_at and ended_at (RFC 3339), for time tracked without the timer.
+func (h *TimeEntryHandler) CreateTimeEntry(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.CreateTimeEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	entry, err := h.service.Create(ctx, userID, todoID, req)
+	if err != nil {
+		return timeEntryError(c, err, "Todo not found")
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgTimeEntryCreated, entry))
+}
+
+// DeleteTimeEntry handles DELETE /api/v1/todos/:id/time-entries/:entry_id.
+func (h *TimeEntryHandler) DeleteTimeEntry(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("entry_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.Delete(ctx, userID, todoID, id); err != nil {
+		return timeEntryError(c, err, "Time entry not found")
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTimeEntryDeleted, nil))
+}
+
+// StartTimer handles POST /api/v1/todos/:id/timer/start.
+func (h *TimeEntryHandler) StartTimer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.StartTimerRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	entry, err := h.service.Start(ctx, userID, todoID, req)
+	if err != nil {
+		return timeEntryError(c, err, "Todo not found")
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgTimerStarted, entry))
+}
+
+// StopTimer handles POST /api/v1/todos/:id/timer/stop.
+func (h *TimeEntryHandler) StopTimer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	entry, err := h.service.Stop(ctx, userID, todoID)
+	if err != nil {
+		return timeEntryError(c, err, "Todo not found")
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTimerStopped, entry))
+}
+
+// GetRunningTimer handles GET /api/v1/time-entries/running; data is null
+// when no timer runs.
+func (h *TimeEntryHandler) GetRunningTimer(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	entry, err := h.service.Running(ctx, userID)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTimerFetched, entry))
+}
+
+// GetTimeReport handles GET /api/v1/time-entries/report?from=&to=&tz=&format=json|csv.
+func (h *TimeEntryHandler) GetTimeReport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "csv" {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, "format must be json or csv"))
+	}
+
+	report, err := h.service.Report(ctx, userID, models.TimeReportQuery{
+		Timezone: c.QueryParam("tz"),
+		From:     c.QueryParam("from"),
+		To:       c.QueryParam("to"),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimeEntry) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	if format == "csv" {
+		return writeTimeReportCSV(c, report)
+	}
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTimeReportFetched, report))
+}
+
+func writeTimeReportCSV(c echo.Context, report *models.TimeReport) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="time-report-%s-%s.csv"`, report.From, report.To))
+	res.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(res)
+	row := func(group, key, name string, seconds int64) {
+		_ = w.Write([]string{group, key, escapeCSVFormula(name), strconv.FormatInt(seconds, 10), strconv.FormatFloat(float64(seconds)/3600, 'f', 2, 64)})
+	}
+	_ = w.Write(timeReportCSVHeader)
+	row("total", "", report.From+" to "+report.To, report.TotalSeconds)
+	for _, todo := range report.ByTodo {
+		row("todo", strconv.Itoa(todo.TodoID), todo.Title, todo.Seconds)
+	}
+	for _, tag := range report.ByTag {
+		row("tag", strconv.Itoa(tag.TagID), tag.Name, tag.Seconds)
+	}
+	for _, day := range report.ByDay {
+		row("day", day.Day, "", day.Seconds)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// timeEntryError maps time tracking errors to responses; notFound names
+// what sql.ErrNoRows stands for.
+func timeEntryError(c echo.Context, err error, notFound string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse(notFound, nil))
+	case errors.Is(err, service.ErrForbidden):
+		return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+	case errors.Is(err, service.ErrTimerRunning):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse("Timer already running", err.Error()))
+	case errors.Is(err, service.ErrNoRunningTimer):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse("No running timer", err.Error()))
+	case errors.Is(err, service.ErrInvalidTimeEntry):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	return internalError(c, err)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/logger"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+	"go.uber.org/zap"
+)
+
+// multipartOverhead allows for the form encoding around an uploaded file.
+const multipartOverhead = 1 << 20
+
+type TodoAttachmentHandler struct {
+	service service.TodoAttachmentService
+}
+
+func NewTodoAttachmentHandler(service service.TodoAttachmentService) *TodoAttachmentHandler {
+	return &TodoAttachmentHandler{service: service}
+}
+
+// GetAttachments handles GET /api/v1/todos/:id/attachments.
+func (h *TodoAttachmentHandler) GetAttachments(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	attachments, err := h.service.List(ctx, userID, todoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgAttachmentsFetched, attachments))
+}
+
+// UploadAttachment handles POST /api/v1/todos/:id/attachments as
+// multipart/form-data with a "file" part.
+func (h *TodoAttachmentHandler) UploadAttachment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	// Stop reading oversized bodies instead of spooling them to disk first.
+	tooLarge := fmt.Sprintf("file must be at most %d bytes", h.service.MaxSize())
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, h.service.MaxSize()+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse(constants.ErrValidation, tooLarge))
+		}
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, "file is required"))
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(ctx, userID, todoID, header.Filename, header.Size, file)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if errors.Is(err, service.ErrAttachmentTooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse(constants.ErrValidation, tooLarge))
+		}
+		if errors.Is(err, service.ErrUnsupportedFileType) {
+			return c.JSON(http.StatusUnsupportedMediaType, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgAttachmentUploaded, attachment))
+}
+
+// DownloadAttachment handles GET /api/v1/todos/:id/attachments/:attachment_id,
+// streaming the file with the content type detected on upload.
+func (h *TodoAttachmentHandler) DownloadAttachment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	attachment, content, err := h.service.Open(ctx, userID, todoID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Attachment not found", nil))
+		}
+		return internalError(c, err)
+	}
+	defer content.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, attachment.ContentType)
+	res.Header().Set(echo.HeaderContentLength, strconv.FormatInt(attachment.Size, 10))
+	res.Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	res.Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+	res.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(res, content); err != nil {
+		// Headers are out; the short body tells the client.
+		logger.L().Error("attachment_download_failed", zap.Int("attachment_id", id), zap.Error(err))
+	}
+	return nil
+}
+
+// DeleteAttachment handles DELETE /api/v1/todos/:id/attachments/:attachment_id.
+func (h *TodoAttachmentHandler) DeleteAttachment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.Delete(ctx, userID, todoID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Attachment not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgAttachmentDeleted, nil))
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+)
+
+type TodoCommentHandler struct {
+	service service.TodoCommentService
+}
+
+func NewTodoCommentHandler(service service.TodoCommentService) *TodoCommentHandler {
+	return &TodoCommentHandler{service: service}
+}
+
+// GetComments handles GET /api/v1/todos/:id/comments.
+func (h *TodoCommentHandler) GetComments(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	comments, err := h.service.List(ctx, userID, todoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgCommentsFetched, comments))
+}
+
+// CreateComment handles POST /api/v1/todos/:id/comments.
+func (h *TodoCommentHandler) CreateComment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.CreateTodoCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	comment, err := h.service.Create(ctx, userID, todoID, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgCommentCreated, comment))
+}
+
+// UpdateComment handles PUT /api/v1/todos/:id/comments/:comment_id.
+func (h *TodoCommentHandler) UpdateComment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("comment_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.UpdateTodoCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	comment, err := h.service.Update(ctx, userID, todoID, id, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Comment not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgCommentUpdated, comment))
+}
+
+// DeleteComment handles DELETE /api/v1/todos/:id/comments/:comment_id.
+func (h *TodoCommentHandler) DeleteComment(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("comment_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.Delete(ctx, userID, todoID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Comment not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgCommentDeleted, nil))
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+)
+
+type TodoDependencyHandler struct {
+	service service.TodoDependencyService
+}
+
+func NewTodoDependencyHandler(service service.TodoDependencyService) *TodoDependencyHandler {
+	return &TodoDependencyHandler{service: service}
+}
+
+// GetDependencies handles GET /api/v1/todos/:id/dependencies.
+func (h *TodoDependencyHandler) GetDependencies(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	dependencies, err := h.service.List(ctx, userID, todoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgDependenciesFetched, dependencies))
+}
+
+// AddDependency handles POST /api/v1/todos/:id/dependencies.
+func (h *TodoDependencyHandler) AddDependency(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.AddTodoDependencyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	dependencies, err := h.service.Add(ctx, userID, todoID, req)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if errors.Is(err, service.ErrDependencyCycle) {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse("Dependency cycle", err.Error()))
+		}
+		if errors.Is(err, service.ErrInvalidDependency) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgDependencyAdded, dependencies))
+}
+
+// RemoveDependency handles DELETE /api/v1/todos/:id/dependencies/:blocked_by_id.
+func (h *TodoDependencyHandler) RemoveDependency(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	blockerID, err := strconv.Atoi(c.Param("blocked_by_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.Remove(ctx, userID, todoID, blockerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Dependency not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgDependencyRemoved, nil))
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/manish-npx/todo-go-echo/internal/constants"
@@ -22,6 +26,13 @@ func NewTodoHandler(s service.TodoService) *TodoHandler {
 
 // CreateTodo handles POST /api/v1/todos.
 func (h *TodoHandler) CreateTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
 	var req models.CreateTodoRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
@@ -31,33 +42,69 @@ func (h *TodoHandler) CreateTodo(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
 	}
 
-	todo, err := h.service.Create(req)
+	todo, err := h.service.Create(ctx, userID, req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if errors.Is(err, service.ErrListArchived) {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse("List is archived", nil))
+		}
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgTodoCreated, todo))
 }
 
-// GetTodos handles GET /api/v1/todos.
+// GetTodos handles GET /api/v1/todos?list_id=&assigned_to=&completed=&status=&ready=&priority=&tag=&q=&sort=&order=&limit=&cursor=.
 func (h *TodoHandler) GetTodos(c echo.Context) error {
-	todos, err := h.service.GetAll()
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
 	}
-	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodosFetched, todos))
+
+	query, err := parseTodoListQuery(c, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	page, err := h.service.List(ctx, userID, query)
+	if err != nil {
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.PaginatedResponse(constants.MsgTodosFetched, page.Todos, dto.PageMeta{
+		NextCursor:    page.NextCursor,
+		HasMore:       page.HasMore,
+		TotalEstimate: page.TotalEstimate,
+	}))
 }
 
 // GetTodo handles GET /api/v1/todos/:id.
 func (h *TodoHandler) GetTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
 	}
 
-	todo, err := h.service.GetByID(id)
+	todo, err := h.service.GetByID(ctx, userID, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 	if todo == nil {
 		return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
@@ -68,6 +115,13 @@ func (h *TodoHandler) GetTodo(c echo.Context) error {
 
 // UpdateTodo handles PUT /api/v1/todos/:id.
 func (h *TodoHandler) UpdateTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
@@ -82,30 +136,546 @@ func (h *TodoHandler) UpdateTodo(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
 	}
 
-	todo, err := h.service.Update(id, req)
+	todo, err := h.service.Update(ctx, userID, id, req)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if errors.Is(err, service.ErrListArchived) {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse("List is archived", nil))
+		}
+		if errors.Is(err, service.ErrInvalidTransition) {
+			return c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse("Status change not allowed", err.Error()))
+		}
+		if errors.Is(err, service.ErrBlocked) {
+			return c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse("Todo is blocked", err.Error()))
+		}
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoUpdated, todo))
 }
 
-// DeleteTodo handles DELETE /api/v1/todos/:id.
+// DeleteTodo handles DELETE /api/v1/todos/:id?subtasks=cascade|promote.
 func (h *TodoHandler) DeleteTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	mode := models.SubtaskDeleteMode(c.QueryParam("subtasks"))
+	if err := h.service.Delete(ctx, userID, id, mode); err != nil {
 		if err == sql.ErrNoRows {
 			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if errors.Is(err, service.ErrHasSubtasks) {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse("Todo has subtasks", "pass subtasks=cascade or subtasks=promote"))
+		}
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoDeleted, nil))
 }
+
+// GetTodoSeries handles GET /api/v1/todos/:id/series.
+func (h *TodoHandler) GetTodoSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	series, err := h.service.GetSeries(ctx, userID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoSeriesFetched, series))
+}
+
+// GetTodoHistory handles GET /api/v1/todos/:id/history.
+func (h *TodoHandler) GetTodoHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	events, err := h.service.History(ctx, userID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoHistoryFetched, events))
+}
+
+// UpdateTodoSeries handles PUT /api/v1/todos/:id/series.
+func (h *TodoHandler) UpdateTodoSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.UpdateTodoSeriesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	series, err := h.service.UpdateSeries(ctx, userID, id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoSeriesUpdated, series))
+}
+
+// StopTodoSeries handles DELETE /api/v1/todos/:id/series.
+func (h *TodoHandler) StopTodoSeries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.StopSeries(ctx, userID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoSeriesStopped, nil))
+}
+
+// GetTrashedTodos handles GET /api/v1/todos/trash.
+func (h *TodoHandler) GetTrashedTodos(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	todos, err := h.service.Trash(ctx, userID)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodosTrashFetched, todos))
+}
+
+// RestoreTodo handles POST /api/v1/todos/:id/restore.
+func (h *TodoHandler) RestoreTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	todo, err := h.service.Restore(ctx, userID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found in trash", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoRestored, todo))
+}
+
+// MoveTodo handles POST /api/v1/todos/:id/move with after_id and/or before_id.
+func (h *TodoHandler) MoveTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.MoveTodoRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	todo, err := h.service.Move(ctx, userID, id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoMoved, todo))
+}
+
+// AssignTodo handles PUT /api/v1/todos/:id/assignee with the user_id to
+// assign. The caller needs edit rights; the assignee has to see the todo.
+func (h *TodoHandler) AssignTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.AssignTodoRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	todo, err := h.service.Assign(ctx, userID, id, req)
+	if err != nil {
+		return assigneeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoAssigned, todo))
+}
+
+// UnassignTodo handles DELETE /api/v1/todos/:id/assignee.
+func (h *TodoHandler) UnassignTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	todo, err := h.service.Unassign(ctx, userID, id)
+	if err != nil {
+		return assigneeError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoUnassigned, todo))
+}
+
+// assigneeError maps assign and unassign errors to responses.
+func assigneeError(c echo.Context, err error) error {
+	switch {
+	case err == sql.ErrNoRows:
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse("Todo not found", nil))
+	case errors.Is(err, service.ErrForbidden):
+		return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+	case service.IsInvalidTodoInput(err):
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	return internalError(c, err)
+}
+
+// GetOverdueTodos handles GET /api/v1/todos/overdue?tz=.
+func (h *TodoHandler) GetOverdueTodos(c echo.Context) error {
+	return h.listDue(c, func(ctx context.Context, userID int, loc *time.Location) ([]models.Todo, error) {
+		return h.service.Overdue(ctx, userID, loc)
+	})
+}
+
+// GetTodosDueToday handles GET /api/v1/todos/due-today?tz=.
+func (h *TodoHandler) GetTodosDueToday(c echo.Context) error {
+	return h.listDue(c, func(ctx context.Context, userID int, loc *time.Location) ([]models.Todo, error) {
+		return h.service.DueToday(ctx, userID, loc)
+	})
+}
+
+// GetUpcomingTodos handles GET /api/v1/todos/upcoming?days=&tz=.
+func (h *TodoHandler) GetUpcomingTodos(c echo.Context) error {
+	days := 7
+	if raw := c.QueryParam("days"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, "days must be a number"))
+		}
+		days = value
+	}
+
+	return h.listDue(c, func(ctx context.Context, userID int, loc *time.Location) ([]models.Todo, error) {
+		return h.service.DueWithin(ctx, userID, days, loc)
+	})
+}
+
+// GetTodoStats handles GET /api/v1/todos/stats?tz=&from=&to=&interval=day|week.
+func (h *TodoHandler) GetTodoStats(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	stats, err := h.service.Stats(ctx, userID, models.TodoStatsQuery{
+		Timezone: c.QueryParam("tz"),
+		From:     c.QueryParam("from"),
+		To:       c.QueryParam("to"),
+		Interval: c.QueryParam("interval"),
+	})
+	if err != nil {
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoStatsFetched, stats))
+}
+
+// GetTodoBoard handles GET /api/v1/todos/board?list_id=&limit=, the todos
+// grouped by status with up to limit per column.
+func (h *TodoHandler) GetTodoBoard(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	list, err := parseTodoListQuery(c, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	board, err := h.service.Board(ctx, userID, models.TodoBoardQuery{ListID: list.ListID, Limit: list.Limit})
+	if err != nil {
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoBoardFetched, board))
+}
+
+func (h *TodoHandler) listDue(c echo.Context, fetch func(ctx context.Context, userID int, loc *time.Location) ([]models.Todo, error)) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	loc, err := requestLocation(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	todos, err := fetch(ctx, userID, loc)
+	if err != nil {
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodosFetched, todos))
+}
+
+// BulkTodos handles POST /api/v1/todos/bulk. A rolled-back atomic batch
+// answers 422 with the per-item results so the client can see what failed.
+func (h *TodoHandler) BulkTodos(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	var req models.BulkTodoRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	result, err := h.service.Bulk(ctx, userID, req)
+	if err != nil {
+		if service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+	if !result.Committed {
+		return c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse(constants.MsgTodosBulkRolledBack, result))
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodosBulkApplied, result))
+}
+
+// requestLocation reads the caller's IANA timezone from ?tz=, defaulting to UTC.
+func requestLocation(c echo.Context) (*time.Location, error) {
+	name := c.QueryParam("tz")
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+// parseTodoListQuery reads the list filters; assigned_to=me stands for userID.
+func parseTodoListQuery(c echo.Context, userID int) (models.TodoListQuery, error) {
+	query := models.TodoListQuery{
+		Status:   c.QueryParam("status"),
+		Priority: c.QueryParam("priority"),
+		Tag:      c.QueryParam("tag"),
+		Query:    c.QueryParam("q"),
+		Sort:     c.QueryParam("sort"),
+		Order:    c.QueryParam("order"),
+		Cursor:   c.QueryParam("cursor"),
+	}
+
+	if raw := c.QueryParam("completed"); raw != "" {
+		completed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, errors.New("completed must be true or false")
+		}
+		query.Completed = &completed
+	}
+
+	if raw := c.QueryParam("ready"); raw != "" {
+		ready, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, errors.New("ready must be true or false")
+		}
+		query.Ready = ready
+	}
+
+	if raw := c.QueryParam("list_id"); raw != "" {
+		listID, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, errors.New("list_id must be a number")
+		}
+		query.ListID = &listID
+	}
+
+	if raw := c.QueryParam("assigned_to"); raw != "" {
+		assigneeID := userID
+		if raw != "me" {
+			var err error
+			if assigneeID, err = strconv.Atoi(raw); err != nil {
+				return query, errors.New("assigned_to must be me or a user id")
+			}
+		}
+		query.AssigneeID = &assigneeID
+	}
+
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return query, errors.New("limit must be a number")
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+)
+
+type TodoListHandler struct {
+	service service.TodoListService
+}
+
+func NewTodoListHandler(service service.TodoListService) *TodoListHandler {
+	return &TodoListHandler{service: service}
+}
+
+// GetLists handles GET /api/v1/lists?archived=, returning owned and shared lists.
+func (h *TodoListHandler) GetLists(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	archived := false
+	if raw := c.QueryParam("archived"); raw != "" {
+		if archived, err = strconv.ParseBool(raw); err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, "archived must be true or false"))
+		}
+	}
+
+	lists, err := h.service.GetAll(ctx, userID, archived)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgListsFetched, lists))
+}
+
+// GetList handles GET /api/v1/lists/:id.
+func (h *TodoListHandler) GetList(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	list, err := h.service.GetByID(ctx, userID, id)
+	if err != nil {
+		return internalError(c, err)
+	}
+	if list == nil {
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse("List not found", nil))
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgListFetched, list))
+}
+
+// CreateList handles POST /api/v1/lists.
+func (h *TodoListHandler) CreateList(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	var req models.CreateTodoListRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	list, err := h.service.Create(ctx, userID, req)
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgListCreated, list))
+}
+
+// UpdateList handles PUT /api/v1/lists/:id.
+func (h *TodoListHandler) UpdateList(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.UpdateTodoListRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	list, err := h.service.Update(ctx, userID, id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("List not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgListUpdated, list))
+}
+
+// DeleteList handles DELETE /api/v1/lists/:id. Todos in the list are kept.
+func (h *TodoListHandler) DeleteList(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.Delete(ctx, userID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("List not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgListDeleted, nil))
+}
+
+// ArchiveList handles POST /api/v1/lists/:id/archive.
+func (h *TodoListHandler) ArchiveList(c echo.Context) error {
+	return h.setArchived(c, true, constants.MsgListArchived)
+}
+
+// UnarchiveList handles POST /api/v1/lists/:id/unarchive.
+func (h *TodoListHandler) UnarchiveList(c echo.Context) error {
+	return h.setArchived(c, false, constants.MsgListUnarchived)
+}
+
+func (h *TodoListHandler) setArchived(c echo.Context, archived bool, message string) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	list, err := h.service.SetArchived(ctx, userID, id, archived)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("List not found", nil))
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(message, list))
+}
+
+// CountOpenTodos handles GET /api/v1/lists/:id/open-count.
+func (h *TodoListHandler) CountOpenTodos(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	count, err := h.service.CountOpen(ctx, userID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("List not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgListCounted, map[string]any{
+		"list_id":    id,
+		"open_todos": count,
+	}))
+}
+
+// MoveTodos handles POST /api/v1/lists/:id/move.
+func (h *TodoListHandler) MoveTodos(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.MoveTodosRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := h.service.MoveTodos(ctx, userID, id, req); err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("List not found", nil))
+		case errors.Is(err, service.ErrForbidden):
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		case errors.Is(err, service.ErrListArchived):
+			return c.JSON(http.StatusConflict, dto.ErrorResponse("List is archived", nil))
+		case errors.Is(err, service.ErrInvalidList), errors.Is(err, service.ErrTodosNotInList):
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodosMoved, nil))
+}
+
+// GetMembers handles GET /api/v1/lists/:id/members.
+func (h *TodoListHandler) GetMembers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	members, err := h.service.GetMembers(ctx, userID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse("List not found", nil))
+		}
+		return internalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgListMembersFetched, members))
+}
+
+// AddMember handles POST /api/v1/lists/:id/members.
+func (h *TodoListHandler) AddMember(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.AddListMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	member, err := h.service.AddMember(ctx, userID, id, req)
+	if err != nil {
+		return listMemberError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgListMemberAdded, member))
+}
+
+// UpdateMember handles PUT /api/v1/lists/:id/members/:user_id.
+func (h *TodoListHandler) UpdateMember(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	memberID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	var req models.UpdateListMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	member, err := h.service.UpdateMember(ctx, userID, id, memberID, req)
+	if err != nil {
+		return listMemberError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgListMemberUpdated, member))
+}
+
+// RemoveMember handles DELETE /api/v1/lists/:id/members/:user_id.
+func (h *TodoListHandler) RemoveMember(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	memberID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrInvalidID, err.Error()))
+	}
+
+	if err := h.service.RemoveMember(ctx, userID, id, memberID); err != nil {
+		return listMemberError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgListMemberRemoved, nil))
+}
+
+// listMemberError maps membership service errors to responses.
+func listMemberError(c echo.Context, err error) error {
+	switch {
+	case err == sql.ErrNoRows:
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse("List not found", nil))
+	case errors.Is(err, service.ErrForbidden):
+		return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+	case errors.Is(err, service.ErrMemberNotFound):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse("User not found", err.Error()))
+	case errors.Is(err, service.ErrNotMember):
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse("Member not found", err.Error()))
+	case errors.Is(err, service.ErrAlreadyMember):
+		return c.JSON(http.StatusConflict, dto.ErrorResponse("Already a member", err.Error()))
+	}
+	return internalError(c, err)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+)
+
+type TodoQuickAddHandler struct {
+	service service.TodoQuickAddService
+}
+
+func NewTodoQuickAddHandler(service service.TodoQuickAddService) *TodoQuickAddHandler {
+	return &TodoQuickAddHandler{service: service}
+}
+
+// QuickAddTodo handles POST /api/v1/todos/quick. With dry_run the parsed
+// tokens come back so the client can preview them; nothing is stored.
+func (h *TodoQuickAddHandler) QuickAddTodo(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	var req models.QuickAddTodoRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	result, err := h.service.QuickAdd(ctx, userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			return c.JSON(http.StatusForbidden, dto.ErrorResponse("Forbidden", err.Error()))
+		}
+		if errors.Is(err, service.ErrListArchived) {
+			return c.JSON(http.StatusConflict, dto.ErrorResponse("List is archived", nil))
+		}
+		if errors.Is(err, service.ErrInvalidQuickAdd) || service.IsInvalidTodoInput(err) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	if req.DryRun {
+		return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgTodoQuickAddParsed, result))
+	}
+	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgTodoCreated, result))
+}
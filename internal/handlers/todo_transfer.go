@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
+	"github.com/manish-npx/todo-go-echo/internal/dto"
+	"github.com/manish-npx/todo-go-echo/internal/logger"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/service"
+	"go.uber.org/zap"
+)
+
+// maxImportFileSize bounds uploads to POST /todos/import.
+const maxImportFileSize = 5 << 20
+
+// todoCSVHeader is the export layout. Imports match columns by name, so an
+// export can be edited and uploaded again; columns import doesn't know
+// (id, completed, timestamps) are ignored, and a timezone column may be
+// added for date-only due dates. Titles and descriptions that look like
+// formulas are exported with a leading quote, which import strips again.
+var todoCSVHeader = []string{
+	"id", "title", "description", "completed", "status", "priority", "due_at",
+	"tag_ids", "list_id", "parent_id", "recurrence_rule", "created_at", "updated_at",
+}
+
+// ExportTodos handles GET /api/v1/todos/export?format=csv|json. Todos are
+// written as they are read, so the response starts before the export ends.
+func (h *TodoHandler) ExportTodos(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	format := models.TodoImportFormat(c.QueryParam("format"))
+	var writer todoExportWriter
+	switch format {
+	case "", models.TodoFormatCSV:
+		format = models.TodoFormatCSV
+		writer = &todoCSVWriter{}
+	case models.TodoFormatJSON:
+		writer = &todoJSONWriter{}
+	default:
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, "format must be csv or json"))
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, writer.contentType())
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="todos.%s"`, format))
+
+	err = h.service.Export(ctx, userID, func(todos []models.Todo) error {
+		if err := writer.write(res, todos); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	if err == nil {
+		err = writer.close(res)
+	}
+	if err != nil {
+		if !res.Committed {
+			return internalError(c, err)
+		}
+		// Too late for an error status; the cut-off body tells the client.
+		logger.L().Error("todo_export_failed", zap.Int("user_id", userID), zap.Error(err))
+	}
+	return nil
+}
+
+// ImportTodos handles POST /api/v1/todos/import as multipart/form-data with
+// a "file" part, an optional "format" (csv or json, else taken from the file
+// name) and an optional "dry_run".
+func (h *TodoHandler) ImportTodos(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, "file is required"))
+	}
+	if header.Size > maxImportFileSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse(constants.ErrValidation, fmt.Sprintf("file must be at most %d bytes", maxImportFileSize)))
+	}
+
+	format := models.TodoImportFormat(c.FormValue("format"))
+	if format == "" {
+		format = models.TodoImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), "."))
+	}
+
+	dryRun := false
+	if raw := c.FormValue("dry_run"); raw != "" {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, "dry_run must be true or false"))
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+	defer file.Close()
+
+	var rows []models.TodoImportRow
+	switch format {
+	case models.TodoFormatCSV:
+		rows, err = readTodoCSV(file)
+	case models.TodoFormatJSON:
+		rows, err = readTodoJSON(file)
+	default:
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, "format must be csv or json"))
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+	}
+
+	// Rows go through the same validation as a CreateTodoRequest body.
+	for i := range rows {
+		if rows[i].Err == nil {
+			rows[i].Err = c.Validate(&rows[i].Request)
+		}
+	}
+
+	result, err := h.service.Import(ctx, userID, rows, dryRun)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImport) {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
+		}
+		return internalError(c, err)
+	}
+
+	status, message := http.StatusCreated, constants.MsgTodosImported
+	if dryRun {
+		status, message = http.StatusOK, constants.MsgTodosImportChecked
+	}
+	return c.JSON(status, dto.SuccessResponse(message, result))
+}
+
+// todoExportWriter encodes batches of todos as they arrive.
+type todoExportWriter interface {
+	contentType() string
+	write(w io.Writer, todos []models.Todo) error
+	// close finishes the document, also when no batch was written.
+	close(w io.Writer) error
+}
+
+type todoCSVWriter struct {
+	csv *csv.Writer
+}
+
+func (t *todoCSVWriter) contentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (t *todoCSVWriter) start(w io.Writer) error {
+	if t.csv != nil {
+		return nil
+	}
+	t.csv = csv.NewWriter(w)
+	return t.csv.Write(todoCSVHeader)
+}
+
+func (t *todoCSVWriter) write(w io.Writer, todos []models.Todo) error {
+	if err := t.start(w); err != nil {
+		return err
+	}
+	for _, todo := range todos {
+		dueAt := ""
+		if todo.DueAt != nil {
+			dueAt = todo.DueAt.UTC().Format(time.RFC3339)
+		}
+		tagIDs := make([]string, 0, len(todo.Tags))
+		for _, tag := range todo.Tags {
+			tagIDs = append(tagIDs, strconv.Itoa(tag.ID))
+		}
+
+		err := t.csv.Write([]string{
+			strconv.Itoa(todo.ID),
+			escapeCSVFormula(todo.Title),
+			escapeCSVFormula(todo.Description),
+			strconv.FormatBool(todo.Completed),
+			string(todo.Status),
+			string(todo.Priority),
+			dueAt,
+			strings.Join(tagIDs, ";"),
+			formatOptionalInt(todo.ListID),
+			formatOptionalInt(todo.ParentID),
+			derefString(todo.RecurrenceRule),
+			todo.CreatedAt.UTC().Format(time.RFC3339),
+			todo.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	t.csv.Flush()
+	return t.csv.Error()
+}
+
+func (t *todoCSVWriter) close(w io.Writer) error {
+	if err := t.start(w); err != nil {
+		return err
+	}
+	t.csv.Flush()
+	return t.csv.Error()
+}
+
+type todoJSONWriter struct {
+	started bool
+}
+
+func (t *todoJSONWriter) contentType() string {
+	return echo.MIMEApplicationJSONCharsetUTF8
+}
+
+func (t *todoJSONWriter) write(w io.Writer, todos []models.Todo) error {
+	for _, todo := range todos {
+		sep := ","
+		if !t.started {
+			sep, t.started = "[", true
+		}
+		raw, err := json.Marshal(todo)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *todoJSONWriter) close(w io.Writer) error {
+	end := "]"
+	if !t.started {
+		end = "[]"
+	}
+	_, err := io.WriteString(w, end+"\n")
+	return err
+}
+
+// readTodoCSV reads a header row and one request per following record.
+// Cells that don't parse fail only their own row.
+func readTodoCSV(r io.Reader) ([]models.TodoImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("CSV header must include a title column")
+	}
+
+	var rows []models.TodoImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// One extra row is enough for the service to reject the file.
+		if len(rows) > service.MaxImportRows {
+			return rows, nil
+		}
+
+		line, _ := reader.FieldPos(0)
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row := models.TodoImportRow{Line: line}
+		row.Request, row.Err = parseTodoCSVRecord(cell)
+		rows = append(rows, row)
+	}
+}
+
+func parseTodoCSVRecord(cell func(name string) string) (models.CreateTodoRequest, error) {
+	req := models.CreateTodoRequest{
+		Title:          unescapeCSVFormula(cell("title")),
+		Description:    unescapeCSVFormula(cell("description")),
+		Timezone:       cell("timezone"),
+		Status:         cell("status"),
+		Priority:       cell("priority"),
+		RecurrenceRule: cell("recurrence_rule"),
+	}
+	if dueAt := cell("due_at"); dueAt != "" {
+		req.DueAt = &dueAt
+	}
+
+	var err error
+	if req.ListID, err = parseOptionalInt("list_id", cell("list_id")); err != nil {
+		return req, err
+	}
+	if req.ParentID, err = parseOptionalInt("parent_id", cell("parent_id")); err != nil {
+		return req, err
+	}
+	if tagIDs := cell("tag_ids"); tagIDs != "" {
+		for _, raw := range strings.Split(tagIDs, ";") {
+			id, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil {
+				return req, fmt.Errorf("tag_ids must be numbers separated by ';'")
+			}
+			req.TagIDs = append(req.TagIDs, id)
+		}
+	}
+	return req, nil
+}
+
+// readTodoJSON reads an array of CreateTodoRequest objects. An element that
+// doesn't fit the request shape fails only its own row; a file that isn't a
+// JSON array fails as a whole.
+func readTodoJSON(r io.Reader) ([]models.TodoImportRow, error) {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, errors.New("JSON import must be an array of todos")
+	}
+
+	var rows []models.TodoImportRow
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("reading todo %d: %w", len(rows)+1, err)
+		}
+		if len(rows) > service.MaxImportRows {
+			return rows, nil
+		}
+
+		row := models.TodoImportRow{Line: len(rows) + 1}
+		row.Err = json.Unmarshal(raw, &row.Request)
+		rows = append(rows, row)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("reading JSON array: %w", err)
+	}
+	return rows, nil
+}
+
+func parseOptionalInt(name, value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", name)
+	}
+	return &n, nil
+}
+
+// escapeCSVFormula prefixes a cell that a spreadsheet would run as a formula
+// with a quote, so exported text stays text.
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVFormula undoes escapeCSVFormula so an export imports unchanged.
+func unescapeCSVFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+func TestTodoCSVExportEscapesFormulas(t *testing.T) {
+	todos := []models.Todo{
+		{ID: 1, Title: `=HYPERLINK("http://evil.example","Click")`, Description: "@SUM(A1)"},
+		{ID: 2, Title: "-5 degrees", Description: "+1 for this"},
+		{ID: 3, Title: "Plain", Description: "'quoted' on purpose"},
+	}
+
+	var buf bytes.Buffer
+	writer := &todoCSVWriter{}
+	if err := writer.write(&buf, todos); err != nil {
+		t.Fatalf("write() error = %v", err)
+	}
+	if err := writer.close(&buf); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	for i, record := range records[1:] {
+		for _, cell := range record[1:3] {
+			if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+				t.Fatalf("todo %d exported formula cell %q", todos[i].ID, cell)
+			}
+		}
+	}
+
+	rows, err := readTodoCSV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("readTodoCSV() error = %v", err)
+	}
+	for i, row := range rows {
+		if row.Request.Title != todos[i].Title || row.Request.Description != todos[i].Description {
+			t.Fatalf("row %d imported as %q / %q, want %q / %q",
+				i, row.Request.Title, row.Request.Description, todos[i].Title, todos[i].Description)
+		}
+	}
+}
@@ -22,6 +22,8 @@ func NewUserHandler(s service.UserService) *UserHandler {
 
 // POST /register
 func (h *UserHandler) Register(c echo.Context) error {
+	ctx := c.Request().Context()
+
 	var req models.RegisterRequest
 
 	if err := c.Bind(&req); err != nil {
@@ -32,11 +34,11 @@ func (h *UserHandler) Register(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
 	}
 
-	if err := h.service.Register(req); err != nil {
+	if err := h.service.Register(ctx, req); err != nil {
 		if errors.Is(err, service.ErrEmailAlreadyExists) {
 			return c.JSON(http.StatusConflict, dto.ErrorResponse("Email already exists", err.Error()))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgUserRegistered, nil))
@@ -44,6 +46,8 @@ func (h *UserHandler) Register(c echo.Context) error {
 
 // POST /users (protected)
 func (h *UserHandler) CreateUser(c echo.Context) error {
+	ctx := c.Request().Context()
+
 	var req models.CreateUserRequest
 
 	if err := c.Bind(&req); err != nil {
@@ -53,11 +57,11 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
 	}
 
-	if err := h.service.CreateUser(req); err != nil {
+	if err := h.service.CreateUser(ctx, req); err != nil {
 		if errors.Is(err, service.ErrEmailAlreadyExists) {
 			return c.JSON(http.StatusConflict, dto.ErrorResponse("Email already exists", err.Error()))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, dto.SuccessResponse(constants.MsgUserCreated, nil))
@@ -65,6 +69,8 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 
 // POST /login
 func (h *UserHandler) Login(c echo.Context) error {
+	ctx := c.Request().Context()
+
 	var req models.LoginRequest
 
 	if err := c.Bind(&req); err != nil {
@@ -75,7 +81,7 @@ func (h *UserHandler) Login(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse(constants.ErrValidation, err.Error()))
 	}
 
-	token, err := h.service.Login(req)
+	token, err := h.service.Login(ctx, req)
 	if err != nil {
 		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Invalid credentials", err.Error()))
 	}
@@ -85,17 +91,19 @@ func (h *UserHandler) Login(c echo.Context) error {
 
 // GET /users/profile (protected)
 func (h *UserHandler) Profile(c echo.Context) error {
+	ctx := c.Request().Context()
+
 	userID, err := getUserIDFromToken(c)
 	if err != nil {
 		return c.JSON(http.StatusUnauthorized, dto.ErrorResponse("Unauthorized", err.Error()))
 	}
 
-	user, err := h.service.GetProfile(userID)
+	user, err := h.service.GetProfile(ctx, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
 			return c.JSON(http.StatusNotFound, dto.ErrorResponse("User not found", err.Error()))
 		}
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgUserProfileFetched, user))
@@ -103,9 +111,11 @@ func (h *UserHandler) Profile(c echo.Context) error {
 
 // GET /users
 func (h *UserHandler) GetUsers(c echo.Context) error {
-	users, err := h.service.GetUsers()
+	ctx := c.Request().Context()
+
+	users, err := h.service.GetUsers(ctx)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse(constants.ErrInternal, err.Error()))
+		return internalError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, dto.SuccessResponse(constants.MsgUsersFetched, users))
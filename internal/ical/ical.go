@@ -0,0 +1,366 @@
+// Package ical reads and writes the subset of iCalendar (RFC 5545) needed to
+// exchange todos as VTODO components: one VTODO per calendar object, with
+// the properties a task app shows. Other components (VTIMEZONE, VALARM) are
+// skipped when reading and unknown properties are dropped.
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoTodo is returned for calendar data without a VTODO component.
+var ErrNoTodo = errors.New("calendar object has no VTODO")
+
+// ErrMalformed is returned for data that isn't an iCalendar object.
+var ErrMalformed = errors.New("malformed iCalendar data")
+
+// Status values of a VTODO.
+const (
+	StatusNeedsAction = "NEEDS-ACTION"
+	StatusInProcess   = "IN-PROCESS"
+	StatusCompleted   = "COMPLETED"
+	StatusCancelled   = "CANCELLED"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+	utcLayout      = "20060102T150405Z"
+)
+
+// Todo is a VTODO component. Times are in UTC once decoded.
+type Todo struct {
+	UID         string
+	Summary     string
+	Description string
+	Status      string
+	Priority    int // 1 (highest) to 9 (lowest); 0 means undefined
+	Due         *time.Time
+	DueIsDate   bool // DUE had VALUE=DATE; Due is the start of that day in UTC
+	Completed   *time.Time
+	Created     time.Time
+	Modified    time.Time
+	Stamp       time.Time
+	RRule       string
+	Categories  []string
+}
+
+// Encode writes t as a complete VCALENDAR object.
+func Encode(t *Todo) []byte {
+	var b bytes.Buffer
+	writeLine(&b, "BEGIN", "VCALENDAR")
+	writeLine(&b, "VERSION", "2.0")
+	writeLine(&b, "PRODID", "-//todo-go-echo//CalDAV//EN")
+	writeLine(&b, "BEGIN", "VTODO")
+	writeLine(&b, "UID", escapeText(t.UID))
+	writeLine(&b, "DTSTAMP", t.Stamp.UTC().Format(utcLayout))
+	if !t.Created.IsZero() {
+		writeLine(&b, "CREATED", t.Created.UTC().Format(utcLayout))
+	}
+	if !t.Modified.IsZero() {
+		writeLine(&b, "LAST-MODIFIED", t.Modified.UTC().Format(utcLayout))
+	}
+	writeLine(&b, "SUMMARY", escapeText(t.Summary))
+	if t.Description != "" {
+		writeLine(&b, "DESCRIPTION", escapeText(t.Description))
+	}
+	if t.Status != "" {
+		writeLine(&b, "STATUS", t.Status)
+	}
+	if t.Completed != nil {
+		writeLine(&b, "COMPLETED", t.Completed.UTC().Format(utcLayout))
+	}
+	if t.Due != nil {
+		if t.DueIsDate {
+			writeLine(&b, "DUE;VALUE=DATE", t.Due.UTC().Format(dateLayout))
+		} else {
+			writeLine(&b, "DUE", t.Due.UTC().Format(utcLayout))
+		}
+	}
+	if t.Priority > 0 {
+		writeLine(&b, "PRIORITY", strconv.Itoa(t.Priority))
+	}
+	if t.RRule != "" {
+		writeLine(&b, "RRULE", t.RRule)
+	}
+	if len(t.Categories) > 0 {
+		escaped := make([]string, len(t.Categories))
+		for i, category := range t.Categories {
+			escaped[i] = escapeText(category)
+		}
+		writeLine(&b, "CATEGORIES", strings.Join(escaped, ","))
+	}
+	writeLine(&b, "END", "VTODO")
+	writeLine(&b, "END", "VCALENDAR")
+	return b.Bytes()
+}
+
+// Decode reads the first VTODO of a VCALENDAR object.
+func Decode(data []byte) (*Todo, error) {
+	lines, err := unfold(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("%w: missing BEGIN:VCALENDAR", ErrMalformed)
+	}
+
+	var todo *Todo
+	// depth counts components opened inside the VTODO, such as VALARM.
+	depth := 0
+	for _, line := range lines {
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case prop.name == "BEGIN" && todo == nil && strings.EqualFold(prop.value, "VTODO"):
+			todo = &Todo{}
+			continue
+		case todo == nil:
+			continue
+		case prop.name == "BEGIN":
+			depth++
+			continue
+		case prop.name == "END" && depth > 0:
+			depth--
+			continue
+		case prop.name == "END":
+			if todo.UID == "" {
+				return nil, fmt.Errorf("%w: VTODO without UID", ErrMalformed)
+			}
+			return todo, nil
+		case depth > 0:
+			continue
+		}
+
+		if err := todo.set(prop); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrMalformed, prop.name, err)
+		}
+	}
+	if todo != nil {
+		return nil, fmt.Errorf("%w: unterminated VTODO", ErrMalformed)
+	}
+	return nil, ErrNoTodo
+}
+
+func (t *Todo) set(prop property) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		t.UID = unescapeText(prop.value)
+	case "SUMMARY":
+		t.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		t.Description = unescapeText(prop.value)
+	case "STATUS":
+		t.Status = strings.ToUpper(prop.value)
+	case "PRIORITY":
+		if t.Priority, err = strconv.Atoi(prop.value); err == nil && (t.Priority < 0 || t.Priority > 9) {
+			err = errors.New("must be between 0 and 9")
+		}
+	case "DUE":
+		var due time.Time
+		due, t.DueIsDate, err = parseTime(prop)
+		t.Due = &due
+	case "COMPLETED":
+		var completed time.Time
+		completed, _, err = parseTime(prop)
+		t.Completed = &completed
+	case "CREATED":
+		t.Created, _, err = parseTime(prop)
+	case "LAST-MODIFIED":
+		t.Modified, _, err = parseTime(prop)
+	case "DTSTAMP":
+		t.Stamp, _, err = parseTime(prop)
+	case "RRULE":
+		t.RRule = prop.value
+	case "CATEGORIES":
+		for _, category := range splitUnescaped(prop.value, ',') {
+			if category = strings.TrimSpace(unescapeText(category)); category != "" {
+				t.Categories = append(t.Categories, category)
+			}
+		}
+	}
+	return err
+}
+
+// Property returns the value of a VTODO property as it would be encoded,
+// for evaluating CalDAV prop-filters. ok is false when t doesn't have it.
+func (t *Todo) Property(name string) (value string, ok bool) {
+	switch strings.ToUpper(name) {
+	case "UID":
+		return t.UID, t.UID != ""
+	case "SUMMARY":
+		return t.Summary, t.Summary != ""
+	case "DESCRIPTION":
+		return t.Description, t.Description != ""
+	case "STATUS":
+		return t.Status, t.Status != ""
+	case "PRIORITY":
+		return strconv.Itoa(t.Priority), t.Priority > 0
+	case "DUE":
+		if t.Due == nil {
+			return "", false
+		}
+		return t.Due.UTC().Format(utcLayout), true
+	case "COMPLETED":
+		if t.Completed == nil {
+			return "", false
+		}
+		return t.Completed.UTC().Format(utcLayout), true
+	case "RRULE":
+		return t.RRule, t.RRule != ""
+	case "CATEGORIES":
+		return strings.Join(t.Categories, ","), len(t.Categories) > 0
+	}
+	return "", false
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseLine splits NAME;PARAM=VALUE:value, honouring quoted parameter values.
+func parseLine(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+	inQuotes := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ':':
+			if !inQuotes {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("%w: line without a value: %q", ErrMalformed, line)
+	}
+	prop.value = line[colon+1:]
+
+	parts := splitUnescaped(line[:colon], ';')
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// parseTime reads DATE, UTC DATE-TIME, DATE-TIME with TZID and floating
+// DATE-TIME values; floating times are taken as UTC.
+func parseTime(prop property) (time.Time, bool, error) {
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(prop.value) == len(dateLayout) {
+		day, err := time.Parse(dateLayout, prop.value)
+		return day, true, err
+	}
+	if strings.HasSuffix(prop.value, "Z") {
+		value, err := time.Parse(utcLayout, prop.value)
+		return value, false, err
+	}
+
+	loc := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+	value, err := time.ParseInLocation(dateTimeLayout, prop.value, loc)
+	return value.UTC(), false, err
+}
+
+// unfold joins continuation lines (starting with a space or tab) and drops
+// the line breaks, accepting bare LF as well as CRLF.
+func unfold(data []byte) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// writeLine writes name:value folded at 75 octets without splitting UTF-8 sequences.
+func writeLine(b *bytes.Buffer, name, value string) {
+	line := name + ":" + value
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // the leading space counts
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitUnescaped splits s at sep characters that aren't escaped or quoted.
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start, inQuotes := 0, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '"':
+			inQuotes = !inQuotes
+		case s[i] == sep && !inQuotes:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
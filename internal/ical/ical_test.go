@@ -0,0 +1,127 @@
+package ical
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		check   func(t *testing.T, todo *Todo)
+		wantErr error
+	}{
+		{
+			name: "apple reminders style",
+			data: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Apple Inc.//iOS 17//EN\r\n" +
+				"BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\nBEGIN:STANDARD\r\nDTSTART:19701025T030000\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n" +
+				"BEGIN:VTODO\r\nUID:ABC-123\r\nDTSTAMP:20260301T100000Z\r\nSUMMARY:Buy milk\\, eggs\r\n" +
+				"DUE;TZID=Europe/Berlin:20260302T090000\r\nPRIORITY:1\r\nSTATUS:NEEDS-ACTION\r\n" +
+				"BEGIN:VALARM\r\nACTION:DISPLAY\r\nSUMMARY:Alarm text\r\nEND:VALARM\r\n" +
+				"END:VTODO\r\nEND:VCALENDAR\r\n",
+			check: func(t *testing.T, todo *Todo) {
+				if todo.UID != "ABC-123" || todo.Summary != "Buy milk, eggs" || todo.Priority != 1 {
+					t.Fatalf("Decode() = %+v", todo)
+				}
+				want := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+				if todo.Due == nil || !todo.Due.Equal(want) || todo.DueIsDate {
+					t.Fatalf("Due = %v, want %v", todo.Due, want)
+				}
+			},
+		},
+		{
+			name: "folded lines, LF endings and escapes",
+			data: "BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:x\nDESCRIPTION:first line\\nsecond\n  line\\; done\nEND:VTODO\nEND:VCALENDAR\n",
+			check: func(t *testing.T, todo *Todo) {
+				if todo.Description != "first line\nsecond line; done" {
+					t.Fatalf("Description = %q", todo.Description)
+				}
+			},
+		},
+		{
+			name: "date-only due and completion",
+			data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:y\r\nDUE;VALUE=DATE:20260415\r\nSTATUS:COMPLETED\r\nCOMPLETED:20260410T120000Z\r\nCATEGORIES:work,home\\,garden\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			check: func(t *testing.T, todo *Todo) {
+				if !todo.DueIsDate || todo.Due.Format(dateLayout) != "20260415" {
+					t.Fatalf("Due = %v (date %v)", todo.Due, todo.DueIsDate)
+				}
+				if todo.Status != StatusCompleted || todo.Completed == nil {
+					t.Fatalf("Status = %q, Completed = %v", todo.Status, todo.Completed)
+				}
+				if !slices.Equal(todo.Categories, []string{"work", "home,garden"}) {
+					t.Fatalf("Categories = %q", todo.Categories)
+				}
+			},
+		},
+		{name: "event only", data: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:e\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", wantErr: ErrNoTodo},
+		{name: "not a calendar", data: "hello", wantErr: ErrMalformed},
+		{name: "missing uid", data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:x\r\nEND:VTODO\r\nEND:VCALENDAR\r\n", wantErr: ErrMalformed},
+		{name: "bad priority", data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:p\r\nPRIORITY:12\r\nEND:VTODO\r\nEND:VCALENDAR\r\n", wantErr: ErrMalformed},
+		{name: "unknown timezone", data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:z\r\nDUE;TZID=Mars/Olympus:20260101T000000\r\nEND:VTODO\r\nEND:VCALENDAR\r\n", wantErr: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo, err := Decode([]byte(tt.data))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Decode() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			tt.check(t, todo)
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	due := time.Date(2026, 5, 1, 17, 30, 0, 0, time.UTC)
+	stamp := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)
+	in := &Todo{
+		UID:         "todo-7@example",
+		Summary:     "Plan; review, ship",
+		Description: strings.Repeat("long description ", 10) + "ünïcödé",
+		Status:      StatusNeedsAction,
+		Priority:    5,
+		Due:         &due,
+		Stamp:       stamp,
+		Created:     stamp,
+		Modified:    stamp,
+		RRule:       "FREQ=WEEKLY;BYDAY=MO",
+		Categories:  []string{"work", "a,b"},
+	}
+
+	data := Encode(in)
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line longer than 75 octets: %q", line)
+		}
+	}
+
+	out, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode(Encode()) error = %v", err)
+	}
+	if out.UID != in.UID || out.Summary != in.Summary || out.Description != in.Description ||
+		out.Status != in.Status || out.Priority != in.Priority || out.RRule != in.RRule ||
+		!out.Due.Equal(due) || !out.Stamp.Equal(stamp) || !slices.Equal(out.Categories, in.Categories) {
+		t.Fatalf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestProperty(t *testing.T) {
+	todo := &Todo{UID: "u", Summary: "Call mom", Status: StatusNeedsAction}
+	if _, ok := todo.Property("COMPLETED"); ok {
+		t.Fatal("Property(COMPLETED) defined on an open todo")
+	}
+	if value, ok := todo.Property("status"); !ok || value != StatusNeedsAction {
+		t.Fatalf("Property(status) = %q, %v", value, ok)
+	}
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// BasicAuthMiddleware guards routes used by clients that can't log in for a
+// JWT, such as calendar apps. The validator stores whatever the handlers need.
+func BasicAuthMiddleware(realm string, validator echoMiddleware.BasicAuthValidator) echo.MiddlewareFunc {
+	return echoMiddleware.BasicAuthWithConfig(echoMiddleware.BasicAuthConfig{
+		Realm:     realm,
+		Validator: validator,
+	})
+}
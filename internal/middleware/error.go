@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/manish-npx/todo-go-echo/internal/constants"
 	"github.com/manish-npx/todo-go-echo/internal/dto"
 	"github.com/manish-npx/todo-go-echo/internal/logger"
 	"go.uber.org/zap"
@@ -29,6 +32,12 @@ func ErrorHandler(err error, c echo.Context) {
 				msg = "request failed"
 			}
 		}
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		code = http.StatusServiceUnavailable
+		msg = "request timed out"
+	} else if errors.Is(err, context.Canceled) {
+		code = constants.StatusClientClosedRequest
+		msg = "request canceled"
 	} else if err != nil {
 		msg = err.Error()
 	}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// AppPassword is a generated secret a user hands to a CalDAV client instead
+// of their account password. Only its bcrypt hash is stored.
+type AppPassword struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id" gorm:"index"`
+	Name       string     `json:"name"`
+	Password   string     `json:"-" gorm:"column:password_hash"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CreateAppPasswordRequest is used when generating an app password
+type CreateAppPasswordRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"` // e.g. "iPhone Reminders"
+}
+
+// NewAppPassword is returned once on creation; the password can't be read back later
+type NewAppPassword struct {
+	AppPassword
+	Password string `json:"password"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // BlogStatus represents the possible states of a blog post
 type BlogStatus string
@@ -12,17 +16,18 @@ const (
 
 // Blog represents a blog post
 type Blog struct {
-	ID          int        `json:"id" db:"id"`
-	Title       string     `json:"title" db:"title"`
-	Content     string     `json:"content" db:"content"`
-	Author      string     `json:"author" db:"author"`
-	CategoryID  *int       `json:"category_id,omitempty" db:"category_id"`
-	Category    *Category  `json:"category,omitempty"` // This will be populated when joining
-	Status      BlogStatus `json:"status" db:"status"`
-	Views       int        `json:"views" db:"views"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-	PublishedAt *time.Time `json:"published_at,omitempty" db:"published_at"`
+	ID          int            `json:"id" db:"id"`
+	Title       string         `json:"title" db:"title"`
+	Content     string         `json:"content" db:"content"`
+	Author      string         `json:"author" db:"author"`
+	CategoryID  *int           `json:"category_id,omitempty" db:"category_id"`
+	Category    *Category      `json:"category,omitempty"` // This will be populated when joining
+	Status      BlogStatus     `json:"status" db:"status"`
+	Views       int            `json:"views" db:"views"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+	PublishedAt *time.Time     `json:"published_at,omitempty" db:"published_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitzero" db:"deleted_at" gorm:"index"` // set while the blog is in the trash
 }
 
 // CreateBlogRequest is used when creating a blog
@@ -42,3 +47,56 @@ type UpdateBlogRequest struct {
 	CategoryID *int    `json:"category_id"`
 	Status     *string `json:"status" validate:"omitempty,oneof=draft published"`
 }
+
+// Sort fields accepted by GET /blogs.
+const (
+	BlogSortCreatedAt = "created_at"
+	BlogSortUpdatedAt = "updated_at"
+	BlogSortTitle     = "title"
+	BlogSortViews     = "views"
+)
+
+// BlogListQuery carries the raw GET /blogs query parameters.
+type BlogListQuery struct {
+	CategoryID *int
+	Author     string
+	Status     string
+	From       string // YYYY-MM-DD, inclusive
+	To         string // YYYY-MM-DD, inclusive
+	Sort       string
+	Order      string
+	Limit      int
+	Offset     int
+	Cursor     string
+}
+
+// BlogCursor is the decoded keyset position of the last row on a page.
+type BlogCursor struct {
+	Value any
+	ID    int
+}
+
+// BlogFilter is the normalized list query handed to the repository. Every
+// set field narrows the result; From and To bound created_at as [From, To).
+type BlogFilter struct {
+	CategoryID *int
+	Author     string
+	Status     BlogStatus
+	From       *time.Time
+	To         *time.Time
+	Sort       string
+	Desc       bool
+	Limit      int
+	Offset     int
+	After      *BlogCursor
+}
+
+// BlogPage is a single page of blogs plus paging metadata.
+type BlogPage struct {
+	Blogs      []Blog
+	Total      int64
+	Limit      int
+	Offset     int
+	NextCursor string
+	HasMore    bool
+}
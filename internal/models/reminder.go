@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Reminder is a todo whose reminder time has passed, addressed to the
+// assignee or, without one, the owner.
+type Reminder struct {
+	TodoID   int        `json:"todo_id"`
+	Title    string     `json:"title"`
+	DueAt    *time.Time `json:"due_at,omitempty"`
+	RemindAt time.Time  `json:"remind_at"`
+	UserID   int        `json:"user_id"` // recipient
+	Name     string     `json:"name"`
+	Email    string     `json:"email"`
+}
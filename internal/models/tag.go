@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Tag is a user-owned label that can be attached to many todos
+type Tag struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id" gorm:"index;uniqueIndex:uni_tags_user_name"`
+	Name      string    `json:"name" db:"name" gorm:"uniqueIndex:uni_tags_user_name,expression:LOWER(name)"`
+	Color     string    `json:"color" db:"color"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateTagRequest is used when creating a tag
+type CreateTagRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=50"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+// UpdateTagRequest is used when renaming or recoloring a tag
+type UpdateTagRequest struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color *string `json:"color" validate:"omitempty,hexcolor"`
+}
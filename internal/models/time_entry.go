@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// TimeEntry is time a user spent on a todo, from a timer or entered by hand
+type TimeEntry struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id" gorm:"uniqueIndex:uni_time_entries_running,where:ended_at IS NULL"` // who tracked it
+	TodoID    int        `json:"todo_id" gorm:"index"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"` // nil while the timer runs
+	Note      string     `json:"note"`
+	// DurationSeconds runs up to now while the timer runs.
+	DurationSeconds int64     `json:"duration_seconds" gorm:"-"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// StartTimerRequest starts a timer on a todo
+type StartTimerRequest struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+// CreateTimeEntryRequest records time that wasn't tracked with the timer
+type CreateTimeEntryRequest struct {
+	StartedAt time.Time `json:"started_at" validate:"required"`
+	EndedAt   time.Time `json:"ended_at" validate:"required"`
+	Note      string    `json:"note" validate:"max=500"`
+}
+
+// TimeReportQuery carries the raw GET /time-entries/report query parameters.
+type TimeReportQuery struct {
+	Timezone string
+	From     string // YYYY-MM-DD, inclusive
+	To       string // YYYY-MM-DD, inclusive
+}
+
+// TimeReportFilter is the normalized report query handed to the repository.
+// Start and End bound the range in time; From and To are its days in Timezone.
+type TimeReportFilter struct {
+	Timezone string
+	From     string
+	To       string
+	Start    time.Time
+	End      time.Time
+	Now      time.Time // end of running timers
+}
+
+// TimeReport sums the caller's tracked time within a range. Entries crossing
+// the range or a midnight are split, and running timers count up to now.
+type TimeReport struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Timezone     string           `json:"timezone"`
+	TotalSeconds int64            `json:"total_seconds"`
+	ByTodo       []TimeReportTodo `json:"by_todo"`
+	// ByTag counts a todo with several tags under each, so it can add up to
+	// more than the total; untagged time is left out.
+	ByTag []TimeReportTag `json:"by_tag"`
+	ByDay []TimeReportDay `json:"by_day"` // every day of the range, empty ones included
+}
+
+type TimeReportTodo struct {
+	TodoID  int    `json:"todo_id"`
+	Title   string `json:"title"`
+	Seconds int64  `json:"seconds"`
+}
+
+type TimeReportTag struct {
+	TagID   int    `json:"tag_id"`
+	Name    string `json:"name"`
+	Seconds int64  `json:"seconds"`
+}
+
+type TimeReportDay struct {
+	Day     string `json:"day"`
+	Seconds int64  `json:"seconds"`
+}
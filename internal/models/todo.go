@@ -2,27 +2,312 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// TodoPriority is the triage level of a todo
+type TodoPriority string
+
+const (
+	PriorityNone   TodoPriority = "none"
+	PriorityLow    TodoPriority = "low"
+	PriorityMedium TodoPriority = "medium"
+	PriorityHigh   TodoPriority = "high"
+	PriorityUrgent TodoPriority = "urgent"
+)
+
+// Valid reports whether p is one of the known priority levels.
+func (p TodoPriority) Valid() bool {
+	switch p {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
+// TodoStatus is a step of the todo workflow. The set of statuses and the
+// transitions between them are configured; todo and done always exist.
+type TodoStatus string
+
+// The default workflow's statuses.
+const (
+	StatusTodo       TodoStatus = "todo" // where new todos start
+	StatusInProgress TodoStatus = "in_progress"
+	StatusBlocked    TodoStatus = "blocked"
+	StatusDone       TodoStatus = "done" // the only status that counts as completed
 )
 
 // models Todo represents a task in our todo list
 type Todo struct {
-	ID          int       `json:"id" db:"id"`
-	Title       string    `json:"title" validate:"required,min=3"`
-	Description string    `json:"description" validate:"required,min=5"`
-	Completed   bool      `json:"completed" db:"completed"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          int           `json:"id" db:"id"`
+	UserID      int           `json:"user_id" db:"user_id" gorm:"index"` // owner taken from the JWT
+	Title       string        `json:"title" validate:"required,min=3"`
+	Description string        `json:"description" validate:"required,min=5"`
+	Status      TodoStatus    `json:"status" db:"status" gorm:"default:todo"`
+	Completed   bool          `json:"completed" db:"completed" gorm:"->;type:boolean GENERATED ALWAYS AS (status = 'done') STORED"` // computed from Status
+	DueAt       *time.Time    `json:"due_at,omitempty" db:"due_at"`
+	RemindAt    *time.Time    `json:"remind_at,omitempty" db:"remind_at"`
+	RemindedAt  *time.Time    `json:"reminded_at,omitempty" db:"reminded_at"` // when the reminder went out; cleared when remind_at changes
+	Priority    TodoPriority  `json:"priority" db:"priority" gorm:"default:none"`
+	Tags        []Tag         `json:"tags" gorm:"many2many:todo_tags;"`
+	ParentID    *int          `json:"parent_id,omitempty" db:"parent_id" gorm:"index"`
+	ListID      *int          `json:"list_id,omitempty" db:"list_id" gorm:"index"`
+	AssigneeID  *int          `json:"assignee_id,omitempty" db:"assignee_id" gorm:"index"`   // sees the todo whoever owns it
+	Position    string        `json:"position" db:"position" gorm:"type:text COLLATE \"C\""` // rank key within the list, or the owner's todos without a list
+	Children    []Todo        `json:"children,omitempty" gorm:"-"`                           // filled for GET /todos/:id only
+	Progress    *TodoProgress `json:"progress,omitempty" gorm:"-"`
+	// Recurrence: RecurrenceRule is an RRULE subset; SeriesID links every
+	// occurrence to the first one, and Occurrence is the 1-based position.
+	RecurrenceRule *string        `json:"recurrence_rule,omitempty" db:"recurrence_rule"`
+	Timezone       string         `json:"timezone,omitempty" db:"timezone" gorm:"size:64;not null;default:''"` // the rule is evaluated in this zone; "" is UTC
+	SeriesID       *int           `json:"series_id,omitempty" db:"series_id" gorm:"index"`
+	Occurrence     int            `json:"occurrence,omitempty" db:"occurrence" gorm:"default:1"`
+	NextOccurrence *Todo          `json:"next_occurrence,omitempty" gorm:"-"`               // set when completing spawns the next one
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitzero" db:"deleted_at" gorm:"index"` // set while the todo is in the trash
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+
+	// Read-only; list and get queries count the comments in a subquery.
+	CommentCount int `json:"comment_count" gorm:"->;-:migration"`
+
+	// CalDAV identity of todos created by a CalDAV client; others use defaults derived from ID.
+	ICalUID    *string `json:"-" db:"ical_uid" gorm:"column:ical_uid"`
+	CalDAVName *string `json:"-" db:"caldav_name" gorm:"column:caldav_name"`
+}
+
+// SetStatus moves the todo to status, keeping Completed in step with the
+// column the database computes.
+func (t *Todo) SetStatus(status TodoStatus) {
+	t.Status = status
+	t.Completed = status == StatusDone
+}
+
+// TodoProgress rolls up the completion of a todo's direct subtasks.
+type TodoProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
 }
 
+// SubtaskDeleteMode decides what happens to subtasks when their parent is deleted.
+type SubtaskDeleteMode string
+
+const (
+	SubtaskDeleteCascade SubtaskDeleteMode = "cascade" // delete the whole subtree
+	SubtaskDeletePromote SubtaskDeleteMode = "promote" // re-attach children to the grandparent
+)
+
 // CreateTodoRequest is used when creating a new todo
 type CreateTodoRequest struct {
-	Title       string `json:"title" validate:"required,min=3"`
-	Description string `json:"description" validate:"required,min=5"`
+	Title       string  `json:"title" validate:"required,min=3"`
+	Description string  `json:"description" validate:"required,min=5"`
+	DueAt       *string `json:"due_at,omitempty" validate:"omitempty,due_date"`
+	RemindAt    *string `json:"remind_at,omitempty" validate:"omitempty,due_date"`
+	Timezone    string  `json:"timezone,omitempty" validate:"omitempty,timezone"` // resolves date-only due_at and remind_at
+	Priority    string  `json:"priority,omitempty" validate:"omitempty,oneof=none low medium high urgent"`
+	TagIDs      []int   `json:"tag_ids,omitempty" validate:"omitempty,dive,min=1"`
+	ParentID    *int    `json:"parent_id,omitempty" validate:"omitempty,min=1"`
+	ListID      *int    `json:"list_id,omitempty" validate:"omitempty,min=1"` // defaults to the parent's list
+	Status      string  `json:"status,omitempty" validate:"omitempty,max=20"` // defaults to todo
+	// RecurrenceRule e.g. "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10"
+	RecurrenceRule string `json:"recurrence_rule,omitempty" validate:"omitempty,rrule"`
+	// TagNames are matched against the owner's tags ignoring case, and
+	// created when missing. Only quick-add sets them.
+	TagNames []string `json:"-"`
 }
 
 // UpdateTodoRequest is used when updating an existing todo
 type UpdateTodoRequest struct {
 	Title       *string `json:"title,omitempty" validate:"omitempty,min=3"`
 	Description *string `json:"description,omitempty" validate:"omitempty,min=5"`
-	Completed   *bool   `json:"completed,omitempty"`
+	Completed   *bool   `json:"completed,omitempty"` // true moves to done, false reopens a done todo
+	Status      *string `json:"status,omitempty" validate:"omitempty,max=20"`
+	DueAt       *string `json:"due_at,omitempty" validate:"omitempty,due_date"`    // "" clears the due date
+	RemindAt    *string `json:"remind_at,omitempty" validate:"omitempty,due_date"` // "" clears the reminder
+	Timezone    string  `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Priority    *string `json:"priority,omitempty" validate:"omitempty,oneof=none low medium high urgent"`
+	TagIDs      *[]int  `json:"tag_ids,omitempty" validate:"omitempty,dive,min=1"` // [] removes all tags
+	ListID      *int    `json:"list_id,omitempty" validate:"omitempty,min=0"`      // 0 removes the todo from its list
+	// RecurrenceRule "" stops the todo from recurring
+	RecurrenceRule *string `json:"recurrence_rule,omitempty" validate:"omitempty,rrule"`
+}
+
+// UpdateTodoSeriesRequest edits every open occurrence of a recurring series
+type UpdateTodoSeriesRequest struct {
+	Title          *string `json:"title,omitempty" validate:"omitempty,min=3"`
+	Description    *string `json:"description,omitempty" validate:"omitempty,min=5"`
+	Priority       *string `json:"priority,omitempty" validate:"omitempty,oneof=none low medium high urgent"`
+	RecurrenceRule *string `json:"recurrence_rule,omitempty" validate:"omitempty,rrule"`
+}
+
+// QuickAddTodoRequest creates a todo from one line of text such as
+// "Pay rent every month on the 1st #finance !high"
+type QuickAddTodoRequest struct {
+	Text     string `json:"text" validate:"required,max=500"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone"` // dates in the text are read in this zone
+	ListID   *int   `json:"list_id,omitempty" validate:"omitempty,min=1"`
+	DryRun   bool   `json:"dry_run,omitempty"` // only parse; nothing is created
+}
+
+// DueDateLayout is the date-only form accepted for due_at.
+const DueDateLayout = "2006-01-02"
+
+// ParseDueDate accepts RFC 3339 timestamps or date-only values. A date-only
+// value means "by the end of that day" in loc.
+func ParseDueDate(value string, loc *time.Location) (time.Time, error) {
+	if dueAt, err := time.Parse(time.RFC3339, value); err == nil {
+		return dueAt, nil
+	}
+
+	day, err := time.ParseInLocation(DueDateLayout, value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+// Sort fields accepted by GET /todos.
+const (
+	TodoSortCreatedAt = "created_at"
+	TodoSortUpdatedAt = "updated_at"
+	TodoSortTitle     = "title"
+	TodoSortPosition  = "position"
+)
+
+// TodoListQuery carries the raw GET /todos query parameters.
+type TodoListQuery struct {
+	ListID     *int
+	AssigneeID *int // assigned_to; "me" is resolved by the handler
+	Completed  *bool
+	Status     string
+	Ready      bool
+	Priority   string
+	Tag        string
+	Query      string
+	Sort       string
+	Order      string
+	Limit      int
+	Cursor     string
+}
+
+// TodoCursor is the decoded keyset position of the last row on a page.
+type TodoCursor struct {
+	Value any
+	ID    int
+}
+
+// TodoFilter is the normalized list query handed to the repository.
+type TodoFilter struct {
+	ListID     *int
+	AssigneeID *int
+	Completed  *bool
+	Status     TodoStatus
+	Ready      bool // open with no open blockers
+	Priority   TodoPriority
+	Tag        string
+	Query      string
+	Sort       string
+	Desc       bool
+	Limit      int
+	After      *TodoCursor
+}
+
+// TodoPage is a single page of todos plus paging metadata.
+type TodoPage struct {
+	Todos         []Todo
+	NextCursor    string
+	HasMore       bool
+	TotalEstimate int64
+}
+
+// TodoBoardQuery carries the raw GET /todos/board query parameters.
+type TodoBoardQuery struct {
+	ListID *int
+	Limit  int // per column
+}
+
+// TodoBoard is the caller's todos grouped by status, one column per status
+// of the workflow in order.
+type TodoBoard struct {
+	Columns []TodoBoardColumn `json:"columns"`
+}
+
+// TodoBoardColumn holds the first todos of one status in manual order.
+type TodoBoardColumn struct {
+	Status TodoStatus   `json:"status"`
+	Next   []TodoStatus `json:"next"` // statuses its todos may move to
+	Total  int64        `json:"total"`
+	Todos  []Todo       `json:"todos"`
+}
+
+// AssignTodoRequest hands a todo to the owner or a member of its list
+type AssignTodoRequest struct {
+	UserID int `json:"user_id" validate:"required,min=1"`
+}
+
+// MoveTodoRequest places a todo between two neighbours in its list. Either
+// neighbour may be omitted to move it right after AfterID or right before BeforeID.
+type MoveTodoRequest struct {
+	AfterID  *int `json:"after_id" validate:"omitempty,min=1"`
+	BeforeID *int `json:"before_id" validate:"omitempty,min=1,required_without=AfterID"`
+}
+
+// BulkTodoOp is one kind of change a bulk request can apply
+type BulkTodoOp string
+
+const (
+	BulkOpComplete   BulkTodoOp = "complete"
+	BulkOpUncomplete BulkTodoOp = "uncomplete"
+	BulkOpUpdate     BulkTodoOp = "update"
+	BulkOpDelete     BulkTodoOp = "delete"
+)
+
+// BulkMode decides what happens to the batch when one item fails
+type BulkMode string
+
+const (
+	BulkModeAtomic     BulkMode = "atomic"      // any failure rolls back the whole batch
+	BulkModeBestEffort BulkMode = "best_effort" // failed items are rolled back on their own
+)
+
+// BulkTodoRequest is used by POST /todos/bulk
+type BulkTodoRequest struct {
+	Mode       string                   `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Operations []BulkTodoOperationInput `json:"operations" validate:"required,min=1,max=50,dive"`
+}
+
+// BulkTodoOperationInput applies one operation to every ID in IDs
+type BulkTodoOperationInput struct {
+	Op       string             `json:"op" validate:"required,oneof=complete uncomplete update delete"`
+	IDs      []int              `json:"ids" validate:"required,min=1,dive,min=1"`
+	Fields   *UpdateTodoRequest `json:"fields,omitempty" validate:"required_if=Op update"`
+	Subtasks string             `json:"subtasks,omitempty" validate:"omitempty,oneof=cascade promote"` // delete only
+}
+
+// BulkItemStatus is the outcome of one item in a bulk request
+type BulkItemStatus string
+
+const (
+	BulkItemOK         BulkItemStatus = "ok"
+	BulkItemFailed     BulkItemStatus = "failed"
+	BulkItemRolledBack BulkItemStatus = "rolled_back" // succeeded, then undone by an atomic failure
+	BulkItemSkipped    BulkItemStatus = "skipped"     // never attempted after an atomic failure
+)
+
+// BulkTodoItemResult reports what happened to one todo
+type BulkTodoItemResult struct {
+	Op     BulkTodoOp     `json:"op"`
+	ID     int            `json:"id"`
+	Status BulkItemStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	Todo   *Todo          `json:"todo,omitempty"`
+}
+
+// BulkTodoResult summarizes a bulk request
+type BulkTodoResult struct {
+	Mode      BulkMode             `json:"mode"`
+	Committed bool                 `json:"committed"`
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []BulkTodoItemResult `json:"results"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TodoAttachment describes a file uploaded to a todo; the bytes themselves
+// live in the attachment storage under StorageKey
+type TodoAttachment struct {
+	ID          int       `json:"id"`
+	TodoID      int       `json:"todo_id" gorm:"index"`
+	UserID      *int      `json:"user_id"` // uploader; nil once the account is deleted
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"` // sniffed from the content, not taken from the client
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-" gorm:"uniqueIndex"`
+	CreatedAt   time.Time `json:"created_at"`
+}
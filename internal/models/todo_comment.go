@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// TodoComment is a message on a todo, visible to everyone who can see the todo
+type TodoComment struct {
+	ID       int        `json:"id"`
+	TodoID   int        `json:"todo_id" gorm:"index"`
+	UserID   int        `json:"user_id"` // author taken from the JWT
+	User     *Author    `json:"author,omitempty"`
+	Body     string     `json:"body"`
+	EditedAt *time.Time `json:"edited_at,omitempty"` // last edit by the author
+	// CreatedAt orders the thread
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateTodoCommentRequest is used when commenting on a todo
+type CreateTodoCommentRequest struct {
+	Body string `json:"body" validate:"required,notblank,max=5000"`
+}
+
+// UpdateTodoCommentRequest replaces the text of a comment
+type UpdateTodoCommentRequest struct {
+	Body string `json:"body" validate:"required,notblank,max=5000"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TodoDependency records that TodoID can't be completed until BlockedByID is done
+type TodoDependency struct {
+	TodoID      int       `json:"todo_id" gorm:"primaryKey;autoIncrement:false"`
+	BlockedByID int       `json:"blocked_by_id" gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TodoDependencies is the response of GET /todos/:id/dependencies. Only
+// todos the caller can see are listed, but every open blocker counts
+// towards Ready.
+type TodoDependencies struct {
+	BlockedBy []Todo `json:"blocked_by"`
+	Blocks    []Todo `json:"blocks"`
+	Ready     bool   `json:"ready"` // no blocker is still open
+}
+
+// AddTodoDependencyRequest marks the todo as blocked by another one
+type AddTodoDependencyRequest struct {
+	BlockedByID int `json:"blocked_by_id" validate:"required,min=1"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// TodoEventAction is what happened to a todo in one history entry
+type TodoEventAction string
+
+const (
+	TodoEventCreated   TodoEventAction = "created"
+	TodoEventUpdated   TodoEventAction = "updated"
+	TodoEventCompleted TodoEventAction = "completed"
+	TodoEventReopened  TodoEventAction = "reopened"
+	TodoEventDeleted   TodoEventAction = "deleted"
+	TodoEventRestored  TodoEventAction = "restored"
+)
+
+// FieldChange holds one field's value before and after a change
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// TodoEvent is one entry of a todo's history, written with the change itself
+type TodoEvent struct {
+	ID      int                    `json:"id"`
+	TodoID  int                    `json:"todo_id" gorm:"index"`
+	UserID  *int                   `json:"user_id"` // acting user; nil once the account is deleted
+	User    *Author                `json:"user,omitempty"`
+	Action  TodoEventAction        `json:"action"`
+	Changes map[string]FieldChange `json:"changes,omitempty" gorm:"type:jsonb;serializer:json"` // keyed by JSON field name
+	// CreatedAt orders the history
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+// TodoImportFormat is a file format accepted by import and export
+type TodoImportFormat string
+
+const (
+	TodoFormatCSV  TodoImportFormat = "csv"
+	TodoFormatJSON TodoImportFormat = "json"
+)
+
+// TodoImportRow is one record read from an uploaded file. Err is set when
+// the record couldn't be parsed or failed CreateTodoRequest validation.
+type TodoImportRow struct {
+	Line    int
+	Request CreateTodoRequest
+	Err     error
+}
+
+// TodoImportStatus is the outcome of one imported row
+type TodoImportStatus string
+
+const (
+	TodoImportCreated TodoImportStatus = "created"
+	TodoImportValid   TodoImportStatus = "valid" // dry run: the row would be created
+	TodoImportFailed  TodoImportStatus = "failed"
+)
+
+// TodoImportRowResult reports one row of POST /todos/import
+type TodoImportRowResult struct {
+	Line   int              `json:"line"` // CSV line or JSON array index, starting at 1
+	Status TodoImportStatus `json:"status"`
+	Error  string           `json:"error,omitempty"`
+	Todo   *Todo            `json:"todo,omitempty"`
+}
+
+// TodoImportResult is the response of POST /todos/import
+type TodoImportResult struct {
+	DryRun  bool                  `json:"dry_run"`
+	Created int                   `json:"created"`
+	Valid   int                   `json:"valid"`
+	Failed  int                   `json:"failed"`
+	Rows    []TodoImportRowResult `json:"rows"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// ListRole is what a user may do with a list and the todos in it
+type ListRole string
+
+const (
+	ListRoleOwner  ListRole = "owner"
+	ListRoleEditor ListRole = "editor"
+	ListRoleViewer ListRole = "viewer"
+)
+
+// CanEdit reports whether the role may change the list's todos
+func (r ListRole) CanEdit() bool {
+	return r == ListRoleOwner || r == ListRoleEditor
+}
+
+// TodoList groups todos into a project or list
+type TodoList struct {
+	ID          int        `json:"id" db:"id"`
+	UserID      int        `json:"user_id" db:"user_id" gorm:"index"`
+	Name        string     `json:"name" db:"name"`
+	Description string     `json:"description" db:"description"`
+	Archived    bool       `json:"archived" db:"archived"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	OpenTodos   int64      `json:"open_todos" gorm:"->;-:migration"` // computed on read
+	Role        ListRole   `json:"role" gorm:"->;-:migration"`       // the caller's role, computed on read
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateTodoListRequest is used when creating a list
+type CreateTodoListRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// UpdateTodoListRequest is used when renaming or describing a list
+type UpdateTodoListRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+}
+
+// MoveTodosRequest moves todos out of one list; a nil target means "no list"
+type MoveTodosRequest struct {
+	TodoIDs      []int `json:"todo_ids" validate:"required,min=1,max=500,dive,min=1"`
+	TargetListID *int  `json:"target_list_id" validate:"omitempty,min=1"`
+}
+
+// ListMember grants a registered user access to someone else's list
+type ListMember struct {
+	ListID    int       `json:"list_id" db:"list_id" gorm:"primaryKey;autoIncrement:false"`
+	UserID    int       `json:"user_id" db:"user_id" gorm:"primaryKey;autoIncrement:false;index"`
+	Role      ListRole  `json:"role" db:"role"`
+	User      *Author   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AddListMemberRequest invites a registered user by email
+type AddListMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=viewer editor"`
+}
+
+// UpdateListMemberRequest changes a member's role
+type UpdateListMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=viewer editor"`
+}
@@ -0,0 +1,50 @@
+package models
+
+// Bucket sizes of the completion series in GET /todos/stats.
+const (
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+)
+
+// TodoStatsQuery carries the raw GET /todos/stats query parameters.
+type TodoStatsQuery struct {
+	Timezone string
+	From     string // YYYY-MM-DD, inclusive
+	To       string // YYYY-MM-DD, inclusive
+	Interval string
+}
+
+// TodoStatsFilter is the normalized stats query handed to the repository.
+// Dates are YYYY-MM-DD in Timezone; Today anchors the streak.
+type TodoStatsFilter struct {
+	Timezone string
+	From     string
+	To       string
+	Today    string
+	Interval string
+}
+
+// TodoStats summarizes the caller's own todos. A todo counts as completed
+// on the day it was last updated, since completion has no timestamp of its own.
+type TodoStats struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Timezone string `json:"timezone"`
+	Interval string `json:"interval"`
+	// Open and Done split the todos created within the range.
+	Open int64 `json:"open"`
+	Done int64 `json:"done"`
+	// Completed has one bucket per day or week of the range, empty ones included.
+	Completed            []TodoStatsBucket `json:"completed"`
+	AvgCompletionSeconds *float64          `json:"avg_completion_seconds"` // null when nothing was completed
+	// CurrentStreak counts consecutive days with a completion, ending today
+	// or, if nothing is done yet today, yesterday. It ignores the range.
+	CurrentStreak int `json:"current_streak_days"`
+}
+
+// TodoStatsBucket is the number of todos completed in the day or week
+// starting on Start.
+type TodoStatsBucket struct {
+	Start string `json:"start"`
+	Count int64  `json:"count"`
+}
@@ -13,6 +13,17 @@ type User struct {
 	UpdatedAt time.Time `json:"updated_at"`                               // auto timestamp
 }
 
+// Author is the part of a user shown to the other people on a todo or
+// list: enough to tell who did something, without contact details.
+type Author struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (Author) TableName() string {
+	return "users"
+}
+
 // Request struct for registration
 type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=2,max=100"`
@@ -0,0 +1,588 @@
+// Package quickadd parses one-line todo entries such as
+// "Pay rent every month on the 1st #finance !high" into a title and the
+// fields written inline: a due date and time, a recurrence rule, #tags and
+// a !priority. Parsing never fails; words that aren't understood stay in
+// the title, and every recognized phrase is returned as a token so a client
+// can preview what was picked up.
+package quickadd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/recurrence"
+)
+
+// Kind says what a token was read as.
+type Kind string
+
+const (
+	KindDate       Kind = "date"
+	KindTime       Kind = "time"
+	KindRecurrence Kind = "recurrence"
+	KindTag        Kind = "tag"
+	KindPriority   Kind = "priority"
+)
+
+// MaxTagLength matches the longest tag name the API accepts.
+const MaxTagLength = 50
+
+// Token is a recognized phrase. Start and End are byte offsets into the
+// input; Value is the normalized meaning (YYYY-MM-DD, HH:MM, an RRULE, a
+// tag name or a priority).
+type Token struct {
+	Kind  Kind   `json:"kind"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Value string `json:"value"`
+}
+
+// Result is a parsed entry. When AllDay is set, Due is midnight of the due
+// date and the todo is due by the end of that day.
+type Result struct {
+	Title      string     `json:"title"`
+	Due        *time.Time `json:"due_at,omitempty"`
+	AllDay     bool       `json:"all_day,omitempty"`
+	Recurrence string     `json:"recurrence_rule,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	Priority   string     `json:"priority,omitempty"`
+	Tokens     []Token    `json:"tokens"`
+}
+
+var priorities = map[string]string{
+	"!low":    "low",
+	"!medium": "medium",
+	"!med":    "medium",
+	"!high":   "high",
+	"!urgent": "urgent",
+}
+
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var frequencies = map[string]recurrence.Frequency{
+	"day": recurrence.Daily, "days": recurrence.Daily,
+	"week": recurrence.Weekly, "weeks": recurrence.Weekly,
+	"month": recurrence.Monthly, "months": recurrence.Monthly,
+}
+
+// word is a whitespace-separated piece of the input. norm is lowercased
+// with trailing punctuation removed and is what the matchers look at.
+type word struct {
+	text       string
+	norm       string
+	start, end int
+}
+
+type parser struct {
+	text   string
+	now    time.Time
+	words  []word
+	result Result
+
+	date  *time.Time // midnight of the due date in now's location
+	clock *[2]int    // hour and minute
+	rule  *recurrence.Rule
+}
+
+// Parse reads text relative to now; dates are resolved in now's location.
+// Only the first date, time, recurrence and priority are used; later ones
+// are left in the title.
+func Parse(text string, now time.Time) Result {
+	p := &parser{text: text, now: now, words: split(text)}
+	var title []string
+	for i := 0; i < len(p.words); {
+		if n := p.match(i); n > 0 {
+			i += n
+			continue
+		}
+		title = append(title, p.words[i].text)
+		i++
+	}
+	p.result.Title = strings.Join(title, " ")
+	if p.rule != nil {
+		p.result.Recurrence = p.rule.String()
+	}
+	p.resolveDue()
+	if p.result.Tokens == nil {
+		p.result.Tokens = []Token{}
+	}
+	return p.result
+}
+
+func split(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text + " " {
+		space := r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		switch {
+		case !space && start < 0:
+			start = i
+		case space && start >= 0:
+			raw := text[start:i]
+			words = append(words, word{
+				text:  raw,
+				norm:  strings.TrimRight(strings.ToLower(raw), ",.;:?"),
+				start: start,
+				end:   i,
+			})
+			start = -1
+		}
+	}
+	return words
+}
+
+// norm returns the normalized word at i, or "" past the end.
+func (p *parser) norm(i int) string {
+	if i < 0 || i >= len(p.words) {
+		return ""
+	}
+	return p.words[i].norm
+}
+
+func (p *parser) match(i int) int {
+	for _, matcher := range []func(int) int{p.matchTag, p.matchPriority, p.matchRecurrence, p.matchDate, p.matchTime} {
+		if n := matcher(i); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// token records words[i:i+n] as a token and returns n.
+func (p *parser) token(kind Kind, i, n int, value string) int {
+	start, end := p.words[i].start, p.words[i+n-1].end
+	p.result.Tokens = append(p.result.Tokens, Token{
+		Kind:  kind,
+		Text:  p.text[start:end],
+		Start: start,
+		End:   end,
+		Value: value,
+	})
+	return n
+}
+
+func (p *parser) matchTag(i int) int {
+	raw := p.words[i].text
+	if !strings.HasPrefix(raw, "#") {
+		return 0
+	}
+	name := strings.TrimRight(raw[1:], ",.;:?!")
+	if name == "" || strings.Contains(name, "#") || len(name) > MaxTagLength {
+		return 0
+	}
+	for _, tag := range p.result.Tags {
+		if strings.EqualFold(tag, name) {
+			return p.token(KindTag, i, 1, tag)
+		}
+	}
+	p.result.Tags = append(p.result.Tags, name)
+	return p.token(KindTag, i, 1, name)
+}
+
+func (p *parser) matchPriority(i int) int {
+	priority, ok := priorities[p.norm(i)]
+	if !ok || p.result.Priority != "" {
+		return 0
+	}
+	p.result.Priority = priority
+	return p.token(KindPriority, i, 1, priority)
+}
+
+// matchRecurrence reads "daily", "weekly", "monthly", "every day|week|month",
+// "every other week", "every 3 days", "every weekday" and weekday lists
+// such as "every mon, wed and fri".
+func (p *parser) matchRecurrence(i int) int {
+	if p.rule != nil {
+		return 0
+	}
+	rule := &recurrence.Rule{Interval: 1}
+	n := 0
+	switch p.norm(i) {
+	case "daily":
+		rule.Freq, n = recurrence.Daily, 1
+	case "weekly":
+		rule.Freq, n = recurrence.Weekly, 1
+	case "monthly":
+		rule.Freq, n = recurrence.Monthly, 1
+	case "every":
+		n = p.every(i+1, rule)
+		if n == 0 {
+			return 0
+		}
+		n++
+	default:
+		return 0
+	}
+	p.rule = rule
+	return p.token(KindRecurrence, i, n, rule.String())
+}
+
+// every parses what follows "every" into rule and returns the words used.
+func (p *parser) every(i int, rule *recurrence.Rule) int {
+	next := p.norm(i)
+	if freq, ok := frequencies[next]; ok {
+		rule.Freq = freq
+		return 1
+	}
+	if next == "other" {
+		if freq, ok := frequencies[p.norm(i+1)]; ok {
+			rule.Freq, rule.Interval = freq, 2
+			return 2
+		}
+		return 0
+	}
+	if interval, err := strconv.Atoi(next); err == nil && interval > 0 {
+		if freq, ok := frequencies[p.norm(i+1)]; ok {
+			rule.Freq, rule.Interval = freq, interval
+			return 2
+		}
+		return 0
+	}
+	if next == "weekday" || next == "weekdays" {
+		rule.Freq = recurrence.Weekly
+		rule.ByDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return 1
+	}
+
+	// A list of weekdays joined by commas and "and".
+	n := 0
+	for {
+		days, ok := weekdayList(p.norm(i + n))
+		if !ok {
+			break
+		}
+		rule.ByDay = append(rule.ByDay, days...)
+		n++
+		if p.norm(i+n) == "and" {
+			if _, ok := weekdayList(p.norm(i + n + 1)); ok {
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	rule.Freq = recurrence.Weekly
+	// Round-trip through the RRULE parser to sort and de-duplicate the days.
+	if canonical, err := recurrence.Parse(rule.String()); err == nil {
+		*rule = *canonical
+	}
+	return n
+}
+
+// weekdayList reads "mon", "mondays" or "mon,wed".
+func weekdayList(s string) ([]time.Weekday, bool) {
+	var days []time.Weekday
+	for _, part := range strings.Split(s, ",") {
+		if part == "" {
+			continue
+		}
+		day, ok := weekdays[part]
+		if !ok {
+			day, ok = weekdays[strings.TrimSuffix(part, "s")]
+		}
+		if !ok {
+			return nil, false
+		}
+		days = append(days, day)
+	}
+	return days, len(days) > 0
+}
+
+// matchDate reads a due date, optionally led by "on", "by" or "due".
+func (p *parser) matchDate(i int) int {
+	if p.date != nil {
+		return 0
+	}
+	j := i
+	switch p.norm(j) {
+	case "due":
+		j++
+		if p.norm(j) == "on" || p.norm(j) == "by" {
+			j++
+		}
+	case "on", "by":
+		j++
+	}
+	date, n := p.parseDate(j, j > i)
+	if n == 0 {
+		return 0
+	}
+	p.date = &date
+	return p.token(KindDate, i, j-i+n, date.Format(time.DateOnly))
+}
+
+// parseDate returns the date at words[i:] and how many words it took.
+// Abbreviated weekdays and bare ordinals ("the 1st") need a leading word
+// so that titles like "Enjoy the sun" stay intact.
+func (p *parser) parseDate(i int, led bool) (time.Time, int) {
+	today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+	first := p.norm(i)
+
+	switch first {
+	case "today":
+		return today, 1
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), 1
+	case "next":
+		switch second := p.norm(i + 1); second {
+		case "week":
+			return nextWeekday(today, time.Monday), 2
+		case "month":
+			return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), 2
+		default:
+			if day, ok := weekdays[second]; ok {
+				return nextWeekday(today, day), 2
+			}
+		}
+		return time.Time{}, 0
+	case "in":
+		if led {
+			return time.Time{}, 0
+		}
+		count, ok := countWord(p.norm(i + 1))
+		if !ok {
+			return time.Time{}, 0
+		}
+		switch frequencies[p.norm(i+2)] {
+		case recurrence.Daily:
+			return today.AddDate(0, 0, count), 3
+		case recurrence.Weekly:
+			return today.AddDate(0, 0, 7*count), 3
+		case recurrence.Monthly:
+			return today.AddDate(0, count, 0), 3
+		}
+		return time.Time{}, 0
+	case "the":
+		if day, ok := ordinal(p.norm(i + 1)); ok && led {
+			if date, ok := nextMonthDay(today, day); ok {
+				return date, 2
+			}
+		}
+		return time.Time{}, 0
+	}
+
+	if day, ok := weekdays[first]; ok && (led || first == strings.ToLower(day.String())) {
+		return nextWeekday(today, day), 1
+	}
+	if date, err := time.ParseInLocation(time.DateOnly, first, today.Location()); err == nil {
+		return date, 1
+	}
+	if month, ok := months[first]; ok {
+		if day, ok := dayNumber(p.norm(i + 1)); ok {
+			if date, ok := nextDate(today, month, day); ok {
+				return date, 2
+			}
+		}
+	}
+	if day, ok := dayNumber(first); ok {
+		if month, ok := months[p.norm(i+1)]; ok {
+			if date, ok := nextDate(today, month, day); ok {
+				return date, 2
+			}
+		}
+		if _, isOrdinal := ordinal(first); isOrdinal && led {
+			if date, ok := nextMonthDay(today, day); ok {
+				return date, 1
+			}
+		}
+	}
+	return time.Time{}, 0
+}
+
+// nextWeekday returns the first day after today that falls on day.
+func nextWeekday(today time.Time, day time.Weekday) time.Time {
+	days := (int(day)-int(today.Weekday())+6)%7 + 1
+	return today.AddDate(0, 0, days)
+}
+
+// nextMonthDay returns the next date, today included, on that day of the
+// month, skipping months that are too short.
+func nextMonthDay(today time.Time, day int) (time.Time, bool) {
+	for step := range 12 {
+		date := time.Date(today.Year(), today.Month()+time.Month(step), day, 0, 0, 0, 0, today.Location())
+		if date.Day() == day && !date.Before(today) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextDate returns month/day this year, or next year once it has passed.
+func nextDate(today time.Time, month time.Month, day int) (time.Time, bool) {
+	for year := today.Year(); year <= today.Year()+4; year++ {
+		date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+		if date.Day() == day && !date.Before(today) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func countWord(s string) (int, bool) {
+	if s == "a" || s == "an" || s == "one" {
+		return 1, true
+	}
+	count, err := strconv.Atoi(s)
+	return count, err == nil && count > 0
+}
+
+// ordinal reads "1st", "22nd", "3rd" or "15th".
+func ordinal(s string) (int, bool) {
+	if len(s) < 3 {
+		return 0, false
+	}
+	switch s[len(s)-2:] {
+	case "st", "nd", "rd", "th":
+	default:
+		return 0, false
+	}
+	day, err := strconv.Atoi(s[:len(s)-2])
+	return day, err == nil && day >= 1 && day <= 31
+}
+
+// dayNumber reads a day of the month written as "7" or "7th".
+func dayNumber(s string) (int, bool) {
+	if day, ok := ordinal(s); ok {
+		return day, true
+	}
+	day, err := strconv.Atoi(s)
+	return day, err == nil && day >= 1 && day <= 31
+}
+
+// matchTime reads "5pm", "5:30 pm", "17:30", "noon" or "midnight",
+// optionally led by "at". A bare hour ("at 5") is too ambiguous to use.
+func (p *parser) matchTime(i int) int {
+	if p.clock != nil {
+		return 0
+	}
+	j := i
+	if p.norm(j) == "at" || p.norm(j) == "@" {
+		j++
+	}
+	clock, n := parseClock(p.norm(j), p.norm(j+1))
+	if n == 0 {
+		return 0
+	}
+	p.clock = &clock
+	return p.token(KindTime, i, j-i+n, fmt.Sprintf("%02d:%02d", clock[0], clock[1]))
+}
+
+// parseClock reads a time from word, or from word and a following "am"/"pm".
+func parseClock(word, next string) ([2]int, int) {
+	switch word {
+	case "noon":
+		return [2]int{12, 0}, 1
+	case "midnight":
+		return [2]int{0, 0}, 1
+	}
+
+	n := 1
+	suffix := ""
+	switch {
+	case strings.HasSuffix(word, "am"), strings.HasSuffix(word, "pm"):
+		word, suffix = word[:len(word)-2], word[len(word)-2:]
+	case next == "am" || next == "pm":
+		suffix, n = next, 2
+	}
+
+	hourText, minuteText, hasMinutes := strings.Cut(word, ":")
+	if !hasMinutes && suffix == "" {
+		return [2]int{}, 0
+	}
+	hour, err := strconv.Atoi(hourText)
+	if err != nil || len(hourText) > 2 {
+		return [2]int{}, 0
+	}
+	minute := 0
+	if hasMinutes {
+		if len(minuteText) != 2 {
+			return [2]int{}, 0
+		}
+		if minute, err = strconv.Atoi(minuteText); err != nil || minute > 59 {
+			return [2]int{}, 0
+		}
+	}
+	switch suffix {
+	case "":
+		if hour > 23 {
+			return [2]int{}, 0
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return [2]int{}, 0
+		}
+		hour %= 12
+		if suffix == "pm" {
+			hour += 12
+		}
+	}
+	return [2]int{hour, minute}, n
+}
+
+// resolveDue combines the date and time. A time without a date means the
+// next time that clock comes round, and a weekday rule without a date
+// starts on its first matching day.
+func (p *parser) resolveDue() {
+	if p.date == nil && p.clock == nil && (p.rule == nil || len(p.rule.ByDay) == 0) {
+		return
+	}
+
+	day := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+	if p.date != nil {
+		day = *p.date
+	} else {
+		for range 8 {
+			if p.fits(day) {
+				break
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+
+	due := day
+	if p.clock != nil {
+		due = time.Date(day.Year(), day.Month(), day.Day(), p.clock[0], p.clock[1], 0, 0, day.Location())
+	}
+	p.result.Due = &due
+	p.result.AllDay = p.clock == nil
+}
+
+// fits reports whether day can be the first due date when none was given.
+func (p *parser) fits(day time.Time) bool {
+	if p.rule != nil && len(p.rule.ByDay) > 0 && !slices.Contains(p.rule.ByDay, day.Weekday()) {
+		return false
+	}
+	if p.clock != nil {
+		at := time.Date(day.Year(), day.Month(), day.Day(), p.clock[0], p.clock[1], 0, 0, day.Location())
+		return at.After(p.now)
+	}
+	return true
+}
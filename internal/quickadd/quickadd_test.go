@@ -0,0 +1,140 @@
+package quickadd
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Friday, 16 October 2026, 10:00.
+	now := time.Date(2026, time.October, 16, 10, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) string {
+		return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		title      string
+		due        string // YYYY-MM-DD, or YYYY-MM-DD HH:MM for timed todos
+		recurrence string
+		tags       []string
+		priority   string
+		kinds      []Kind
+	}{
+		{
+			name:       "example",
+			input:      "Pay rent every month on the 1st #finance !high",
+			title:      "Pay rent",
+			due:        day(time.November, 1),
+			recurrence: "FREQ=MONTHLY",
+			tags:       []string{"finance"},
+			priority:   "high",
+			kinds:      []Kind{KindRecurrence, KindDate, KindTag, KindPriority},
+		},
+		{name: "plain title", input: "Water the plants", title: "Water the plants"},
+		{name: "tomorrow with time", input: "Call mom tomorrow at 5pm", title: "Call mom", due: day(time.October, 17) + " 17:00", kinds: []Kind{KindDate, KindTime}},
+		{name: "weekday is the next one", input: "Submit report friday", title: "Submit report", due: day(time.October, 23), kinds: []Kind{KindDate}},
+		{name: "abbreviated weekday needs on", input: "Enjoy the sun", title: "Enjoy the sun"},
+		{name: "on abbreviated weekday", input: "Dentist on tue at 9:30am", title: "Dentist", due: day(time.October, 20) + " 09:30", kinds: []Kind{KindDate, KindTime}},
+		{name: "next week", input: "Plan sprint next week", title: "Plan sprint", due: day(time.October, 19), kinds: []Kind{KindDate}},
+		{name: "in days", input: "Renew passport in 10 days", title: "Renew passport", due: day(time.October, 26), kinds: []Kind{KindDate}},
+		{name: "in a week", input: "Follow up in a week", title: "Follow up", due: day(time.October, 23), kinds: []Kind{KindDate}},
+		{name: "iso date", input: "File taxes by 2027-04-15", title: "File taxes", due: "2027-04-15", kinds: []Kind{KindDate}},
+		{name: "month and day", input: "Buy gift dec 24th", title: "Buy gift", due: day(time.December, 24), kinds: []Kind{KindDate}},
+		{name: "passed date rolls to next year", input: "Book flights 3 march", title: "Book flights", due: "2027-03-03", kinds: []Kind{KindDate}},
+		{name: "ordinal today", input: "Send invoice due on the 16th", title: "Send invoice", due: day(time.October, 16), kinds: []Kind{KindDate}},
+		{name: "ordinal skips short months", input: "Pay card on the 31st", title: "Pay card", due: day(time.October, 31), kinds: []Kind{KindDate}},
+		{name: "passed time is tomorrow", input: "Stand-up 9am", title: "Stand-up", due: day(time.October, 17) + " 09:00", kinds: []Kind{KindTime}},
+		{name: "later time is today", input: "Lunch at noon", title: "Lunch", due: day(time.October, 16) + " 12:00", kinds: []Kind{KindTime}},
+		{name: "24-hour time", input: "Deploy at 17:30", title: "Deploy", due: day(time.October, 16) + " 17:30", kinds: []Kind{KindTime}},
+		{name: "bare hour stays", input: "Meet at 5", title: "Meet at 5"},
+		{name: "daily", input: "Stretch daily", title: "Stretch", recurrence: "FREQ=DAILY", kinds: []Kind{KindRecurrence}},
+		{name: "every other week", input: "Clean fridge every other week", title: "Clean fridge", recurrence: "FREQ=WEEKLY;INTERVAL=2", kinds: []Kind{KindRecurrence}},
+		{name: "every n days", input: "Water cactus every 3 days", title: "Water cactus", recurrence: "FREQ=DAILY;INTERVAL=3", kinds: []Kind{KindRecurrence}},
+		{
+			name:       "weekday list starts on first match",
+			input:      "Gym every wed, mon and fri at 7am",
+			title:      "Gym",
+			due:        day(time.October, 19) + " 07:00",
+			recurrence: "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			kinds:      []Kind{KindRecurrence, KindTime},
+		},
+		{name: "every weekday", input: "Check inbox every weekday", title: "Check inbox", due: day(time.October, 16), recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", kinds: []Kind{KindRecurrence}},
+		{name: "second date stays in title", input: "Move meeting from monday to tuesday", title: "Move meeting from to tuesday", due: day(time.October, 19), kinds: []Kind{KindDate}},
+		{name: "tags deduplicated", input: "Read #Books #books #reading, later", title: "Read later", tags: []string{"Books", "reading"}, kinds: []Kind{KindTag, KindTag, KindTag}},
+		{name: "unknown priority stays", input: "Fix bug !asap !urgent", title: "Fix bug !asap", priority: "urgent", kinds: []Kind{KindPriority}},
+		{name: "lead word without date stays", input: "Turn on lights", title: "Turn on lights"},
+		{name: "only tokens", input: "tomorrow #home", due: day(time.October, 17), tags: []string{"home"}, kinds: []Kind{KindDate, KindTag}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.input, now)
+			if got.Title != tt.title {
+				t.Fatalf("Parse(%q).Title = %q, want %q", tt.input, got.Title, tt.title)
+			}
+			due := ""
+			if got.Due != nil {
+				due = got.Due.Format(time.DateOnly)
+				if !got.AllDay {
+					due = got.Due.Format("2006-01-02 15:04")
+				}
+			}
+			if due != tt.due {
+				t.Fatalf("Parse(%q) due = %q, want %q", tt.input, due, tt.due)
+			}
+			if got.Recurrence != tt.recurrence {
+				t.Fatalf("Parse(%q).Recurrence = %q, want %q", tt.input, got.Recurrence, tt.recurrence)
+			}
+			if !slices.Equal(got.Tags, tt.tags) {
+				t.Fatalf("Parse(%q).Tags = %q, want %q", tt.input, got.Tags, tt.tags)
+			}
+			if got.Priority != tt.priority {
+				t.Fatalf("Parse(%q).Priority = %q, want %q", tt.input, got.Priority, tt.priority)
+			}
+			kinds := make([]Kind, len(got.Tokens))
+			for i, token := range got.Tokens {
+				kinds[i] = token.Kind
+				if tt.input[token.Start:token.End] != token.Text {
+					t.Fatalf("token %+v does not match input[%d:%d]", token, token.Start, token.End)
+				}
+			}
+			if len(kinds) == 0 {
+				kinds = nil
+			}
+			if !slices.Equal(kinds, tt.kinds) {
+				t.Fatalf("Parse(%q) token kinds = %v, want %v", tt.input, kinds, tt.kinds)
+			}
+		})
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 10, 0, 0, 0, time.UTC)
+	got := Parse("Pay rent  every month on the 1st #finance !high", now)
+	want := []Token{
+		{Kind: KindRecurrence, Text: "every month", Start: 10, End: 21, Value: "FREQ=MONTHLY"},
+		{Kind: KindDate, Text: "on the 1st", Start: 22, End: 32, Value: "2026-11-01"},
+		{Kind: KindTag, Text: "#finance", Start: 33, End: 41, Value: "finance"},
+		{Kind: KindPriority, Text: "!high", Start: 42, End: 47, Value: "high"},
+	}
+	if !slices.Equal(got.Tokens, want) {
+		t.Fatalf("Parse().Tokens = %+v, want %+v", got.Tokens, want)
+	}
+}
+
+func TestParseUsesLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	// 23:30 UTC on the 16th is already the 17th in Tokyo.
+	now := time.Date(2026, time.October, 16, 23, 30, 0, 0, time.UTC).In(tokyo)
+	got := Parse("Breakfast tomorrow at 8am", now)
+	want := time.Date(2026, time.October, 18, 8, 0, 0, 0, tokyo)
+	if got.Due == nil || !got.Due.Equal(want) || got.Due.Location() != tokyo {
+		t.Fatalf("Parse().Due = %v, want %v", got.Due, want)
+	}
+}
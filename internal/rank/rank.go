@@ -0,0 +1,108 @@
+// Package rank generates lexicographic sort keys for manually ordered items.
+// A key is a base-62 fraction written with the digits 0-9A-Za-z, so keys
+// compare correctly as plain bytes (COLLATE "C"). There is always room for a
+// key between two others, so moving an item only rewrites that item's key.
+package rank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// digits are in ascending byte order.
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrInvalidKey is returned for keys that contain other characters or end in
+// the zero digit, which would leave no room in front of them.
+var ErrInvalidKey = errors.New("invalid rank key")
+
+// ErrOutOfOrder is returned when the lower bound doesn't sort before the upper one.
+var ErrOutOfOrder = errors.New("rank keys out of order")
+
+// Between returns a key that sorts strictly after a and before b. An empty a
+// means "before everything" and an empty b "after everything", so
+// Between("", "") gives a first key and Between(last, "") appends.
+func Between(a, b string) (string, error) {
+	if err := validate(a); err != nil {
+		return "", err
+	}
+	if err := validate(b); err != nil {
+		return "", err
+	}
+	if a != "" && b != "" && a >= b {
+		return "", fmt.Errorf("%w: %q is not before %q", ErrOutOfOrder, a, b)
+	}
+
+	if b == "" {
+		return after(a), nil
+	}
+	return midpoint(a, b), nil
+}
+
+// after bumps the first digit that can still grow, which keeps appended keys
+// short instead of halving the remaining space every time.
+func after(a string) string {
+	for i := 0; i < len(a); i++ {
+		if d := strings.IndexByte(digits, a[i]); d < len(digits)-1 {
+			return a[:i] + string(digits[d+1])
+		}
+	}
+	return a + string(digits[len(digits)/2])
+}
+
+// midpoint returns a key between a and b, where a < b and a may be empty.
+// An empty b stands for the upper end of the key space.
+func midpoint(a, b string) string {
+	// Keys equal up to some point share that prefix; a is padded with zeros.
+	n := 0
+	for n < len(b) && digitAt(a, n) == b[n] {
+		n++
+	}
+	if n > 0 {
+		return b[:n] + midpoint(tail(a, n), b[n:])
+	}
+
+	da := strings.IndexByte(digits, digitAt(a, 0))
+	db := len(digits)
+	if b != "" {
+		db = strings.IndexByte(digits, b[0])
+	}
+	if db-da > 1 {
+		return string(digits[(da+db)/2])
+	}
+
+	// The first digits are adjacent. b's first digit alone still sorts after a
+	// and, being shorter, before b.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	// Otherwise keep a's first digit and find room after the rest of a.
+	return string(digits[da]) + midpoint(tail(a, 1), "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+func tail(s string, n int) string {
+	if n >= len(s) {
+		return ""
+	}
+	return s[n:]
+}
+
+func validate(key string) error {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	if strings.HasSuffix(key, digits[:1]) {
+		return fmt.Errorf("%w: %q ends in %q", ErrInvalidKey, key, digits[:1])
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package rank
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		want    string
+		wantErr error
+	}{
+		{name: "first key", a: "", b: "", want: "V"},
+		{name: "append", a: "V", b: "", want: "W"},
+		{name: "append after max digit", a: "z", b: "", want: "zV"},
+		{name: "append to backfilled key", a: "0000000007V", b: "", want: "1"},
+		{name: "prepend", a: "", b: "V", want: "F"},
+		{name: "prepend before smallest digit", a: "", b: "1", want: "0V"},
+		{name: "middle", a: "A", b: "C", want: "B"},
+		{name: "adjacent digits", a: "A", b: "B", want: "AV"},
+		{name: "adjacent with longer upper", a: "A", b: "BV", want: "B"},
+		{name: "shared prefix", a: "AB", b: "AD", want: "AC"},
+		{name: "lower is prefix of upper", a: "A", b: "AV", want: "AF"},
+		{name: "out of order", a: "C", b: "A", wantErr: ErrOutOfOrder},
+		{name: "equal", a: "C", b: "C", wantErr: ErrOutOfOrder},
+		{name: "trailing zero", a: "A0", b: "", wantErr: ErrInvalidKey},
+		{name: "bad character", a: "A-", b: "", wantErr: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Between(%q, %q) error = %v, want %v", tt.a, tt.b, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Between(%q, %q) error = %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Between(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+			assertBetween(t, tt.a, got, tt.b)
+		})
+	}
+}
+
+// TestBetweenRepeatedInserts drags items into the same gap over and over,
+// the worst case for key length.
+func TestBetweenRepeatedInserts(t *testing.T) {
+	low, high := "A", "B"
+	for range 200 {
+		key, err := Between(low, high)
+		if err != nil {
+			t.Fatalf("Between(%q, %q) error = %v", low, high, err)
+		}
+		assertBetween(t, low, key, high)
+		high = key
+	}
+
+	keys := []string{""}
+	for range 500 {
+		key, err := Between(keys[len(keys)-1], "")
+		if err != nil {
+			t.Fatalf("Between(%q, \"\") error = %v", keys[len(keys)-1], err)
+		}
+		assertBetween(t, keys[len(keys)-1], key, "")
+		keys = append(keys, key)
+	}
+	if last := keys[len(keys)-1]; len(last) > 20 {
+		t.Fatalf("appending 500 keys grew them to %d characters (%q)", len(last), last)
+	}
+}
+
+func assertBetween(t *testing.T, a, key, b string) {
+	t.Helper()
+	if validate(key) != nil || key == "" {
+		t.Fatalf("key %q is not a valid rank key", key)
+	}
+	if a != "" && key <= a {
+		t.Fatalf("key %q does not sort after %q", key, a)
+	}
+	if b != "" && key >= b {
+		t.Fatalf("key %q does not sort before %q", key, b)
+	}
+	if strings.HasSuffix(key, "0") {
+		t.Fatalf("key %q ends in the zero digit", key)
+	}
+}
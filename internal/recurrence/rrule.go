@@ -0,0 +1,217 @@
+// Package recurrence implements the subset of RFC 5545 RRULEs used by
+// recurring todos: FREQ=DAILY|WEEKLY|MONTHLY with INTERVAL, BYDAY, COUNT and UNTIL.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the RRULE FREQ part.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// ErrInvalidRule is wrapped by every Parse error.
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// searchLimit bounds the candidate scan in Next; no valid rule needs more.
+const searchLimit = 1000
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a parsed recurrence rule.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday
+	Count    int        // total occurrences in the series, 0 when unbounded
+	Until    *time.Time // last allowed occurrence, inclusive
+}
+
+// Parse reads an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE".
+// A leading "RRULE:" is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, arg, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		arg = strings.ToUpper(strings.TrimSpace(arg))
+		if !ok || arg == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s given twice", ErrInvalidRule, name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			switch Frequency(arg) {
+			case Daily, Weekly, Monthly:
+				rule.Freq = Frequency(arg)
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, arg)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(arg)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalidRule)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(arg)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalidRule)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(arg)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(arg, ",") {
+				day, ok := weekdayCodes[code]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY value %q", ErrInvalidRule, code)
+				}
+				if !slices.Contains(rule.ByDay, day) {
+					rule.ByDay = append(rule.ByDay, day)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	if rule.Freq == Monthly && len(rule.ByDay) > 0 {
+		return nil, fmt.Errorf("%w: BYDAY is not supported with FREQ=MONTHLY", ErrInvalidRule)
+	}
+	slices.Sort(rule.ByDay)
+
+	return rule, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	// A date-only UNTIL includes the whole day.
+	if day, err := time.Parse("20060102", value); err == nil {
+		return day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ", ErrInvalidRule)
+}
+
+// String renders the rule in canonical RRULE form (without the "RRULE:" prefix).
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			codes = append(codes, strings.ToUpper(day.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence after prev, where prev is occurrence number
+// `occurrence` (1-based) of the series. It reports false once COUNT or
+// UNTIL ends the series. Wall-clock time and location are kept from prev.
+func (r *Rule) Next(prev time.Time, occurrence int) (time.Time, bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	next, ok := r.next(prev)
+	if !ok || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r *Rule) next(prev time.Time) (time.Time, bool) {
+	switch r.Freq {
+	case Daily:
+		for step := 1; step <= searchLimit; step++ {
+			candidate := prev.AddDate(0, 0, step*r.Interval)
+			if r.matchesDay(candidate) {
+				return candidate, true
+			}
+		}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return prev.AddDate(0, 0, 7*r.Interval), true
+		}
+		// Weeks start on Monday (RFC 5545 default WKST); only every
+		// INTERVAL-th week counted from prev's week is eligible.
+		anchor := weekStart(prev)
+		for step := 1; step <= searchLimit; step++ {
+			candidate := prev.AddDate(0, 0, step)
+			weeks := int(weekStart(candidate).Sub(anchor).Hours()+12) / (7 * 24)
+			if weeks%r.Interval == 0 && r.matchesDay(candidate) {
+				return candidate, true
+			}
+		}
+	case Monthly:
+		// Months without prev's day of month are skipped, as RFC 5545 requires.
+		for step := 1; step <= searchLimit; step++ {
+			candidate := time.Date(prev.Year(), prev.Month()+time.Month(step*r.Interval), prev.Day(),
+				prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+			if candidate.Day() == prev.Day() {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) matchesDay(t time.Time) bool {
+	return len(r.ByDay) == 0 || slices.Contains(r.ByDay, t.Weekday())
+}
+
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Monday = 0
+	day := t.AddDate(0, 0, -offset)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+}
@@ -0,0 +1,132 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "daily", input: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "prefix and lowercase", input: "RRULE:freq=weekly;byday=we,mo", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "interval and count", input: "FREQ=MONTHLY;INTERVAL=3;COUNT=4", want: "FREQ=MONTHLY;INTERVAL=3;COUNT=4"},
+		{name: "date-only until", input: "FREQ=DAILY;UNTIL=20261231", want: "FREQ=DAILY;UNTIL=20261231T235959Z"},
+		{name: "missing freq", input: "INTERVAL=2", wantErr: true},
+		{name: "unsupported freq", input: "FREQ=YEARLY", wantErr: true},
+		{name: "zero interval", input: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "count with until", input: "FREQ=DAILY;COUNT=2;UNTIL=20261231", wantErr: true},
+		{name: "ordinal byday", input: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{name: "monthly byday", input: "FREQ=MONTHLY;BYDAY=MO", wantErr: true},
+		{name: "unknown part", input: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{name: "duplicate part", input: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRule) {
+					t.Fatalf("Parse(%q) expected ErrInvalidRule, got %v", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Fatalf("Parse(%q).String() = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleNext(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time // consecutive occurrences after start; series ends after the last one
+		ends  bool
+	}{
+		{
+			name:  "every other day",
+			rule:  "FREQ=DAILY;INTERVAL=2",
+			start: date(2026, 1, 30),
+			want:  []time.Time{date(2026, 2, 1), date(2026, 2, 3)},
+		},
+		{
+			name:  "weekdays only",
+			rule:  "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			start: date(2026, 10, 16), // Friday
+			want:  []time.Time{date(2026, 10, 19), date(2026, 10, 20)},
+		},
+		{
+			name:  "biweekly monday and wednesday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			start: date(2026, 10, 12), // Monday
+			want:  []time.Time{date(2026, 10, 14), date(2026, 10, 26), date(2026, 10, 28)},
+		},
+		{
+			name:  "weekly without byday",
+			rule:  "FREQ=WEEKLY",
+			start: date(2026, 10, 16),
+			want:  []time.Time{date(2026, 10, 23)},
+		},
+		{
+			name:  "monthly skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2026, 1, 31),
+			want:  []time.Time{date(2026, 3, 31), date(2026, 5, 31)},
+		},
+		{
+			name:  "count ends series",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: date(2026, 10, 1),
+			want:  []time.Time{date(2026, 10, 2), date(2026, 10, 3)},
+			ends:  true,
+		},
+		{
+			name:  "until ends series",
+			rule:  "FREQ=WEEKLY;UNTIL=20261015",
+			start: date(2026, 10, 1),
+			want:  []time.Time{date(2026, 10, 8), date(2026, 10, 15)},
+			ends:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+
+			prev := tt.start
+			for i, want := range tt.want {
+				got, ok := rule.Next(prev, i+1)
+				if !ok {
+					t.Fatalf("Next() #%d ended the series early", i+1)
+				}
+				if !got.Equal(want) {
+					t.Fatalf("Next() #%d = %s, want %s", i+1, got, want)
+				}
+				prev = got
+			}
+
+			if _, ok := rule.Next(prev, len(tt.want)+1); ok == tt.ends {
+				t.Fatalf("Next() after last expected occurrence: ok = %v, want %v", ok, !tt.ends)
+			}
+		})
+	}
+}
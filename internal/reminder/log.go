@@ -0,0 +1,31 @@
+package reminder
+
+import (
+	"context"
+
+	"github.com/manish-npx/todo-go-echo/internal/logger"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"go.uber.org/zap"
+)
+
+// Log writes reminders to the application log, for development or when no
+// mail server or webhook is set up.
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (Log) Notify(ctx context.Context, reminder models.Reminder) error {
+	fields := []zap.Field{
+		zap.Int("todo_id", reminder.TodoID),
+		zap.String("title", reminder.Title),
+		zap.Int("user_id", reminder.UserID),
+		zap.Time("remind_at", reminder.RemindAt),
+	}
+	if reminder.DueAt != nil {
+		fields = append(fields, zap.Time("due_at", *reminder.DueAt))
+	}
+	logger.L().Info("todo reminder", fields...)
+	return nil
+}
@@ -0,0 +1,49 @@
+// Package reminder sends todo reminders in the background. A Scheduler
+// claims due reminders from the database and hands each to a Notifier.
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+// Notifier delivers one reminder.
+type Notifier interface {
+	Notify(ctx context.Context, reminder models.Reminder) error
+}
+
+// permanentError marks a failure that retrying won't fix, such as a
+// rejected recipient.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent wraps err so the scheduler gives up on the reminder at once.
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// New builds the notifier named in cfg.Notifier.
+func New(cfg config.ReminderConfig) (Notifier, error) {
+	switch cfg.Notifier {
+	case "", "log":
+		return NewLog(), nil
+	case "smtp":
+		return NewSMTP(cfg.SMTP)
+	case "webhook":
+		return NewWebhook(cfg.Webhook)
+	}
+	return nil, fmt.Errorf("unknown reminder notifier %q", cfg.Notifier)
+}
@@ -0,0 +1,411 @@
+package reminder
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+func testReminder() models.Reminder {
+	due := time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC)
+	return models.Reminder{
+		TodoID:   7,
+		Title:    "Pay rent\r\nBcc: evil@example.com",
+		DueAt:    &due,
+		RemindAt: due.Add(-time.Hour),
+		UserID:   3,
+		Name:     "Ada",
+		Email:    "ada@example.com",
+	}
+}
+
+// smtpSink is a minimal SMTP server that records the mails it accepts.
+// Recipients starting with "bounce" are rejected.
+type smtpSink struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	auths []string
+	mails []sinkMail
+}
+
+type sinkMail struct {
+	from, to, data string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	sink := &smtpSink{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go sink.serve(conn)
+		}
+	}()
+	return sink
+}
+
+func (s *smtpSink) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var mail sinkMail
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case verb == "EHLO":
+			reply("250-sink")
+			reply("250 AUTH PLAIN")
+		case verb == "AUTH":
+			s.mu.Lock()
+			s.auths = append(s.auths, line)
+			s.mu.Unlock()
+			reply("235 authenticated")
+		case strings.HasPrefix(line, "MAIL FROM:"):
+			mail = sinkMail{from: strings.TrimPrefix(line, "MAIL FROM:")}
+			reply("250 ok")
+		case strings.HasPrefix(line, "RCPT TO:"):
+			mail.to = strings.TrimPrefix(line, "RCPT TO:")
+			if strings.HasPrefix(mail.to, "<bounce") {
+				reply("550 no such user")
+				continue
+			}
+			reply("250 ok")
+		case verb == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			mail.data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply("250 queued")
+		case verb == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSMTPNotify(t *testing.T) {
+	sink := newSMTPSink(t)
+	notifier, err := NewSMTP(config.SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     sink.port(),
+		Username: "todo",
+		Password: "secret",
+		From:     "Todo <todo@example.com>",
+	})
+	if err != nil {
+		t.Fatalf("NewSMTP() error = %v", err)
+	}
+
+	if err := notifier.Notify(context.Background(), testReminder()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.auths) != 1 {
+		t.Fatalf("sink saw %d AUTH commands, want 1", len(sink.auths))
+	}
+	if len(sink.mails) != 1 {
+		t.Fatalf("sink received %d mails, want 1", len(sink.mails))
+	}
+	mail := sink.mails[0]
+	if mail.from != "<todo@example.com>" || mail.to != "<ada@example.com>" {
+		t.Fatalf("envelope = %s -> %s", mail.from, mail.to)
+	}
+	headers, body, _ := strings.Cut(mail.data, "\r\n\r\n")
+	if strings.Contains(headers, "\r\nBcc:") {
+		t.Fatalf("title injected a header:\n%s", headers)
+	}
+	for _, want := range []string{`To: "Ada" <ada@example.com>`, "Subject: =?utf-8?q?Reminder:_Pay_rent=0D=0ABcc:"} {
+		if !strings.Contains(headers, want) {
+			t.Fatalf("headers lack %q:\n%s", want, headers)
+		}
+	}
+	if !strings.Contains(body, "Due: Mon, 02 Mar 2026 17:00:00 UTC") {
+		t.Fatalf("body lacks the due date:\n%s", body)
+	}
+}
+
+func TestSMTPRejectedRecipientIsPermanent(t *testing.T) {
+	sink := newSMTPSink(t)
+	notifier, err := NewSMTP(config.SMTPConfig{Host: "127.0.0.1", Port: sink.port(), From: "todo@example.com"})
+	if err != nil {
+		t.Fatalf("NewSMTP() error = %v", err)
+	}
+
+	reminder := testReminder()
+	reminder.Email = "bounce@example.com"
+	err = notifier.Notify(context.Background(), reminder)
+	if err == nil || !isPermanent(err) {
+		t.Fatalf("Notify() error = %v, want a permanent error", err)
+	}
+}
+
+func TestWebhookNotify(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{name: "delivered", status: http.StatusNoContent},
+		{name: "server error is retried", status: http.StatusBadGateway, wantErr: true},
+		{name: "rate limit is retried", status: http.StatusTooManyRequests, wantErr: true},
+		{name: "client error is permanent", status: http.StatusGone, wantErr: true, wantPermanent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload webhookPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				mac := hmac.New(sha256.New, []byte("shh"))
+				mac.Write(body)
+				if r.Header.Get(SignatureHeader) != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+					t.Errorf("bad signature %q", r.Header.Get(SignatureHeader))
+				}
+				if err := json.Unmarshal(body, &payload); err != nil {
+					t.Errorf("payload: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			notifier, err := NewWebhook(config.WebhookConfig{URL: server.URL, Secret: "shh"})
+			if err != nil {
+				t.Fatalf("NewWebhook() error = %v", err)
+			}
+			err = notifier.Notify(context.Background(), testReminder())
+			if (err != nil) != tt.wantErr || isPermanent(err) != tt.wantPermanent {
+				t.Fatalf("Notify() error = %v, want error %v, permanent %v", err, tt.wantErr, tt.wantPermanent)
+			}
+			if payload.Event != WebhookEvent || payload.Reminder.TodoID != 7 {
+				t.Fatalf("payload = %+v", payload)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.ReminderConfig
+		wantErr bool
+	}{
+		{name: "log by default", cfg: config.ReminderConfig{}},
+		{name: "smtp", cfg: config.ReminderConfig{Notifier: "smtp", SMTP: config.SMTPConfig{Host: "mail", From: "todo@example.com"}}},
+		{name: "smtp without host", cfg: config.ReminderConfig{Notifier: "smtp", SMTP: config.SMTPConfig{From: "todo@example.com"}}, wantErr: true},
+		{name: "webhook", cfg: config.ReminderConfig{Notifier: "webhook", Webhook: config.WebhookConfig{URL: "https://hooks.example.com/todo"}}},
+		{name: "webhook without URL", cfg: config.ReminderConfig{Notifier: "webhook"}, wantErr: true},
+		{name: "unknown", cfg: config.ReminderConfig{Notifier: "pigeon"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// reminderRepoMock hands out each reminder once, like the claim query.
+type reminderRepoMock struct {
+	mu      sync.Mutex
+	pending []models.Reminder
+}
+
+func (m *reminderRepoMock) Claim(ctx context.Context, now time.Time, limit int) ([]models.Reminder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := min(limit, len(m.pending))
+	claimed := m.pending[:n]
+	m.pending = m.pending[n:]
+	return claimed, nil
+}
+
+// notifierMock fails the first failures calls per todo with err.
+type notifierMock struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	calls    map[int]int
+	sent     chan int
+}
+
+func (m *notifierMock) Notify(ctx context.Context, reminder models.Reminder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.calls == nil {
+		m.calls = make(map[int]int)
+	}
+	m.calls[reminder.TodoID]++
+	if m.calls[reminder.TodoID] <= m.failures {
+		return m.err
+	}
+	if m.sent != nil {
+		m.sent <- reminder.TodoID
+	}
+	return nil
+}
+
+func TestSchedulerRetries(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		err           error
+		wantDelivered int
+		wantCalls     int
+	}{
+		{name: "first try", wantDelivered: 1, wantCalls: 1},
+		{name: "succeeds on a retry", failures: 2, err: errors.New("connection refused"), wantDelivered: 1, wantCalls: 3},
+		{name: "gives up after max attempts", failures: 5, err: errors.New("connection refused"), wantCalls: 3},
+		{name: "permanent failure is not retried", failures: 5, err: permanent(errors.New("550 no such user")), wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := &reminderRepoMock{pending: []models.Reminder{testReminder()}}
+			notifier := &notifierMock{failures: tt.failures, err: tt.err}
+			scheduler := NewScheduler(repo, notifier, config.ReminderConfig{MaxAttempts: 3})
+			scheduler.backoff = time.Millisecond
+
+			delivered, err := scheduler.RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("RunOnce() error = %v", err)
+			}
+			if delivered != tt.wantDelivered || notifier.calls[7] != tt.wantCalls {
+				t.Fatalf("RunOnce() delivered %d in %d calls, want %d in %d", delivered, notifier.calls[7], tt.wantDelivered, tt.wantCalls)
+			}
+
+			// A claimed reminder is never sent again, delivered or not.
+			if delivered, err := scheduler.RunOnce(ctx); err != nil || delivered != 0 || notifier.calls[7] != tt.wantCalls {
+				t.Fatalf("second RunOnce() = %d, %v after %d calls", delivered, err, notifier.calls[7])
+			}
+		})
+	}
+}
+
+func TestSchedulerSendsBatches(t *testing.T) {
+	repo := &reminderRepoMock{}
+	for id := 1; id <= claimBatchSize*2+1; id++ {
+		reminder := testReminder()
+		reminder.TodoID = id
+		repo.pending = append(repo.pending, reminder)
+	}
+	notifier := &notifierMock{}
+	scheduler := NewScheduler(repo, notifier, config.ReminderConfig{})
+
+	delivered, err := scheduler.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+	if delivered != claimBatchSize*2+1 || len(notifier.calls) != delivered {
+		t.Fatalf("RunOnce() delivered %d to %d todos, want %d", delivered, len(notifier.calls), claimBatchSize*2+1)
+	}
+}
+
+func TestSchedulerStartStop(t *testing.T) {
+	repo := &reminderRepoMock{pending: []models.Reminder{testReminder()}}
+	notifier := &notifierMock{sent: make(chan int, 1)}
+	scheduler := NewScheduler(repo, notifier, config.ReminderConfig{IntervalSeconds: 3600})
+
+	scheduler.Start()
+	scheduler.Start()
+	select {
+	case id := <-notifier.sent:
+		if id != 7 {
+			t.Fatalf("sent todo %d, want 7", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler sent nothing on start")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Stop()
+		scheduler.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop() did not return")
+	}
+}
+
+func TestSchedulerStopCancelsBackoff(t *testing.T) {
+	repo := &reminderRepoMock{pending: []models.Reminder{testReminder()}}
+	notifier := &notifierMock{failures: 10, err: errors.New("timeout")}
+	scheduler := NewScheduler(repo, notifier, config.ReminderConfig{BackoffSeconds: 3600})
+
+	scheduler.Start()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		notifier.mu.Lock()
+		calls := notifier.calls[7]
+		notifier.mu.Unlock()
+		if calls > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scheduler never tried to send")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	scheduler.Stop()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Stop() waited %v for the backoff", elapsed)
+	}
+	if notifier.calls[7] != 1 {
+		t.Fatalf("notifier called %d times, want 1", notifier.calls[7])
+	}
+}
@@ -0,0 +1,175 @@
+package reminder
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/logger"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval    = time.Minute
+	defaultMaxAttempts = 5
+	defaultBackoff     = 2 * time.Second
+	// claimBatchSize reminders are claimed and sent at a time.
+	claimBatchSize = 20
+	// attemptTimeout bounds a single delivery attempt.
+	attemptTimeout = 30 * time.Second
+)
+
+// Scheduler looks for due reminders every interval and sends them.
+//
+// A reminder is claimed in the database before it is sent, so it goes out
+// at most once even with several instances running. Failed sends are
+// retried with exponential backoff; a reminder still undelivered after the
+// last attempt, or when the scheduler stops, is logged and dropped.
+type Scheduler struct {
+	repo        repository.ReminderRepository
+	notifier    Notifier
+	interval    time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	now         func() time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewScheduler(repo repository.ReminderRepository, notifier Notifier, cfg config.ReminderConfig) *Scheduler {
+	s := &Scheduler{
+		repo:        repo,
+		notifier:    notifier,
+		interval:    defaultInterval,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+		now:         time.Now,
+	}
+	if cfg.IntervalSeconds > 0 {
+		s.interval = time.Duration(cfg.IntervalSeconds) * time.Second
+	}
+	if cfg.MaxAttempts > 0 {
+		s.maxAttempts = cfg.MaxAttempts
+	}
+	if cfg.BackoffSeconds > 0 {
+		s.backoff = time.Duration(cfg.BackoffSeconds) * time.Second
+	}
+	return s
+}
+
+// Start runs the scheduler in the background until Stop. Starting a running
+// scheduler does nothing.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx, s.done)
+}
+
+// Stop cancels the sends in flight and waits for the scheduler to finish.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.cancel, s.done = nil, nil
+}
+
+func (s *Scheduler) run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.L().Error("reminder scan failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends every reminder due now and reports how many were delivered.
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		reminders, err := s.repo.Claim(ctx, s.now(), claimBatchSize)
+		if err != nil {
+			return delivered, err
+		}
+
+		var wg sync.WaitGroup
+		results := make([]bool, len(reminders))
+		for i, reminder := range reminders {
+			wg.Go(func() {
+				results[i] = s.deliver(ctx, reminder)
+			})
+		}
+		wg.Wait()
+		for _, ok := range results {
+			if ok {
+				delivered++
+			}
+		}
+
+		if len(reminders) < claimBatchSize || ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+	}
+}
+
+// deliver tries the notifier until it succeeds, fails permanently or runs
+// out of attempts, waiting twice as long before each retry.
+func (s *Scheduler) deliver(ctx context.Context, reminder models.Reminder) bool {
+	wait := s.backoff
+	attempt := 1
+	for {
+		err := s.attempt(ctx, reminder)
+		if err == nil {
+			return true
+		}
+		if isPermanent(err) || attempt == s.maxAttempts || sleep(ctx, wait) != nil {
+			logger.L().Error("reminder not delivered",
+				zap.Int("todo_id", reminder.TodoID),
+				zap.Int("user_id", reminder.UserID),
+				zap.Int("attempts", attempt),
+				zap.Error(err))
+			return false
+		}
+		attempt++
+		wait *= 2
+	}
+}
+
+func (s *Scheduler) attempt(ctx context.Context, reminder models.Reminder) error {
+	ctx, cancel := context.WithTimeout(ctx, attemptTimeout)
+	defer cancel()
+	return s.notifier.Notify(ctx, reminder)
+}
+
+// sleep waits for d, or returns early with the context's error.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
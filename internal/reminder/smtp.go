@@ -0,0 +1,137 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+const defaultSMTPPort = 587
+
+// SMTP mails reminders as plain text. It upgrades to TLS when the server
+// offers STARTTLS and authenticates when a username is configured.
+type SMTP struct {
+	host   string
+	addr   string
+	from   *mail.Address
+	auth   smtp.Auth
+	dialer net.Dialer
+	now    func() time.Time
+}
+
+func NewSMTP(cfg config.SMTPConfig) (*SMTP, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("SMTP reminders need a host")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP from address %q", cfg.From)
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	s := &SMTP{
+		host: cfg.Host,
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		from: from,
+		now:  time.Now,
+	}
+	if cfg.Username != "" {
+		// PlainAuth refuses to send the password unencrypted, except to localhost.
+		s.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return s, nil
+}
+
+func (s *SMTP) Notify(ctx context.Context, reminder models.Reminder) error {
+	conn, err := s.dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	// net/smtp knows nothing of contexts; closing the connection interrupts it.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if err := client.Auth(s.auth); err != nil {
+			return smtpError(err)
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return smtpError(err)
+	}
+	if err := client.Rcpt(reminder.Email); err != nil {
+		return smtpError(err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(s.message(reminder)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err)
+	}
+	return client.Quit()
+}
+
+// message builds the mail. Header values from users are MIME-encoded, so a
+// title can't add headers.
+func (s *SMTP) message(reminder models.Reminder) []byte {
+	to := mail.Address{Name: reminder.Name, Address: reminder.Email}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "Reminder: "+reminder.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", s.now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+
+	fmt.Fprintf(&b, "Reminder: %s\r\n", reminder.Title)
+	if reminder.DueAt != nil {
+		fmt.Fprintf(&b, "Due: %s\r\n", reminder.DueAt.UTC().Format(time.RFC1123))
+	}
+	return b.Bytes()
+}
+
+// smtpError marks 5xx replies, which the server won't answer differently
+// next time, as permanent.
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return permanent(err)
+	}
+	return err
+}
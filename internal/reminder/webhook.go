@@ -0,0 +1,81 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+// WebhookEvent names the event in the payload, for receivers that handle
+// more than one kind of webhook.
+const WebhookEvent = "todo.reminder"
+
+// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body,
+// keyed with the configured secret.
+const SignatureHeader = "X-Signature-256"
+
+// Webhook POSTs each reminder as JSON. Any 2xx answer counts as delivered.
+type Webhook struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Event    string          `json:"event"`
+	Reminder models.Reminder `json:"reminder"`
+}
+
+func NewWebhook(cfg config.WebhookConfig) (*Webhook, error) {
+	target, err := url.Parse(cfg.URL)
+	if err != nil || target.Host == "" || (target.Scheme != "http" && target.Scheme != "https") {
+		return nil, fmt.Errorf("invalid reminder webhook URL %q", cfg.URL)
+	}
+	return &Webhook{url: cfg.URL, secret: []byte(cfg.Secret), client: http.DefaultClient}, nil
+}
+
+func (w *Webhook) Notify(ctx context.Context, reminder models.Reminder) error {
+	body, err := json.Marshal(webhookPayload{Event: WebhookEvent, Reminder: reminder})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		mac := hmac.New(sha256.New, w.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	err = fmt.Errorf("webhook answered %s: %s", res.Status, strings.TrimSpace(string(detail)))
+	// Other client errors mean the request itself is unwelcome.
+	if res.StatusCode >= 400 && res.StatusCode < 500 &&
+		res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests {
+		return permanent(err)
+	}
+	return err
+}
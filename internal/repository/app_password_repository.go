@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"gorm.io/gorm"
+)
+
+type AppPasswordRepository interface {
+	GetAll(ctx context.Context, userID int) ([]models.AppPassword, error)
+	Create(ctx context.Context, password *models.AppPassword) error
+	Delete(ctx context.Context, userID, id int) error
+	// Touch records that the password was just used to sign in.
+	Touch(ctx context.Context, id int, at time.Time) error
+}
+
+type appPasswordRepository struct {
+	db *gorm.DB
+}
+
+func NewAppPasswordRepository(db *gorm.DB) AppPasswordRepository {
+	return &appPasswordRepository{db: db}
+}
+
+func (r *appPasswordRepository) GetAll(ctx context.Context, userID int) ([]models.AppPassword, error) {
+	var passwords []models.AppPassword
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&passwords).Error
+	if err != nil {
+		return nil, err
+	}
+	return passwords, nil
+}
+
+func (r *appPasswordRepository) Create(ctx context.Context, password *models.AppPassword) error {
+	return r.db.WithContext(ctx).Create(password).Error
+}
+
+func (r *appPasswordRepository) Delete(ctx context.Context, userID, id int) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.AppPassword{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *appPasswordRepository) Touch(ctx context.Context, id int, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.AppPassword{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/manish-npx/todo-go-echo/internal/models"
@@ -10,14 +11,16 @@ import (
 )
 
 type BlogRepository interface {
-	GetAll(ctx context.Context) ([]models.Blog, error)
+	// List returns one page of the blogs matching every field of filter.
+	List(ctx context.Context, filter models.BlogFilter) ([]models.Blog, error)
+	Count(ctx context.Context, filter models.BlogFilter) (int64, error)
 	GetByID(ctx context.Context, id int) (*models.Blog, error)
-	GetByCategory(ctx context.Context, categoryID int) ([]models.Blog, error)
-	GetPublished(ctx context.Context) ([]models.Blog, error)
-	GetByAuthor(ctx context.Context, author string) ([]models.Blog, error)
 	Create(ctx context.Context, blog *models.Blog) error
 	Update(ctx context.Context, blog *models.Blog) error
 	Delete(ctx context.Context, id int) error
+	GetTrash(ctx context.Context) ([]models.Blog, error)
+	Restore(ctx context.Context, id int) error
+	PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error)
 	IncrementViews(ctx context.Context, id int) error
 	Search(ctx context.Context, query string) ([]models.Blog, error)
 }
@@ -30,32 +33,38 @@ func NewBlogRepository(db *gorm.DB) BlogRepository {
 	return &blogRepository{db: db}
 }
 
-func (r *blogRepository) GetAll(ctx context.Context) ([]models.Blog, error) {
-	var blogs []models.Blog
-	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&blogs).Error
-	if err != nil {
-		return nil, err
-	}
-	return blogs, nil
+// blogSortColumns whitelists sortable columns so ORDER BY is never built from raw input.
+var blogSortColumns = map[string]string{
+	models.BlogSortCreatedAt: "created_at",
+	models.BlogSortUpdatedAt: "updated_at",
+	models.BlogSortTitle:     "title",
+	models.BlogSortViews:     "views",
 }
 
-func (r *blogRepository) GetByID(ctx context.Context, id int) (*models.Blog, error) {
-	var blog models.Blog
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&blog).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, nil
+// List pages by keyset when filter.After is set and by offset otherwise;
+// the id column breaks ties between equal sort values.
+func (r *blogRepository) List(ctx context.Context, filter models.BlogFilter) ([]models.Blog, error) {
+	column, ok := blogSortColumns[filter.Sort]
+	if !ok {
+		column = "created_at"
 	}
-	if err != nil {
-		return nil, err
+	direction, comparator := "ASC", ">"
+	if filter.Desc {
+		direction, comparator = "DESC", "<"
+	}
+
+	query := r.filtered(ctx, filter)
+	if filter.After != nil {
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparator), filter.After.Value, filter.After.ID)
+	} else if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
 	}
-	return &blog, nil
-}
 
-func (r *blogRepository) GetByCategory(ctx context.Context, categoryID int) ([]models.Blog, error) {
 	var blogs []models.Blog
-	err := r.db.WithContext(ctx).
-		Where("category_id = ?", categoryID).
-		Order("created_at DESC").
+	err := query.
+		Order(column + " " + direction).
+		Order("id " + direction).
+		Limit(filter.Limit).
 		Find(&blogs).Error
 	if err != nil {
 		return nil, err
@@ -63,32 +72,50 @@ func (r *blogRepository) GetByCategory(ctx context.Context, categoryID int) ([]m
 	return blogs, nil
 }
 
-func (r *blogRepository) GetPublished(ctx context.Context) ([]models.Blog, error) {
-	var blogs []models.Blog
-	err := r.db.WithContext(ctx).
-		Where("status = ?", models.StatusPublished).
-		Order("published_at DESC").
-		Find(&blogs).Error
+// Count ignores the cursor and offset so every page reports the same total.
+func (r *blogRepository) Count(ctx context.Context, filter models.BlogFilter) (int64, error) {
+	var total int64
+	err := r.filtered(ctx, filter).Count(&total).Error
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return blogs, nil
+	return total, nil
 }
 
-func (r *blogRepository) GetByAuthor(ctx context.Context, author string) ([]models.Blog, error) {
-	var blogs []models.Blog
-	err := r.db.WithContext(ctx).
-		Where("author ILIKE ?", "%"+author+"%").
-		Order("created_at DESC").
-		Find(&blogs).Error
+func (r *blogRepository) filtered(ctx context.Context, filter models.BlogFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Blog{})
+	if filter.CategoryID != nil {
+		query = query.Where("category_id = ?", *filter.CategoryID)
+	}
+	if filter.Author != "" {
+		query = query.Where(`author ILIKE ? ESCAPE '\'`, containsPattern(filter.Author))
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
+
+func (r *blogRepository) GetByID(ctx context.Context, id int) (*models.Blog, error) {
+	var blog models.Blog
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&blog).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	return blogs, nil
+	return &blog, nil
 }
 
 func (r *blogRepository) Create(ctx context.Context, blog *models.Blog) error {
-	now := time.Now()
+	now := utcNow()
 	blog.CreatedAt = now
 	blog.UpdatedAt = now
 	blog.Views = 0
@@ -99,9 +126,9 @@ func (r *blogRepository) Create(ctx context.Context, blog *models.Blog) error {
 }
 
 func (r *blogRepository) Update(ctx context.Context, blog *models.Blog) error {
-	blog.UpdatedAt = time.Now()
+	blog.UpdatedAt = utcNow()
 	if blog.Status == models.StatusPublished && blog.PublishedAt == nil {
-		now := time.Now()
+		now := utcNow()
 		blog.PublishedAt = &now
 	}
 
@@ -136,6 +163,46 @@ func (r *blogRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// GetTrash returns soft-deleted blogs, most recently deleted first.
+func (r *blogRepository) GetTrash(ctx context.Context) ([]models.Blog, error) {
+	var blogs []models.Blog
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&blogs).Error
+	if err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+func (r *blogRepository) Restore(ctx context.Context, id int) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&models.Blog{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{
+			"deleted_at": nil,
+			"updated_at": utcNow(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes up to limit blogs trashed before the cutoff.
+func (r *blogRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ids := r.db.Unscoped().Model(&models.Blog{}).
+		Select("id").
+		Where("deleted_at < ?", before).
+		Order("id").
+		Limit(limit)
+	result := r.db.WithContext(ctx).Unscoped().Where("id IN (?)", ids).Delete(&models.Blog{})
+	return result.RowsAffected, result.Error
+}
+
 func (r *blogRepository) IncrementViews(ctx context.Context, id int) error {
 	return r.db.WithContext(ctx).Model(&models.Blog{}).
 		Where("id = ?", id).
@@ -144,9 +211,9 @@ func (r *blogRepository) IncrementViews(ctx context.Context, id int) error {
 
 func (r *blogRepository) Search(ctx context.Context, searchTerm string) ([]models.Blog, error) {
 	var blogs []models.Blog
-	pattern := "%" + searchTerm + "%"
+	pattern := containsPattern(searchTerm)
 	err := r.db.WithContext(ctx).
-		Where("title ILIKE ? OR content ILIKE ?", pattern, pattern).
+		Where(`title ILIKE ? ESCAPE '\' OR content ILIKE ? ESCAPE '\'`, pattern, pattern).
 		Order("created_at DESC").
 		Find(&blogs).Error
 	if err != nil {
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"gorm.io/gorm"
+)
+
+// claimRemindersQuery marks due reminders as sent and returns them with
+// their recipient. SKIP LOCKED lets several API instances share the work
+// without two of them claiming the same todo.
+const claimRemindersQuery = `
+WITH claimed AS (
+	UPDATE todos SET reminded_at = @now
+	WHERE id IN (
+		SELECT id FROM todos
+		WHERE remind_at <= @now AND reminded_at IS NULL AND deleted_at IS NULL AND NOT completed
+		ORDER BY remind_at, id
+		LIMIT @limit
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, title, due_at, remind_at, COALESCE(assignee_id, user_id) AS recipient_id
+)
+SELECT claimed.id AS todo_id, claimed.title, claimed.due_at, claimed.remind_at,
+	users.id AS user_id, users.name, users.email
+FROM claimed
+JOIN users ON users.id = claimed.recipient_id
+ORDER BY claimed.remind_at, claimed.id`
+
+// ReminderRepository hands out due todo reminders.
+type ReminderRepository interface {
+	// Claim marks up to limit reminders due at now as sent and returns them.
+	// A claimed reminder is never returned again, whether or not it is
+	// delivered, until its todo gets a new remind_at.
+	Claim(ctx context.Context, now time.Time, limit int) ([]models.Reminder, error)
+}
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) Claim(ctx context.Context, now time.Time, limit int) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+	err := r.db.WithContext(ctx).Raw(claimRemindersQuery, map[string]any{"now": now, "limit": limit}).Scan(&reminders).Error
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
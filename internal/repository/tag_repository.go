@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	GetAll(ctx context.Context, userID int) ([]models.Tag, error)
+	GetByID(ctx context.Context, userID, id int) (*models.Tag, error)
+	Create(ctx context.Context, tag *models.Tag) error
+	Update(ctx context.Context, tag *models.Tag) error
+	Delete(ctx context.Context, userID, id int) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) GetAll(ctx context.Context, userID int) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name").Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, userID, id int) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+// Update renames a tag in place; todos reference it through todo_tags so they
+// pick up the new name without being touched.
+func (r *tagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	result := r.db.WithContext(ctx).Model(&models.Tag{}).
+		Where("id = ? AND user_id = ?", tag.ID, tag.UserID).
+		Updates(map[string]any{
+			"name":  tag.Name,
+			"color": tag.Color,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *tagRepository) Delete(ctx context.Context, userID, id int) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.Tag{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"gorm.io/gorm"
+)
+
+// reportEntries is the caller's time within the range, each entry clipped
+// to it; running timers end at @now.
+const reportEntries = `
+WITH entries AS (
+	SELECT todo_id,
+		GREATEST(started_at, @start) AS started_at,
+		LEAST(COALESCE(ended_at, @now), @end) AS ended_at
+	FROM time_entries
+	WHERE user_id = @user AND started_at < @end AND COALESCE(ended_at, @now) > @start
+)`
+
+// reportSeconds sums entry lengths as whole seconds.
+const reportSeconds = "CAST(COALESCE(SUM(EXTRACT(EPOCH FROM entries.ended_at - entries.started_at)), 0) AS bigint)"
+
+const reportTotalQuery = reportEntries + `
+SELECT ` + reportSeconds + ` FROM entries`
+
+// Trashed todos are included; the time was spent all the same.
+const reportByTodoQuery = reportEntries + `
+SELECT entries.todo_id, todos.title, ` + reportSeconds + ` AS seconds
+FROM entries
+JOIN todos ON todos.id = entries.todo_id
+GROUP BY entries.todo_id, todos.title
+ORDER BY seconds DESC, entries.todo_id`
+
+const reportByTagQuery = reportEntries + `
+SELECT tags.id AS tag_id, tags.name, ` + reportSeconds + ` AS seconds
+FROM entries
+JOIN todo_tags ON todo_tags.todo_id = entries.todo_id
+JOIN tags ON tags.id = todo_tags.tag_id
+GROUP BY tags.id, tags.name
+ORDER BY seconds DESC, tags.id`
+
+// reportByDayQuery generates every day of the range and clips the entries
+// again to each day's bounds in the caller's timezone.
+const reportByDayQuery = reportEntries + `, days AS (
+	SELECT day, day AT TIME ZONE @tz AS day_start, (day + interval '1 day') AT TIME ZONE @tz AS day_end
+	FROM generate_series(CAST(@from AS timestamp), CAST(@to AS timestamp), interval '1 day') AS day
+)
+SELECT to_char(days.day, 'YYYY-MM-DD') AS day,
+	CAST(COALESCE(SUM(EXTRACT(EPOCH FROM LEAST(entries.ended_at, days.day_end) - GREATEST(entries.started_at, days.day_start))), 0) AS bigint) AS seconds
+FROM days
+LEFT JOIN entries ON entries.started_at < days.day_end AND entries.ended_at > days.day_start
+GROUP BY days.day
+ORDER BY days.day`
+
+// stopUnreachableTimers ends running timers on the given todos whose user
+// can no longer see them, because the todo went to the trash or they lost
+// access. Otherwise the timer would keep counting in the report and block
+// the user's next start.
+const stopUnreachableTimers = `
+UPDATE time_entries SET ended_at = GREATEST(started_at, @now), updated_at = @now
+WHERE ended_at IS NULL AND todo_id IN @todos AND NOT EXISTS (
+	SELECT 1 FROM todos
+	WHERE todos.id = time_entries.todo_id AND todos.deleted_at IS NULL AND (
+		todos.user_id = time_entries.user_id OR todos.assignee_id = time_entries.user_id OR todos.list_id IN (
+			SELECT id FROM todo_lists WHERE user_id = time_entries.user_id
+			UNION
+			SELECT list_id FROM list_members WHERE user_id = time_entries.user_id
+		)
+	)
+)`
+
+func stopTimers(tx *gorm.DB, todoIDs []int) error {
+	if len(todoIDs) == 0 {
+		return nil
+	}
+	return tx.Exec(stopUnreachableTimers, map[string]any{"now": utcNow(), "todos": todoIDs}).Error
+}
+
+// TimeEntryRepository reads and writes tracked time; whether the caller may
+// see the todo is checked by the service.
+type TimeEntryRepository interface {
+	// List returns a todo's entries, newest first.
+	List(ctx context.Context, todoID int) ([]models.TimeEntry, error)
+	GetByID(ctx context.Context, todoID, id int) (*models.TimeEntry, error)
+	// Running returns the user's running timer, or nil.
+	Running(ctx context.Context, userID int) (*models.TimeEntry, error)
+	Create(ctx context.Context, entry *models.TimeEntry) error
+	// Stop sets ended_at on a running entry; sql.ErrNoRows if it has stopped.
+	Stop(ctx context.Context, entry *models.TimeEntry) error
+	Delete(ctx context.Context, todoID, id int) error
+	Report(ctx context.Context, userID int, filter models.TimeReportFilter) (*models.TimeReport, error)
+}
+
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func (r *timeEntryRepository) List(ctx context.Context, todoID int) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	err := r.db.WithContext(ctx).
+		Where("todo_id = ?", todoID).
+		Order("started_at DESC").
+		Order("id DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *timeEntryRepository) GetByID(ctx context.Context, todoID, id int) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.db.WithContext(ctx).Where("id = ? AND todo_id = ?", id, todoID).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepository) Running(ctx context.Context, userID int) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := r.db.WithContext(ctx).Where("user_id = ? AND ended_at IS NULL", userID).First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepository) Create(ctx context.Context, entry *models.TimeEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *timeEntryRepository) Stop(ctx context.Context, entry *models.TimeEntry) error {
+	entry.UpdatedAt = utcNow()
+	result := r.db.WithContext(ctx).Model(&models.TimeEntry{}).
+		Where("id = ? AND ended_at IS NULL", entry.ID).
+		Updates(map[string]any{
+			"ended_at":   entry.EndedAt,
+			"updated_at": entry.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *timeEntryRepository) Delete(ctx context.Context, todoID, id int) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND todo_id = ?", id, todoID).Delete(&models.TimeEntry{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *timeEntryRepository) Report(ctx context.Context, userID int, filter models.TimeReportFilter) (*models.TimeReport, error) {
+	args := map[string]any{
+		"user":  userID,
+		"start": filter.Start,
+		"end":   filter.End,
+		"now":   filter.Now,
+		"tz":    filter.Timezone,
+		"from":  filter.From,
+		"to":    filter.To,
+	}
+	report := &models.TimeReport{
+		ByTodo: []models.TimeReportTodo{},
+		ByTag:  []models.TimeReportTag{},
+		ByDay:  []models.TimeReportDay{},
+	}
+
+	if err := r.db.WithContext(ctx).Raw(reportTotalQuery, args).Row().Scan(&report.TotalSeconds); err != nil {
+		return nil, err
+	}
+	if err := r.db.WithContext(ctx).Raw(reportByTodoQuery, args).Scan(&report.ByTodo).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.WithContext(ctx).Raw(reportByTagQuery, args).Scan(&report.ByTag).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.WithContext(ctx).Raw(reportByDayQuery, args).Scan(&report.ByDay).Error; err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"gorm.io/gorm"
+)
+
+// TodoAttachmentRepository stores attachment metadata; the blobs are kept
+// by a storage.Storage.
+type TodoAttachmentRepository interface {
+	List(ctx context.Context, todoID int) ([]models.TodoAttachment, error)
+	GetByID(ctx context.Context, todoID, id int) (*models.TodoAttachment, error)
+	Create(ctx context.Context, attachment *models.TodoAttachment) error
+	Delete(ctx context.Context, id int) error
+	// ListPurgeable returns up to limit attachments of todos trashed before
+	// the cutoff, i.e. those the next purge will remove.
+	ListPurgeable(ctx context.Context, before time.Time, limit int) ([]models.TodoAttachment, error)
+}
+
+type todoAttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewTodoAttachmentRepository(db *gorm.DB) TodoAttachmentRepository {
+	return &todoAttachmentRepository{db: db}
+}
+
+func (r *todoAttachmentRepository) List(ctx context.Context, todoID int) ([]models.TodoAttachment, error) {
+	var attachments []models.TodoAttachment
+	err := r.db.WithContext(ctx).
+		Where("todo_id = ?", todoID).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *todoAttachmentRepository) GetByID(ctx context.Context, todoID, id int) (*models.TodoAttachment, error) {
+	var attachment models.TodoAttachment
+	err := r.db.WithContext(ctx).Where("id = ? AND todo_id = ?", id, todoID).First(&attachment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *todoAttachmentRepository) Create(ctx context.Context, attachment *models.TodoAttachment) error {
+	return r.db.WithContext(ctx).Create(attachment).Error
+}
+
+func (r *todoAttachmentRepository) Delete(ctx context.Context, id int) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.TodoAttachment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *todoAttachmentRepository) ListPurgeable(ctx context.Context, before time.Time, limit int) ([]models.TodoAttachment, error) {
+	var attachments []models.TodoAttachment
+	err := r.db.WithContext(ctx).
+		Joins("JOIN todos ON todos.id = todo_attachments.todo_id").
+		Where("todos.deleted_at < ?", before).
+		Order("todo_attachments.id").
+		Limit(limit).
+		Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"gorm.io/gorm"
+)
+
+// TodoCommentRepository reads and writes comments by todo; whether the
+// caller may see the todo is checked by the service.
+type TodoCommentRepository interface {
+	// List returns a todo's comments oldest first, with their authors.
+	List(ctx context.Context, todoID int) ([]models.TodoComment, error)
+	GetByID(ctx context.Context, todoID, id int) (*models.TodoComment, error)
+	Create(ctx context.Context, comment *models.TodoComment) error
+	Update(ctx context.Context, comment *models.TodoComment) error
+	Delete(ctx context.Context, todoID, id int) error
+}
+
+type todoCommentRepository struct {
+	db *gorm.DB
+}
+
+func NewTodoCommentRepository(db *gorm.DB) TodoCommentRepository {
+	return &todoCommentRepository{db: db}
+}
+
+func (r *todoCommentRepository) List(ctx context.Context, todoID int) ([]models.TodoComment, error) {
+	var comments []models.TodoComment
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("todo_id = ?", todoID).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *todoCommentRepository) GetByID(ctx context.Context, todoID, id int) (*models.TodoComment, error) {
+	var comment models.TodoComment
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("id = ? AND todo_id = ?", id, todoID).
+		First(&comment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *todoCommentRepository) Create(ctx context.Context, comment *models.TodoComment) error {
+	if err := r.db.WithContext(ctx).Omit("User").Create(comment).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Preload("User").First(comment, comment.ID).Error
+}
+
+func (r *todoCommentRepository) Update(ctx context.Context, comment *models.TodoComment) error {
+	comment.UpdatedAt = utcNow()
+	result := r.db.WithContext(ctx).Model(&models.TodoComment{}).
+		Where("id = ? AND todo_id = ?", comment.ID, comment.TodoID).
+		Updates(map[string]any{
+			"body":       comment.Body,
+			"edited_at":  comment.EditedAt,
+			"updated_at": comment.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *todoCommentRepository) Delete(ctx context.Context, todoID, id int) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND todo_id = ?", id, todoID).Delete(&models.TodoComment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// dependencyLock is the advisory lock class held while the dependency graph
+// changes, so two concurrent additions can't close a cycle between them.
+const dependencyLock = 3
+
+// openBlockerOf matches a todo's blockers that are neither done nor in the trash.
+const openBlockerOf = `SELECT 1 FROM todo_dependencies d JOIN todos b ON b.id = d.blocked_by_id
+	WHERE d.todo_id = %s AND NOT b.completed AND b.deleted_at IS NULL`
+
+// blockedByQuery walks blocked_by edges from @todo; UNION stops at cycles.
+const blockedByQuery = `
+WITH RECURSIVE blockers(id) AS (
+	SELECT blocked_by_id FROM todo_dependencies WHERE todo_id = @todo
+	UNION
+	SELECT d.blocked_by_id FROM todo_dependencies d JOIN blockers b ON d.todo_id = b.id
+)
+SELECT EXISTS (SELECT 1 FROM blockers WHERE id = @blocker)`
+
+func (r *todoRepository) ListBlockers(ctx context.Context, userID, todoID int) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := preloadTags(withCommentCount(visibleTo(r.db.WithContext(ctx), userID))).
+		Where("id IN (SELECT blocked_by_id FROM todo_dependencies WHERE todo_id = ?)", todoID).
+		Order("id ASC").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) ListBlocked(ctx context.Context, userID, todoID int) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := preloadTags(withCommentCount(visibleTo(r.db.WithContext(ctx), userID))).
+		Where("id IN (SELECT todo_id FROM todo_dependencies WHERE blocked_by_id = ?)", todoID).
+		Order("id ASC").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) OpenBlockers(ctx context.Context, todoID int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM ("+fmt.Sprintf(openBlockerOf, "?")+") open", todoID).Scan(&count).Error
+	return count, err
+}
+
+func (r *todoRepository) IsBlockedBy(ctx context.Context, todoID, blockerID int) (bool, error) {
+	var blocked bool
+	err := r.db.WithContext(ctx).Raw(blockedByQuery, map[string]any{"todo": todoID, "blocker": blockerID}).Scan(&blocked).Error
+	return blocked, err
+}
+
+func (r *todoRepository) LockDependencies(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(CAST(? AS integer), 0)", dependencyLock).Error
+}
+
+func (r *todoRepository) AddDependency(ctx context.Context, todoID, blockerID int) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.TodoDependency{TodoID: todoID, BlockedByID: blockerID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *todoRepository) RemoveDependency(ctx context.Context, todoID, blockerID int) error {
+	result := r.db.WithContext(ctx).
+		Where("todo_id = ? AND blocked_by_id = ?", todoID, blockerID).
+		Delete(&models.TodoDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"gorm.io/gorm"
+)
+
+// TodoListRepository returns lists the user owns or is a member of, with the
+// user's role filled in. Writes are scoped to the owner in list.UserID.
+type TodoListRepository interface {
+	GetAll(ctx context.Context, userID int, archived bool) ([]models.TodoList, error)
+	GetByID(ctx context.Context, userID, id int) (*models.TodoList, error)
+	Create(ctx context.Context, list *models.TodoList) error
+	Update(ctx context.Context, list *models.TodoList) error
+	CountOpen(ctx context.Context, listID int) (int64, error)
+	GetMembers(ctx context.Context, listID int) ([]models.ListMember, error)
+	GetMember(ctx context.Context, listID, userID int) (*models.ListMember, error)
+	AddMember(ctx context.Context, member *models.ListMember) error
+	UpdateMember(ctx context.Context, member *models.ListMember) error
+	// RemoveMember revokes access and unassigns the member from the list's
+	// todos they don't own, since they can no longer see them. Their timers
+	// on those todos are stopped.
+	RemoveMember(ctx context.Context, listID, userID int) error
+}
+
+type todoListRepository struct {
+	db *gorm.DB
+}
+
+func NewTodoListRepository(db *gorm.DB) TodoListRepository {
+	return &todoListRepository{db: db}
+}
+
+// visibleLists selects the lists userID owns or is a member of, with the
+// number of open todos in each and the user's role on it.
+func visibleLists(db *gorm.DB, userID int) *gorm.DB {
+	return db.Model(&models.TodoList{}).
+		Select(`todo_lists.*, (
+			SELECT COUNT(*) FROM todos
+			WHERE todos.list_id = todo_lists.id AND todos.completed = FALSE AND todos.deleted_at IS NULL
+		) AS open_todos, CASE WHEN todo_lists.user_id = ? THEN 'owner' ELSE (
+			SELECT m.role FROM list_members m
+			WHERE m.list_id = todo_lists.id AND m.user_id = ?
+		) END AS role`, userID, userID).
+		Where(`(todo_lists.user_id = ? OR EXISTS (
+			SELECT 1 FROM list_members m WHERE m.list_id = todo_lists.id AND m.user_id = ?
+		))`, userID, userID)
+}
+
+func (r *todoListRepository) GetAll(ctx context.Context, userID int, archived bool) ([]models.TodoList, error) {
+	var lists []models.TodoList
+	err := visibleLists(r.db.WithContext(ctx), userID).
+		Where("todo_lists.archived = ?", archived).
+		Order("name").
+		Find(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (r *todoListRepository) GetByID(ctx context.Context, userID, id int) (*models.TodoList, error) {
+	var list models.TodoList
+	err := visibleLists(r.db.WithContext(ctx), userID).
+		Where("todo_lists.id = ?", id).
+		First(&list).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *todoListRepository) Create(ctx context.Context, list *models.TodoList) error {
+	now := utcNow()
+	list.CreatedAt = now
+	list.UpdatedAt = now
+	return r.db.WithContext(ctx).Create(list).Error
+}
+
+func (r *todoListRepository) Update(ctx context.Context, list *models.TodoList) error {
+	list.UpdatedAt = utcNow()
+	result := r.db.WithContext(ctx).Model(&models.TodoList{}).
+		Where("id = ? AND user_id = ?", list.ID, list.UserID).
+		Updates(map[string]any{
+			"name":        list.Name,
+			"description": list.Description,
+			"archived":    list.Archived,
+			"archived_at": list.ArchivedAt,
+			"updated_at":  list.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *todoListRepository) CountOpen(ctx context.Context, listID int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Todo{}).
+		Where("list_id = ? AND completed = ?", listID, false).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *todoListRepository) GetMembers(ctx context.Context, listID int) ([]models.ListMember, error) {
+	var members []models.ListMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("list_id = ?", listID).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *todoListRepository) GetMember(ctx context.Context, listID, userID int) (*models.ListMember, error) {
+	var member models.ListMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("list_id = ? AND user_id = ?", listID, userID).
+		First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *todoListRepository) AddMember(ctx context.Context, member *models.ListMember) error {
+	now := utcNow()
+	member.CreatedAt = now
+	member.UpdatedAt = now
+	return r.db.WithContext(ctx).Omit("User").Create(member).Error
+}
+
+func (r *todoListRepository) UpdateMember(ctx context.Context, member *models.ListMember) error {
+	member.UpdatedAt = utcNow()
+	result := r.db.WithContext(ctx).Model(&models.ListMember{}).
+		Where("list_id = ? AND user_id = ?", member.ListID, member.UserID).
+		Updates(map[string]any{
+			"role":       member.Role,
+			"updated_at": member.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *todoListRepository) RemoveMember(ctx context.Context, listID, userID int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("list_id = ? AND user_id = ?", listID, userID).Delete(&models.ListMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+		err := tx.Model(&models.Todo{}).
+			Where("list_id = ? AND assignee_id = ? AND user_id <> ?", listID, userID, userID).
+			Updates(map[string]any{
+				"assignee_id": nil,
+				"updated_at":  utcNow(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		var todoIDs []int
+		if err := tx.Model(&models.Todo{}).Where("list_id = ?", listID).Pluck("id", &todoIDs).Error; err != nil {
+			return err
+		}
+		return stopTimers(tx, todoIDs)
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/rank"
+	"gorm.io/gorm"
+)
+
+// Advisory lock classes for the two kinds of ordering scope. A todo in a
+// list is ordered among that list's todos; one without a list among its
+// owner's other todos without a list.
+const (
+	positionLockList  = 1
+	positionLockInbox = 2
+)
+
+// lockPositions serializes writers of one scope until the transaction ends,
+// so two concurrent moves can't compute the same key.
+func lockPositions(tx *gorm.DB, todo *models.Todo) error {
+	class, key := positionLockInbox, todo.UserID
+	if todo.ListID != nil {
+		class, key = positionLockList, *todo.ListID
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock(CAST(? AS integer), CAST(? AS integer))", class, key).Error
+}
+
+// positionScope selects the live todos ordered together with todo.
+func positionScope(tx *gorm.DB, todo *models.Todo) *gorm.DB {
+	query := tx.Model(&models.Todo{})
+	if todo.ListID != nil {
+		return query.Where("list_id = ?", *todo.ListID)
+	}
+	return query.Where("user_id = ? AND list_id IS NULL", todo.UserID)
+}
+
+// nextPosition locks todo's scope and returns a key after its last todo.
+func nextPosition(tx *gorm.DB, todo *models.Todo) (string, error) {
+	if err := lockPositions(tx, todo); err != nil {
+		return "", err
+	}
+
+	var last sql.NullString
+	if err := positionScope(tx, todo).Select("MAX(position)").Row().Scan(&last); err != nil {
+		return "", err
+	}
+	return rank.Between(last.String, "")
+}
+
+// keepAssigneeIn keeps a todo's assignee only if they own it or have a role
+// on the list it moves to.
+const keepAssigneeIn = `CASE WHEN assignee_id = user_id OR assignee_id IN (
+	SELECT user_id FROM todo_lists WHERE id = ?
+	UNION
+	SELECT user_id FROM list_members WHERE list_id = ?
+) THEN assignee_id END`
+
+// appendToScope gives each todo a fresh key at the end of the scope it is
+// about to join and writes the new list_id and position together. Assignees
+// who can't see the new scope are dropped, and so are timers of users who
+// lose access.
+func appendToScope(tx *gorm.DB, todos []models.Todo, listID *int) error {
+	for _, todo := range todos {
+		todo.ListID = listID
+		position, err := nextPosition(tx, &todo)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&models.Todo{}).
+			Where("id = ?", todo.ID).
+			Updates(map[string]any{
+				"list_id":     listID,
+				"assignee_id": gorm.Expr(keepAssigneeIn, listID, listID),
+				"position":    position,
+				"updated_at":  utcNow(),
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+	ids := make([]int, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	return stopTimers(tx, ids)
+}
@@ -1,20 +1,112 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/manish-npx/todo-go-echo/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// TodoRepository scopes reads to the todos a user can see: their own, those
+// assigned to them and those in lists they own or were invited to. Writes
+// are scoped to the owner in todo.UserID; checking the caller's role is up
+// to the service.
 type TodoRepository interface {
-	GetAll() ([]models.Todo, error)
-	GetByID(id int) (*models.Todo, error)
-	Create(todo *models.Todo) error
-	Update(todo *models.Todo) error
-	Delete(id int) error
+	List(ctx context.Context, userID int, filter models.TodoFilter) ([]models.Todo, error)
+	Count(ctx context.Context, userID int, filter models.TodoFilter) (int64, error)
+	ListDue(ctx context.Context, userID int, from, to *time.Time) ([]models.Todo, error)
+	// Stats computes the completion metrics of the user's own todos.
+	Stats(ctx context.Context, userID int, filter models.TodoStatsFilter) (*models.TodoStats, error)
+	GetByID(ctx context.Context, userID, id int) (*models.Todo, error)
+	Create(ctx context.Context, todo *models.Todo) error
+	Update(ctx context.Context, todo *models.Todo) error
+	// Delete moves a todo to the trash; see Restore and PurgeDeleted. It
+	// takes more than one statement, so run it inside Transaction.
+	Delete(ctx context.Context, userID, id int, mode models.SubtaskDeleteMode) error
+	ListTrash(ctx context.Context, userID int) ([]models.Todo, error)
+	GetDeleted(ctx context.Context, userID, id int) (*models.Todo, error)
+	Restore(ctx context.Context, todo *models.Todo) error
+	// PurgeDeleted permanently removes up to limit todos trashed before the
+	// cutoff and returns how many it removed.
+	PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error)
+	GetSubtree(ctx context.Context, userID, id int) ([]models.Todo, error)
+	ChildProgress(ctx context.Context, parentID int) (models.TodoProgress, error)
+	// CountSubtree counts every descendant of a todo, including those the
+	// caller can't see.
+	CountSubtree(ctx context.Context, id int) (int64, error)
+	// LockSubtree locks a todo, its subtasks and the caller's memberships of
+	// their lists until the transaction ends, so neither the tree nor the
+	// caller's roles change between checking and deleting it.
+	LockSubtree(ctx context.Context, userID, id int) error
+	FindTags(ctx context.Context, userID int, ids []int) ([]models.Tag, error)
+	SetTags(ctx context.Context, todo *models.Todo, tags []models.Tag) error
+	// EnsureTags returns userID's tags with the given names, ignoring case,
+	// creating the missing ones. Concurrent callers get the same tags.
+	EnsureTags(ctx context.Context, userID int, names []string) ([]models.Tag, error)
+	GetSeries(ctx context.Context, userID, rootID int) ([]models.Todo, error)
+	// LastOccurrence returns the highest occurrence number of the series,
+	// counting trashed occurrences too.
+	LastOccurrence(ctx context.Context, rootID int) (int, error)
+	// LockPositions holds the ordering lock of todo's list until the
+	// transaction ends; NextPosition takes it too.
+	LockPositions(ctx context.Context, todo *models.Todo) error
+	// NextPosition returns a rank key after the last todo in todo's list.
+	NextPosition(ctx context.Context, todo *models.Todo) (string, error)
+	// NeighbourPosition returns the closest key after (or before) position in
+	// todo's list, ignoring todo itself; "" when there is none.
+	NeighbourPosition(ctx context.Context, todo *models.Todo, position string, after bool) (string, error)
+	SetPosition(ctx context.Context, todo *models.Todo, position string) error
+	// Stream passes every todo visible to userID to fn, batchSize at a time, in ID order.
+	Stream(ctx context.Context, userID, batchSize int, fn func(todos []models.Todo) error) error
+	// ListOwned returns the user's own todos, without shared ones, in ID order.
+	ListOwned(ctx context.Context, userID int) ([]models.Todo, error)
+	// FindCalDAV returns the user's todo stored under a CalDAV resource name,
+	// or the one with the given ID if it never got a name of its own.
+	FindCalDAV(ctx context.Context, userID int, name string, id int) (*models.Todo, error)
+	// ChangedSince returns the user's todos with history after eventID,
+	// including ones now in the trash.
+	ChangedSince(ctx context.Context, userID, eventID int) ([]models.Todo, error)
+	// LatestEventID returns the newest history entry of the user's todos, 0 if none.
+	LatestEventID(ctx context.Context, userID int) (int, error)
+	// AddEvent appends to a todo's history; run it in the change's transaction.
+	AddEvent(ctx context.Context, event *models.TodoEvent) error
+	// ListEvents returns a todo's history oldest first.
+	ListEvents(ctx context.Context, todoID int) ([]models.TodoEvent, error)
+	// ListBlockers and ListBlocked return the todos visible to userID that
+	// block todoID or that todoID blocks.
+	ListBlockers(ctx context.Context, userID, todoID int) ([]models.Todo, error)
+	ListBlocked(ctx context.Context, userID, todoID int) ([]models.Todo, error)
+	// OpenBlockers counts todoID's blockers that are neither done nor in the
+	// trash, whoever can see them.
+	OpenBlockers(ctx context.Context, todoID int) (int64, error)
+	// IsBlockedBy reports whether todoID waits on blockerID, directly or
+	// through other todos.
+	IsBlockedBy(ctx context.Context, todoID, blockerID int) (bool, error)
+	// LockDependencies serializes dependency changes until the transaction ends.
+	LockDependencies(ctx context.Context) error
+	// AddDependency records that todoID is blocked by blockerID; false when it already was.
+	AddDependency(ctx context.Context, todoID, blockerID int) (bool, error)
+	RemoveDependency(ctx context.Context, todoID, blockerID int) error
+	// FindList returns a list visible to userID with their role on it, or nil.
+	FindList(ctx context.Context, userID, id int) (*models.TodoList, error)
+	// FindInList returns the list's todos among ids in position order, or all
+	// of them when ids is nil.
+	FindInList(ctx context.Context, listID int, ids []int) ([]models.Todo, error)
+	// MoveToList appends todos to the end of listID (nil = no list) and
+	// returns them as they are afterwards. It takes more than one statement,
+	// so run it inside Transaction.
+	MoveToList(ctx context.Context, todos []models.Todo, listID *int) ([]models.Todo, error)
+	// DeleteList removes the owner's list; move its todos out first.
+	DeleteList(ctx context.Context, userID, id int) error
+	// Transaction runs fn against a repository bound to one DB transaction;
+	// nested calls become savepoints.
+	Transaction(ctx context.Context, fn func(repo TodoRepository) error) error
 }
 
 type todoRepository struct {
@@ -25,18 +117,115 @@ func NewTodoRepository(db *gorm.DB) TodoRepository {
 	return &todoRepository{db: db}
 }
 
-func (r *todoRepository) GetAll() ([]models.Todo, error) {
+// todoSortColumns whitelists sortable columns so ORDER BY is never built from raw input.
+var todoSortColumns = map[string]string{
+	models.TodoSortCreatedAt: "created_at",
+	models.TodoSortUpdatedAt: "updated_at",
+	models.TodoSortTitle:     "title",
+	models.TodoSortPosition:  "position",
+}
+
+// List returns one keyset page; the id column breaks ties between equal sort values.
+func (r *todoRepository) List(ctx context.Context, userID int, filter models.TodoFilter) ([]models.Todo, error) {
+	column, ok := todoSortColumns[filter.Sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction, comparator := "ASC", ">"
+	if filter.Desc {
+		direction, comparator = "DESC", "<"
+	}
+
+	query := r.filtered(ctx, userID, filter)
+	if filter.After != nil {
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparator), filter.After.Value, filter.After.ID)
+	}
+
 	var todos []models.Todo
-	err := r.db.Order("created_at DESC").Find(&todos).Error
+	err := preloadTags(withCommentCount(query)).
+		Order(column + " " + direction).
+		Order("id " + direction).
+		Limit(filter.Limit).
+		Find(&todos).Error
 	if err != nil {
 		return nil, err
 	}
 	return todos, nil
 }
 
-func (r *todoRepository) GetByID(id int) (*models.Todo, error) {
+// Count ignores the cursor so every page reports the same total.
+func (r *todoRepository) Count(ctx context.Context, userID int, filter models.TodoFilter) (int64, error) {
+	var total int64
+	err := r.filtered(ctx, userID, filter).Count(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// ListDue returns open todos due in [from, to); a nil bound is left open.
+func (r *todoRepository) ListDue(ctx context.Context, userID int, from, to *time.Time) ([]models.Todo, error) {
+	query := visibleTo(r.db.WithContext(ctx), userID).Where("completed = ? AND due_at IS NOT NULL", false)
+	if from != nil {
+		query = query.Where("due_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("due_at < ?", *to)
+	}
+
+	var todos []models.Todo
+	err := preloadTags(withCommentCount(query)).Order("due_at ASC").Order("id ASC").Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) filtered(ctx context.Context, userID int, filter models.TodoFilter) *gorm.DB {
+	query := visibleTo(r.db.WithContext(ctx).Model(&models.Todo{}), userID)
+	if filter.ListID != nil {
+		query = query.Where("list_id = ?", *filter.ListID)
+	}
+	if filter.AssigneeID != nil {
+		query = query.Where("assignee_id = ?", *filter.AssigneeID)
+	}
+	if filter.Completed != nil {
+		query = query.Where("completed = ?", *filter.Completed)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Ready {
+		query = query.Where("NOT todos.completed AND NOT EXISTS (" + fmt.Sprintf(openBlockerOf, "todos.id") + ")")
+	}
+	if filter.Priority != "" {
+		query = query.Where("priority = ?", filter.Priority)
+	}
+	if filter.Tag != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM todo_tags tt JOIN tags t ON t.id = tt.tag_id
+			WHERE tt.todo_id = todos.id AND LOWER(t.name) = LOWER(?)
+		)`, filter.Tag)
+	}
+	if filter.Query != "" {
+		pattern := containsPattern(filter.Query)
+		query = query.Where(`(title ILIKE ? ESCAPE '\' OR description ILIKE ? ESCAPE '\')`, pattern, pattern)
+	}
+	return query
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern turns user input into an ILIKE pattern that matches it
+// literally anywhere in the column; pair it with ESCAPE '\'.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+func (r *todoRepository) GetByID(ctx context.Context, userID, id int) (*models.Todo, error) {
 	var todo models.Todo
-	err := r.db.Where("id = ?", id).First(&todo).Error
+	err := preloadTags(withCommentCount(visibleTo(r.db.WithContext(ctx), userID))).Where("id = ?", id).First(&todo).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -46,23 +235,379 @@ func (r *todoRepository) GetByID(id int) (*models.Todo, error) {
 	return &todo, nil
 }
 
-func (r *todoRepository) Create(todo *models.Todo) error {
-	now := time.Now()
+// Create appends the todo to the end of its list unless it already has a position.
+func (r *todoRepository) Create(ctx context.Context, todo *models.Todo) error {
+	now := utcNow()
 	todo.CreatedAt = now
 	todo.UpdatedAt = now
-	todo.Completed = false
-	return r.db.Create(todo).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if todo.Position == "" {
+			position, err := nextPosition(tx, todo)
+			if err != nil {
+				return err
+			}
+			todo.Position = position
+		}
+		return tx.Omit("Tags.*").Create(todo).Error
+	})
+}
+
+func (r *todoRepository) Update(ctx context.Context, todo *models.Todo) error {
+	todo.UpdatedAt = utcNow()
+	result := r.db.WithContext(ctx).Model(&models.Todo{}).
+		Where("id = ? AND user_id = ?", todo.ID, todo.UserID).
+		Updates(map[string]any{
+			"title":           todo.Title,
+			"description":     todo.Description,
+			"status":          todo.Status,
+			"due_at":          todo.DueAt,
+			"remind_at":       todo.RemindAt,
+			"priority":        todo.Priority,
+			"recurrence_rule": todo.RecurrenceRule,
+			"timezone":        todo.Timezone,
+			"list_id":         todo.ListID,
+			"assignee_id":     todo.AssigneeID,
+			"position":        todo.Position,
+			"updated_at":      todo.UpdatedAt,
+			// Left alone unless remind_at changes, so a reminder the scheduler
+			// claimed meanwhile isn't reset by an unrelated edit.
+			"reminded_at": gorm.Expr("CASE WHEN remind_at IS DISTINCT FROM ? THEN NULL ELSE reminded_at END", todo.RemindAt),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	// A new list or assignee may cut someone off from the todo.
+	return stopTimers(r.db.WithContext(ctx), []int{todo.ID})
+}
+
+// Delete soft-deletes a todo. With cascade the whole subtree goes in the same
+// statement, so the service must have checked the caller may edit every
+// subtask; with promote the direct children move up to the grandparent first.
+// Timers running on the trashed todos are stopped.
+func (r *todoRepository) Delete(ctx context.Context, userID, id int, mode models.SubtaskDeleteMode) error {
+	tx := r.db.WithContext(ctx)
+	var todo models.Todo
+	err := visibleTo(tx, userID).Where("id = ?", id).First(&todo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return sql.ErrNoRows
+	}
+	if err != nil {
+		return err
+	}
+
+	ids := []int{id}
+	switch mode {
+	case models.SubtaskDeleteCascade:
+		var descendants []int
+		if err := tx.Raw(subtreeIDsQuery, id).Scan(&descendants).Error; err != nil {
+			return err
+		}
+		ids = append(ids, descendants...)
+	case models.SubtaskDeletePromote:
+		err := tx.Model(&models.Todo{}).
+			Where("parent_id = ?", id).
+			Update("parent_id", todo.ParentID).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("id IN ?", ids).Delete(&models.Todo{}).Error; err != nil {
+		return err
+	}
+	return stopTimers(tx, ids)
+}
+
+// ListTrash returns the deleted todos userID can see, most recently deleted first.
+func (r *todoRepository) ListTrash(ctx context.Context, userID int) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := preloadTags(visibleTo(r.db.WithContext(ctx).Unscoped(), userID)).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Order("id DESC").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) GetDeleted(ctx context.Context, userID, id int) (*models.Todo, error) {
+	var todo models.Todo
+	err := preloadTags(visibleTo(r.db.WithContext(ctx).Unscoped(), userID)).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&todo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// trashedSubtreeQuery finds the subtasks removed by the same cascade delete
+// as a todo; one delete stamps every row with the same deleted_at.
+const trashedSubtreeQuery = `
+WITH RECURSIVE subtree AS (
+	SELECT id FROM todos WHERE parent_id = ? AND deleted_at = ?
+	UNION ALL
+	SELECT t.id FROM todos t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at = ?
+)
+SELECT id FROM subtree`
+
+// Restore brings a todo back together with the subtasks its delete took
+// with it, each at the end of its list since its old position may have been
+// reused. If its parent is still in the trash it comes back top-level.
+func (r *todoRepository) Restore(ctx context.Context, todo *models.Todo) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deletedAt := todo.DeletedAt.Time
+		var descendants []int
+		if err := tx.Raw(trashedSubtreeQuery, todo.ID, deletedAt, deletedAt).Scan(&descendants).Error; err != nil {
+			return err
+		}
+
+		var restored []models.Todo
+		err := tx.Unscoped().
+			Where("id IN ? AND deleted_at IS NOT NULL", append([]int{todo.ID}, descendants...)).
+			Order("position").
+			Find(&restored).Error
+		if err != nil {
+			return err
+		}
+		if len(restored) == 0 {
+			return sql.ErrNoRows
+		}
+
+		todo.UpdatedAt = utcNow()
+		for _, row := range restored {
+			position, err := nextPosition(tx, &row)
+			if err != nil {
+				return err
+			}
+			err = tx.Unscoped().Model(&models.Todo{}).
+				Where("id = ?", row.ID).
+				Updates(map[string]any{
+					"deleted_at": nil,
+					"position":   position,
+					"updated_at": todo.UpdatedAt,
+				}).Error
+			if err != nil {
+				return err
+			}
+			if row.ID == todo.ID {
+				todo.Position = position
+			}
+		}
+		todo.DeletedAt = gorm.DeletedAt{}
+
+		if todo.ParentID != nil {
+			var parent models.Todo
+			err := tx.Select("id").Where("id = ?", *todo.ParentID).First(&parent).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				todo.ParentID = nil
+				return tx.Model(&models.Todo{}).Where("id = ?", todo.ID).Update("parent_id", nil).Error
+			}
+			return err
+		}
+		return nil
+	})
 }
 
-func (r *todoRepository) Update(todo *models.Todo) error {
-	todo.UpdatedAt = time.Now()
-	result := r.db.Model(&models.Todo{}).
+func (r *todoRepository) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []int
+		err := tx.Unscoped().Model(&models.Todo{}).
+			Where("deleted_at < ?", before).
+			Order("id").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		// Anything still pointing at a purged parent keeps living top-level.
+		err = tx.Unscoped().Model(&models.Todo{}).
+			Where("parent_id IN ? AND id NOT IN ?", ids, ids).
+			Update("parent_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Todo{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
+
+// subtreeIDsQuery walks parent_id links downwards from a todo, excluding the
+// todo itself. Subtasks belong to their parent's tree whoever created them.
+const subtreeIDsQuery = `
+WITH RECURSIVE subtree AS (
+	SELECT id FROM todos WHERE parent_id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT t.id FROM todos t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+)
+SELECT id FROM subtree`
+
+// GetSubtree returns every descendant of a todo that userID can see,
+// ordered so parents come first.
+func (r *todoRepository) GetSubtree(ctx context.Context, userID, id int) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := preloadTags(visibleTo(r.db.WithContext(ctx), userID)).
+		Where("id IN ("+subtreeIDsQuery+")", id).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// ChildProgress counts every direct subtask so roll-up doesn't depend on who looks.
+func (r *todoRepository) ChildProgress(ctx context.Context, parentID int) (models.TodoProgress, error) {
+	var progress models.TodoProgress
+	err := r.db.WithContext(ctx).Model(&models.Todo{}).
+		Select("COUNT(*) FILTER (WHERE completed) AS done, COUNT(*) AS total").
+		Where("parent_id = ?", parentID).
+		Scan(&progress).Error
+	return progress, err
+}
+
+func (r *todoRepository) LockSubtree(ctx context.Context, userID, id int) error {
+	db := r.db.WithContext(ctx)
+	var ids []int
+	err := db.Raw("SELECT id FROM todos WHERE id = ? OR id IN ("+subtreeIDsQuery+") FOR UPDATE", id, id).
+		Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	return db.Exec(`SELECT 1 FROM list_members
+		WHERE user_id = ? AND list_id IN (SELECT list_id FROM todos WHERE id IN ?)
+		FOR SHARE`, userID, ids).Error
+}
+
+func (r *todoRepository) CountSubtree(ctx context.Context, id int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) FROM ("+subtreeIDsQuery+") AS descendants", id).Scan(&count).Error
+	return count, err
+}
+
+// FindTags returns the subset of ids that are tags owned by userID.
+func (r *todoRepository) FindTags(ctx context.Context, userID int, ids []int) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.WithContext(ctx).Where("user_id = ? AND id IN ?", userID, ids).Order("name").Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// SetTags replaces the todo's tag links without rewriting the tags themselves.
+func (r *todoRepository) SetTags(ctx context.Context, todo *models.Todo, tags []models.Tag) error {
+	if err := r.db.WithContext(ctx).Model(todo).Omit("Tags.*").Association("Tags").Replace(tags); err != nil {
+		return err
+	}
+	todo.Tags = tags
+	return nil
+}
+
+func (r *todoRepository) EnsureTags(ctx context.Context, userID int, names []string) ([]models.Tag, error) {
+	lowered := make([]string, len(names))
+	missing := make([]models.Tag, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+		missing[i] = models.Tag{UserID: userID, Name: name}
+	}
+	// Names that already exist, or that a concurrent call just added, hit
+	// uni_tags_user_name and are read back below.
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var found []models.Tag
+	err = r.db.WithContext(ctx).Where("user_id = ? AND LOWER(name) IN ?", userID, lowered).Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.Tag, len(found))
+	for _, tag := range found {
+		byName[strings.ToLower(tag.Name)] = tag
+	}
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range lowered {
+		tag, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("tag %q was not created", name)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// GetSeries returns every occurrence of the series started by rootID.
+func (r *todoRepository) GetSeries(ctx context.Context, userID, rootID int) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := preloadTags(visibleTo(r.db.WithContext(ctx), userID)).
+		Where("(id = ? OR series_id = ?)", rootID, rootID).
+		Order("occurrence ASC").
+		Order("id ASC").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) LastOccurrence(ctx context.Context, rootID int) (int, error) {
+	var last int
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Todo{}).
+		Select("COALESCE(MAX(occurrence), 0)").
+		Where("(id = ? OR series_id = ?)", rootID, rootID).
+		Scan(&last).Error
+	return last, err
+}
+
+func (r *todoRepository) LockPositions(ctx context.Context, todo *models.Todo) error {
+	return lockPositions(r.db.WithContext(ctx), todo)
+}
+
+func (r *todoRepository) NextPosition(ctx context.Context, todo *models.Todo) (string, error) {
+	return nextPosition(r.db.WithContext(ctx), todo)
+}
+
+func (r *todoRepository) NeighbourPosition(ctx context.Context, todo *models.Todo, position string, after bool) (string, error) {
+	query := positionScope(r.db.WithContext(ctx), todo).Where("id <> ?", todo.ID)
+	if after {
+		query = query.Select("MIN(position)").Where("position > ?", position)
+	} else {
+		query = query.Select("MAX(position)").Where("position < ?", position)
+	}
+
+	var neighbour sql.NullString
+	if err := query.Row().Scan(&neighbour); err != nil {
+		return "", err
+	}
+	return neighbour.String, nil
+}
+
+func (r *todoRepository) SetPosition(ctx context.Context, todo *models.Todo, position string) error {
+	todo.UpdatedAt = utcNow()
+	result := r.db.WithContext(ctx).Model(&models.Todo{}).
 		Where("id = ?", todo.ID).
 		Updates(map[string]any{
-			"title":       todo.Title,
-			"description": todo.Description,
-			"completed":   todo.Completed,
-			"updated_at":  todo.UpdatedAt,
+			"position":   position,
+			"updated_at": todo.UpdatedAt,
 		})
 	if result.Error != nil {
 		return result.Error
@@ -70,11 +615,133 @@ func (r *todoRepository) Update(todo *models.Todo) error {
 	if result.RowsAffected == 0 {
 		return sql.ErrNoRows
 	}
+	todo.Position = position
 	return nil
 }
 
-func (r *todoRepository) Delete(id int) error {
-	result := r.db.Where("id = ?", id).Delete(&models.Todo{})
+func (r *todoRepository) Stream(ctx context.Context, userID, batchSize int, fn func(todos []models.Todo) error) error {
+	var batch []models.Todo
+	return preloadTags(visibleTo(r.db.WithContext(ctx), userID)).
+		Order("id ASC").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+func (r *todoRepository) ListOwned(ctx context.Context, userID int) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := preloadTags(r.db.WithContext(ctx)).Where("user_id = ?", userID).Order("id ASC").Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) FindCalDAV(ctx context.Context, userID int, name string, id int) (*models.Todo, error) {
+	var todo models.Todo
+	err := preloadTags(r.db.WithContext(ctx)).
+		Where("user_id = ?", userID).
+		Where("(caldav_name = ? OR (caldav_name IS NULL AND id = ?))", name, id).
+		First(&todo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+func (r *todoRepository) ChangedSince(ctx context.Context, userID, eventID int) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := preloadTags(r.db.WithContext(ctx).Unscoped()).
+		Where("user_id = ?", userID).
+		Where("id IN (SELECT todo_id FROM todo_events WHERE id > ?)", eventID).
+		Order("id ASC").
+		Find(&todos).Error
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) LatestEventID(ctx context.Context, userID int) (int, error) {
+	var latest sql.NullInt64
+	err := r.db.WithContext(ctx).Table("todo_events").
+		Joins("JOIN todos ON todos.id = todo_events.todo_id").
+		Where("todos.user_id = ?", userID).
+		Select("MAX(todo_events.id)").
+		Row().Scan(&latest)
+	return int(latest.Int64), err
+}
+
+func (r *todoRepository) AddEvent(ctx context.Context, event *models.TodoEvent) error {
+	event.CreatedAt = utcNow()
+	return r.db.WithContext(ctx).Omit("User").Create(event).Error
+}
+
+func (r *todoRepository) ListEvents(ctx context.Context, todoID int) ([]models.TodoEvent, error) {
+	var events []models.TodoEvent
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("todo_id = ?", todoID).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *todoRepository) FindList(ctx context.Context, userID, id int) (*models.TodoList, error) {
+	var list models.TodoList
+	err := visibleLists(r.db.WithContext(ctx), userID).Where("todo_lists.id = ?", id).First(&list).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *todoRepository) FindInList(ctx context.Context, listID int, ids []int) ([]models.Todo, error) {
+	query := r.db.WithContext(ctx).Where("list_id = ?", listID)
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+
+	var todos []models.Todo
+	if err := query.Order("position").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+func (r *todoRepository) MoveToList(ctx context.Context, todos []models.Todo, listID *int) ([]models.Todo, error) {
+	if len(todos) == 0 {
+		return nil, nil
+	}
+
+	tx := r.db.WithContext(ctx)
+	if err := appendToScope(tx, todos, listID); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	var moved []models.Todo
+	if err := tx.Where("id IN ?", ids).Order("position").Find(&moved).Error; err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+func (r *todoRepository) DeleteList(ctx context.Context, userID, id int) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.TodoList{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -83,3 +750,30 @@ func (r *todoRepository) Delete(id int) error {
 	}
 	return nil
 }
+
+func (r *todoRepository) Transaction(ctx context.Context, fn func(repo TodoRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&todoRepository{db: tx})
+	})
+}
+
+// visibleTo limits a todos query to what userID owns, is assigned or
+// reaches through a list.
+func visibleTo(db *gorm.DB, userID int) *gorm.DB {
+	return db.Where(`(todos.user_id = ? OR todos.assignee_id = ? OR todos.list_id IN (
+		SELECT id FROM todo_lists WHERE user_id = ?
+		UNION
+		SELECT list_id FROM list_members WHERE user_id = ?
+	))`, userID, userID, userID, userID)
+}
+
+// withCommentCount fills Todo.CommentCount alongside the todo's own columns.
+func withCommentCount(db *gorm.DB) *gorm.DB {
+	return db.Select("todos.*, (SELECT COUNT(*) FROM todo_comments WHERE todo_comments.todo_id = todos.id) AS comment_count")
+}
+
+func preloadTags(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tags.name")
+	})
+}
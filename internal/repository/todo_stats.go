@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+// created_at and updated_at are TIMESTAMP columns holding UTC: the
+// connection runs in UTC for column defaults (see database.NewGormConnection),
+// and the repositories write utcNow. These expressions turn them into
+// wall-clock time in the caller's timezone.
+const (
+	localCreatedAt = "((created_at AT TIME ZONE 'UTC') AT TIME ZONE @tz)"
+	localUpdatedAt = "((updated_at AT TIME ZONE 'UTC') AT TIME ZONE @tz)"
+)
+
+// utcNow is the time to write into TIMESTAMP columns. The driver stores a
+// time.Time's wall clock there, so a local time would be off by the offset.
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+const ownedLiveTodos = "user_id = @user AND deleted_at IS NULL"
+
+const statsCountsQuery = `
+SELECT COUNT(*) FILTER (WHERE NOT completed) AS open, COUNT(*) FILTER (WHERE completed) AS done
+FROM todos
+WHERE ` + ownedLiveTodos + `
+	AND CAST(` + localCreatedAt + ` AS date) BETWEEN CAST(@from AS date) AND CAST(@to AS date)`
+
+// statsSeriesQuery generates every bucket of the range so empty days and
+// weeks are reported as zero. Weeks start on Monday.
+const statsSeriesQuery = `
+SELECT to_char(buckets.start, 'YYYY-MM-DD') AS start, COUNT(done.id) AS count
+FROM generate_series(
+	date_trunc(@interval, CAST(@from AS timestamp)),
+	CAST(@to AS timestamp),
+	CAST(@step AS interval)
+) AS buckets(start)
+LEFT JOIN (
+	SELECT id, ` + localUpdatedAt + ` AS done_at
+	FROM todos
+	WHERE ` + ownedLiveTodos + ` AND completed
+) done ON date_trunc(@interval, done.done_at) = buckets.start
+	AND CAST(done.done_at AS date) BETWEEN CAST(@from AS date) AND CAST(@to AS date)
+GROUP BY buckets.start
+ORDER BY buckets.start`
+
+const statsAvgCompletionQuery = `
+SELECT AVG(EXTRACT(EPOCH FROM updated_at - created_at))
+FROM todos
+WHERE ` + ownedLiveTodos + ` AND completed
+	AND CAST(` + localUpdatedAt + ` AS date) BETWEEN CAST(@from AS date) AND CAST(@to AS date)`
+
+// statsStreakQuery numbers the completion days newest first; consecutive
+// days then share day + row number, so the newest island is the streak.
+const statsStreakQuery = `
+WITH days AS (
+	SELECT DISTINCT CAST(` + localUpdatedAt + ` AS date) AS day
+	FROM todos
+	WHERE ` + ownedLiveTodos + ` AND completed
+), islands AS (
+	SELECT day, day + CAST(ROW_NUMBER() OVER (ORDER BY day DESC) AS integer) AS island FROM days
+)
+SELECT COUNT(*) FROM islands
+WHERE island = (SELECT island FROM islands ORDER BY day DESC LIMIT 1)
+	AND (SELECT MAX(day) FROM days) >= CAST(@today AS date) - 1`
+
+// Stats computes the completion metrics of the user's own live todos.
+func (r *todoRepository) Stats(ctx context.Context, userID int, filter models.TodoStatsFilter) (*models.TodoStats, error) {
+	args := map[string]any{
+		"user":     userID,
+		"tz":       filter.Timezone,
+		"from":     filter.From,
+		"to":       filter.To,
+		"today":    filter.Today,
+		"interval": filter.Interval,
+		"step":     "1 " + filter.Interval,
+	}
+	stats := &models.TodoStats{Completed: []models.TodoStatsBucket{}}
+
+	if err := r.db.WithContext(ctx).Raw(statsCountsQuery, args).Row().Scan(&stats.Open, &stats.Done); err != nil {
+		return nil, err
+	}
+	if err := r.db.WithContext(ctx).Raw(statsSeriesQuery, args).Scan(&stats.Completed).Error; err != nil {
+		return nil, err
+	}
+	var avg sql.NullFloat64
+	if err := r.db.WithContext(ctx).Raw(statsAvgCompletionQuery, args).Row().Scan(&avg); err != nil {
+		return nil, err
+	}
+	if avg.Valid {
+		stats.AvgCompletionSeconds = &avg.Float64
+	}
+	if err := r.db.WithContext(ctx).Raw(statsStreakQuery, args).Row().Scan(&stats.CurrentStreak); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
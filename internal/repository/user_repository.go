@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,10 +11,10 @@ import (
 )
 
 type UserRepository interface {
-	Create(user *models.User) error
-	GetByEmail(email string) (*models.User, error)
-	GetByID(id int) (*models.User, error)
-	GetAll() ([]models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetAll(ctx context.Context) ([]models.User, error)
 }
 
 type userRepository struct {
@@ -24,13 +25,13 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
-func (r *userRepository) GetByEmail(email string) (*models.User, error) {
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, sql.ErrNoRows
 	}
@@ -40,9 +41,9 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) GetByID(id int) (*models.User, error) {
+func (r *userRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
 	var user models.User
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Select("id", "name", "email", "mobile", "created_at", "updated_at").
 		Where("id = ?", id).
 		First(&user).Error
@@ -55,9 +56,9 @@ func (r *userRepository) GetByID(id int) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) GetAll() ([]models.User, error) {
+func (r *userRepository) GetAll(ctx context.Context) ([]models.User, error) {
 	var users []models.User
-	err := r.db.
+	err := r.db.WithContext(ctx).
 		Select("id", "name", "email", "mobile", "created_at", "updated_at").
 		Find(&users).Error
 	if err != nil {
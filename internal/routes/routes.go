@@ -11,11 +11,20 @@ import (
 )
 
 type RouteHandlers struct {
-	TodoHandler     *handlers.TodoHandler
-	CategoryHandler *handlers.CategoryHandler
-	BlogHandler     *handlers.BlogHandler
-	UserHandler     *handlers.UserHandler
-	JWTSecret       string // Secret injected once and used only for protected route middleware.
+	TodoHandler           *handlers.TodoHandler
+	TodoListHandler       *handlers.TodoListHandler
+	TodoCommentHandler    *handlers.TodoCommentHandler
+	TodoDependencyHandler *handlers.TodoDependencyHandler
+	TodoAttachmentHandler *handlers.TodoAttachmentHandler
+	TimeEntryHandler      *handlers.TimeEntryHandler
+	TodoQuickAddHandler   *handlers.TodoQuickAddHandler
+	TagHandler            *handlers.TagHandler
+	CategoryHandler       *handlers.CategoryHandler
+	BlogHandler           *handlers.BlogHandler
+	UserHandler           *handlers.UserHandler
+	AppPasswordHandler    *handlers.AppPasswordHandler
+	CalDAVHandler         *handlers.CalDAVHandler
+	JWTSecret             string // Secret injected once and used only for protected route middleware.
 }
 
 func RegisterRoutes(router *echo.Echo, routeHandlers RouteHandlers) {
@@ -26,13 +35,80 @@ func RegisterRoutes(router *echo.Echo, routeHandlers RouteHandlers) {
 		return c.JSON(http.StatusOK, dto.SuccessResponse("OK", nil))
 	})
 
-	// Todos
+	// Todos (protected, scoped to the token's user and their shared lists)
 	todos := api.Group("/todos")
+	todos.Use(middleware.JWTMiddleware(routeHandlers.JWTSecret))
 	todos.GET("", routeHandlers.TodoHandler.GetTodos)
 	todos.POST("", routeHandlers.TodoHandler.CreateTodo)
+	todos.POST("/quick", routeHandlers.TodoQuickAddHandler.QuickAddTodo)
+	todos.POST("/bulk", routeHandlers.TodoHandler.BulkTodos)
+	todos.GET("/export", routeHandlers.TodoHandler.ExportTodos)
+	todos.POST("/import", routeHandlers.TodoHandler.ImportTodos)
+	todos.GET("/overdue", routeHandlers.TodoHandler.GetOverdueTodos)
+	todos.GET("/due-today", routeHandlers.TodoHandler.GetTodosDueToday)
+	todos.GET("/upcoming", routeHandlers.TodoHandler.GetUpcomingTodos)
+	todos.GET("/stats", routeHandlers.TodoHandler.GetTodoStats)
+	todos.GET("/board", routeHandlers.TodoHandler.GetTodoBoard)
+	todos.GET("/trash", routeHandlers.TodoHandler.GetTrashedTodos)
 	todos.GET("/:id", routeHandlers.TodoHandler.GetTodo)
 	todos.PUT("/:id", routeHandlers.TodoHandler.UpdateTodo)
 	todos.DELETE("/:id", routeHandlers.TodoHandler.DeleteTodo)
+	todos.POST("/:id/restore", routeHandlers.TodoHandler.RestoreTodo)
+	todos.POST("/:id/move", routeHandlers.TodoHandler.MoveTodo)
+	todos.PUT("/:id/assignee", routeHandlers.TodoHandler.AssignTodo)
+	todos.DELETE("/:id/assignee", routeHandlers.TodoHandler.UnassignTodo)
+	todos.GET("/:id/history", routeHandlers.TodoHandler.GetTodoHistory)
+	todos.GET("/:id/comments", routeHandlers.TodoCommentHandler.GetComments)
+	todos.POST("/:id/comments", routeHandlers.TodoCommentHandler.CreateComment)
+	todos.PUT("/:id/comments/:comment_id", routeHandlers.TodoCommentHandler.UpdateComment)
+	todos.DELETE("/:id/comments/:comment_id", routeHandlers.TodoCommentHandler.DeleteComment)
+	todos.GET("/:id/dependencies", routeHandlers.TodoDependencyHandler.GetDependencies)
+	todos.POST("/:id/dependencies", routeHandlers.TodoDependencyHandler.AddDependency)
+	todos.DELETE("/:id/dependencies/:blocked_by_id", routeHandlers.TodoDependencyHandler.RemoveDependency)
+	todos.GET("/:id/attachments", routeHandlers.TodoAttachmentHandler.GetAttachments)
+	todos.POST("/:id/attachments", routeHandlers.TodoAttachmentHandler.UploadAttachment)
+	todos.GET("/:id/attachments/:attachment_id", routeHandlers.TodoAttachmentHandler.DownloadAttachment)
+	todos.DELETE("/:id/attachments/:attachment_id", routeHandlers.TodoAttachmentHandler.DeleteAttachment)
+	todos.POST("/:id/timer/start", routeHandlers.TimeEntryHandler.StartTimer)
+	todos.POST("/:id/timer/stop", routeHandlers.TimeEntryHandler.StopTimer)
+	todos.GET("/:id/time-entries", routeHandlers.TimeEntryHandler.GetTimeEntries)
+	todos.POST("/:id/time-entries", routeHandlers.TimeEntryHandler.CreateTimeEntry)
+	todos.DELETE("/:id/time-entries/:entry_id", routeHandlers.TimeEntryHandler.DeleteTimeEntry)
+	todos.GET("/:id/series", routeHandlers.TodoHandler.GetTodoSeries)
+	todos.PUT("/:id/series", routeHandlers.TodoHandler.UpdateTodoSeries)
+	todos.DELETE("/:id/series", routeHandlers.TodoHandler.StopTodoSeries)
+
+	// Time tracking across todos (protected, the token's user's own time)
+	timeEntries := api.Group("/time-entries")
+	timeEntries.Use(middleware.JWTMiddleware(routeHandlers.JWTSecret))
+	timeEntries.GET("/running", routeHandlers.TimeEntryHandler.GetRunningTimer)
+	timeEntries.GET("/report", routeHandlers.TimeEntryHandler.GetTimeReport)
+
+	// Todo lists (protected; owned and shared with the token's user)
+	lists := api.Group("/lists")
+	lists.Use(middleware.JWTMiddleware(routeHandlers.JWTSecret))
+	lists.GET("", routeHandlers.TodoListHandler.GetLists)
+	lists.POST("", routeHandlers.TodoListHandler.CreateList)
+	lists.GET("/:id", routeHandlers.TodoListHandler.GetList)
+	lists.PUT("/:id", routeHandlers.TodoListHandler.UpdateList)
+	lists.DELETE("/:id", routeHandlers.TodoListHandler.DeleteList)
+	lists.POST("/:id/archive", routeHandlers.TodoListHandler.ArchiveList)
+	lists.POST("/:id/unarchive", routeHandlers.TodoListHandler.UnarchiveList)
+	lists.GET("/:id/open-count", routeHandlers.TodoListHandler.CountOpenTodos)
+	lists.POST("/:id/move", routeHandlers.TodoListHandler.MoveTodos)
+	lists.GET("/:id/members", routeHandlers.TodoListHandler.GetMembers)
+	lists.POST("/:id/members", routeHandlers.TodoListHandler.AddMember)
+	lists.PUT("/:id/members/:user_id", routeHandlers.TodoListHandler.UpdateMember)
+	lists.DELETE("/:id/members/:user_id", routeHandlers.TodoListHandler.RemoveMember)
+
+	// Tags (protected, per user)
+	tags := api.Group("/tags")
+	tags.Use(middleware.JWTMiddleware(routeHandlers.JWTSecret))
+	tags.GET("", routeHandlers.TagHandler.GetTags)
+	tags.POST("", routeHandlers.TagHandler.CreateTag)
+	tags.GET("/:id", routeHandlers.TagHandler.GetTag)
+	tags.PUT("/:id", routeHandlers.TagHandler.UpdateTag)
+	tags.DELETE("/:id", routeHandlers.TagHandler.DeleteTag)
 
 	// Categories
 	categories := api.Group("/categories")
@@ -47,10 +123,12 @@ func RegisterRoutes(router *echo.Echo, routeHandlers RouteHandlers) {
 	blogs.GET("", routeHandlers.BlogHandler.GetBlogs)
 	blogs.POST("", routeHandlers.BlogHandler.CreateBlog)
 	blogs.GET("/search", routeHandlers.BlogHandler.SearchBlogs)
+	blogs.GET("/trash", routeHandlers.BlogHandler.GetBlogTrash)
 	blogs.GET("/:id", routeHandlers.BlogHandler.GetBlog)
 	blogs.PUT("/:id", routeHandlers.BlogHandler.UpdateBlog)
 	blogs.DELETE("/:id", routeHandlers.BlogHandler.DeleteBlog)
 	blogs.PATCH("/:id/publish", routeHandlers.BlogHandler.PublishBlog)
+	blogs.POST("/:id/restore", routeHandlers.BlogHandler.RestoreBlog)
 
 	// Auth
 	auth := api.Group("/auth")
@@ -67,4 +145,18 @@ func RegisterRoutes(router *echo.Echo, routeHandlers RouteHandlers) {
 	users.POST("", routeHandlers.UserHandler.CreateUser)
 	users.GET("/profile", routeHandlers.UserHandler.Profile)
 	users.GET("", routeHandlers.UserHandler.GetUsers)
+	users.GET("/me/app-passwords", routeHandlers.AppPasswordHandler.GetAppPasswords)
+	users.POST("/me/app-passwords", routeHandlers.AppPasswordHandler.CreateAppPassword)
+	users.DELETE("/me/app-passwords/:id", routeHandlers.AppPasswordHandler.DeleteAppPassword)
+
+	// CalDAV (Basic auth with the account password or an app password)
+	router.Any("/.well-known/caldav", routeHandlers.CalDAVHandler.WellKnown)
+	calDAVMethods := []string{
+		http.MethodOptions, echo.PROPFIND, echo.REPORT,
+		http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete,
+	}
+	caldav := router.Group(handlers.CalDAVPrefix)
+	caldav.Use(middleware.BasicAuthMiddleware("todo-go-echo CalDAV", routeHandlers.CalDAVHandler.Authenticate))
+	caldav.Match(calDAVMethods, "", routeHandlers.CalDAVHandler.Handle)
+	caldav.Match(calDAVMethods, "/*", routeHandlers.CalDAVHandler.Handle)
 }
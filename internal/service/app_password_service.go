@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// appPasswordBytes of randomness give 24 base32 characters.
+const appPasswordBytes = 15
+
+type AppPasswordService interface {
+	GetAll(ctx context.Context, userID int) ([]models.AppPassword, error)
+	Create(ctx context.Context, userID int, req models.CreateAppPasswordRequest) (*models.NewAppPassword, error)
+	Delete(ctx context.Context, userID, id int) error
+}
+
+type appPasswordService struct {
+	repo repository.AppPasswordRepository
+}
+
+func NewAppPasswordService(repo repository.AppPasswordRepository) AppPasswordService {
+	return &appPasswordService{repo: repo}
+}
+
+func (s *appPasswordService) GetAll(ctx context.Context, userID int) ([]models.AppPassword, error) {
+	return s.repo.GetAll(ctx, userID)
+}
+
+// Create generates a password shown to the user once, in groups of four
+// characters for typing on a phone.
+func (s *appPasswordService) Create(ctx context.Context, userID int, req models.CreateAppPasswordRequest) (*models.NewAppPassword, error) {
+	secret := make([]byte, appPasswordBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	plain := strings.ToLower(base32.StdEncoding.EncodeToString(secret))
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	password := models.AppPassword{
+		UserID:   userID,
+		Name:     strings.TrimSpace(req.Name),
+		Password: string(hash),
+	}
+	if err := s.repo.Create(ctx, &password); err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(plain)/4)
+	for i := 0; i < len(plain); i += 4 {
+		groups = append(groups, plain[i:i+4])
+	}
+	return &models.NewAppPassword{AppPassword: password, Password: strings.Join(groups, "-")}, nil
+}
+
+func (s *appPasswordService) Delete(ctx context.Context, userID, id int) error {
+	return s.repo.Delete(ctx, userID, id)
+}
+
+// normalizeAppPassword undoes the grouping and any case changes a keyboard made.
+func normalizeAppPassword(password string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(password))
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+// ErrInvalidBlogQuery is returned for unsupported list parameters or a malformed cursor.
+var ErrInvalidBlogQuery = errors.New("invalid blog query")
+
+const (
+	defaultBlogPageSize = 20
+	maxBlogPageSize     = 100
+)
+
+// blogCursor is the opaque payload behind next_cursor. Like todoCursor it
+// pins the sort so it can't be replayed against a different order.
+type blogCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+func buildBlogFilter(query models.BlogListQuery) (models.BlogFilter, error) {
+	filter := models.BlogFilter{
+		CategoryID: query.CategoryID,
+		Author:     query.Author,
+		Status:     models.BlogStatus(query.Status),
+		Sort:       query.Sort,
+		Desc:       true,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+	}
+
+	if filter.CategoryID != nil && *filter.CategoryID <= 0 {
+		return filter, fmt.Errorf("%w: invalid category ID", ErrInvalidBlogQuery)
+	}
+
+	switch filter.Status {
+	case "", models.StatusDraft, models.StatusPublished:
+	default:
+		return filter, fmt.Errorf("%w: status must be draft or published", ErrInvalidBlogQuery)
+	}
+
+	if query.From != "" {
+		from, err := time.Parse(models.DueDateLayout, query.From)
+		if err != nil {
+			return filter, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidBlogQuery)
+		}
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, err := time.Parse(models.DueDateLayout, query.To)
+		if err != nil {
+			return filter, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidBlogQuery)
+		}
+		// to is inclusive, so the bound is the start of the next day.
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, fmt.Errorf("%w: from must not be after to", ErrInvalidBlogQuery)
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.BlogSortCreatedAt
+	case models.BlogSortCreatedAt, models.BlogSortUpdatedAt, models.BlogSortTitle, models.BlogSortViews:
+	default:
+		return filter, fmt.Errorf("%w: unsupported sort %q", ErrInvalidBlogQuery, query.Sort)
+	}
+
+	switch query.Order {
+	case "":
+		// Titles read A to Z; everything else newest or most viewed first.
+		filter.Desc = filter.Sort != models.BlogSortTitle
+	case "desc":
+	case "asc":
+		filter.Desc = false
+	default:
+		return filter, fmt.Errorf("%w: order must be asc or desc", ErrInvalidBlogQuery)
+	}
+
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = defaultBlogPageSize
+	case filter.Limit < 0 || filter.Limit > maxBlogPageSize:
+		return filter, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidBlogQuery, maxBlogPageSize)
+	}
+	if filter.Offset < 0 {
+		return filter, fmt.Errorf("%w: offset must not be negative", ErrInvalidBlogQuery)
+	}
+
+	if query.Cursor != "" {
+		if filter.Offset > 0 {
+			return filter, fmt.Errorf("%w: use either cursor or offset", ErrInvalidBlogQuery)
+		}
+		after, err := decodeBlogCursor(query.Cursor, filter)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = after
+	}
+
+	return filter, nil
+}
+
+func encodeBlogCursor(filter models.BlogFilter, last models.Blog) string {
+	cursor := blogCursor{Sort: filter.Sort, Desc: filter.Desc, ID: last.ID}
+	switch filter.Sort {
+	case models.BlogSortTitle:
+		cursor.Value = last.Title
+	case models.BlogSortViews:
+		cursor.Value = strconv.Itoa(last.Views)
+	case models.BlogSortUpdatedAt:
+		cursor.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeBlogCursor(encoded string, filter models.BlogFilter) (*models.BlogCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidBlogQuery)
+	}
+
+	var cursor blogCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidBlogQuery)
+	}
+	if cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+		return nil, fmt.Errorf("%w: cursor does not match sort order", ErrInvalidBlogQuery)
+	}
+
+	switch filter.Sort {
+	case models.BlogSortTitle:
+		return &models.BlogCursor{Value: cursor.Value, ID: cursor.ID}, nil
+	case models.BlogSortViews:
+		views, err := strconv.Atoi(cursor.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidBlogQuery)
+		}
+		return &models.BlogCursor{Value: views, ID: cursor.ID}, nil
+	}
+
+	value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidBlogQuery)
+	}
+	return &models.BlogCursor{Value: value, ID: cursor.ID}, nil
+}
@@ -3,19 +3,20 @@ package service
 import (
 	"context"
 	"database/sql"
-	"errors"
-	"strconv"
 
 	"github.com/manish-npx/todo-go-echo/internal/models"
 	"github.com/manish-npx/todo-go-echo/internal/repository"
 )
 
 type BlogService interface {
-	GetBlogs(ctx context.Context, categoryID, author, status string) ([]models.Blog, error)
+	// GetBlogs returns one page of blogs matching every filter in query.
+	GetBlogs(ctx context.Context, query models.BlogListQuery) (*models.BlogPage, error)
 	GetByID(ctx context.Context, id int) (*models.Blog, error)
 	Create(ctx context.Context, req models.CreateBlogRequest) (*models.Blog, error)
 	Update(ctx context.Context, id int, req models.UpdateBlogRequest) (*models.Blog, error)
 	Delete(ctx context.Context, id int) error
+	Trash(ctx context.Context) ([]models.Blog, error)
+	Restore(ctx context.Context, id int) (*models.Blog, error)
 	Search(ctx context.Context, query string) ([]models.Blog, error)
 	Publish(ctx context.Context, id int) (*models.Blog, error)
 }
@@ -32,21 +33,32 @@ func NewBlogService(blogRepo repository.BlogRepository, categoryRepo repository.
 	}
 }
 
-func (s *blogService) GetBlogs(ctx context.Context, categoryID, author, status string) ([]models.Blog, error) {
-	switch {
-	case categoryID != "":
-		categoryIDValue, err := strconv.Atoi(categoryID)
-		if err != nil {
-			return nil, errors.New("invalid category ID")
-		}
-		return s.blogRepo.GetByCategory(ctx, categoryIDValue)
-	case author != "":
-		return s.blogRepo.GetByAuthor(ctx, author)
-	case status == "published":
-		return s.blogRepo.GetPublished(ctx)
-	default:
-		return s.blogRepo.GetAll(ctx)
+func (s *blogService) GetBlogs(ctx context.Context, query models.BlogListQuery) (*models.BlogPage, error) {
+	filter, err := buildBlogFilter(query)
+	if err != nil {
+		return nil, err
 	}
+
+	// Fetch one extra row to learn whether another page exists.
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	blogs, err := s.blogRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.blogRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.BlogPage{Blogs: blogs, Total: total, Limit: pageSize, Offset: filter.Offset}
+	if len(blogs) > pageSize {
+		page.Blogs = blogs[:pageSize]
+		page.HasMore = true
+		page.NextCursor = encodeBlogCursor(filter, page.Blogs[pageSize-1])
+	}
+	return page, nil
 }
 
 func (s *blogService) GetByID(ctx context.Context, id int) (*models.Blog, error) {
@@ -128,6 +140,17 @@ func (s *blogService) Delete(ctx context.Context, id int) error {
 	return s.blogRepo.Delete(ctx, id)
 }
 
+func (s *blogService) Trash(ctx context.Context) ([]models.Blog, error) {
+	return s.blogRepo.GetTrash(ctx)
+}
+
+func (s *blogService) Restore(ctx context.Context, id int) (*models.Blog, error) {
+	if err := s.blogRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.blogRepo.GetByID(ctx, id)
+}
+
 func (s *blogService) Search(ctx context.Context, query string) ([]models.Blog, error) {
 	return s.blogRepo.Search(ctx, query)
 }
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+type blogRepoMock struct {
+	blogs []models.Blog
+}
+
+// compare orders blogs by the filter's sort column, then by id.
+func (m *blogRepoMock) compare(filter models.BlogFilter, a models.Blog, value any, id int) int {
+	var c int
+	switch filter.Sort {
+	case models.BlogSortTitle:
+		c = cmp.Compare(a.Title, value.(string))
+	case models.BlogSortViews:
+		c = cmp.Compare(a.Views, value.(int))
+	case models.BlogSortUpdatedAt:
+		c = a.UpdatedAt.Compare(value.(time.Time))
+	default:
+		c = a.CreatedAt.Compare(value.(time.Time))
+	}
+	if c == 0 {
+		c = cmp.Compare(a.ID, id)
+	}
+	if filter.Desc {
+		return -c
+	}
+	return c
+}
+
+func (m *blogRepoMock) sortValue(filter models.BlogFilter, blog models.Blog) any {
+	switch filter.Sort {
+	case models.BlogSortTitle:
+		return blog.Title
+	case models.BlogSortViews:
+		return blog.Views
+	case models.BlogSortUpdatedAt:
+		return blog.UpdatedAt
+	default:
+		return blog.CreatedAt
+	}
+}
+
+func (m *blogRepoMock) filtered(filter models.BlogFilter) []models.Blog {
+	var blogs []models.Blog
+	for _, blog := range m.blogs {
+		if filter.CategoryID != nil && (blog.CategoryID == nil || *blog.CategoryID != *filter.CategoryID) {
+			continue
+		}
+		if filter.Author != "" && !strings.Contains(strings.ToLower(blog.Author), strings.ToLower(filter.Author)) {
+			continue
+		}
+		if filter.Status != "" && blog.Status != filter.Status {
+			continue
+		}
+		if filter.From != nil && blog.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !blog.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		blogs = append(blogs, blog)
+	}
+	return blogs
+}
+
+func (m *blogRepoMock) List(ctx context.Context, filter models.BlogFilter) ([]models.Blog, error) {
+	blogs := m.filtered(filter)
+	slices.SortFunc(blogs, func(a, b models.Blog) int {
+		return m.compare(filter, a, m.sortValue(filter, b), b.ID)
+	})
+	if filter.After != nil {
+		blogs = slices.DeleteFunc(blogs, func(blog models.Blog) bool {
+			return m.compare(filter, blog, filter.After.Value, filter.After.ID) <= 0
+		})
+	} else {
+		blogs = blogs[min(filter.Offset, len(blogs)):]
+	}
+	return blogs[:min(filter.Limit, len(blogs))], nil
+}
+
+func (m *blogRepoMock) Count(ctx context.Context, filter models.BlogFilter) (int64, error) {
+	return int64(len(m.filtered(filter))), nil
+}
+
+func (m *blogRepoMock) GetByID(ctx context.Context, id int) (*models.Blog, error) {
+	for i := range m.blogs {
+		if m.blogs[i].ID == id {
+			return &m.blogs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *blogRepoMock) Create(ctx context.Context, blog *models.Blog) error {
+	blog.ID = len(m.blogs) + 1
+	m.blogs = append(m.blogs, *blog)
+	return nil
+}
+
+func (m *blogRepoMock) Update(ctx context.Context, blog *models.Blog) error {
+	for i := range m.blogs {
+		if m.blogs[i].ID == blog.ID {
+			m.blogs[i] = *blog
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (m *blogRepoMock) Delete(ctx context.Context, id int) error {
+	return errors.New("not implemented")
+}
+
+func (m *blogRepoMock) GetTrash(ctx context.Context) ([]models.Blog, error) {
+	return nil, nil
+}
+
+func (m *blogRepoMock) Restore(ctx context.Context, id int) error {
+	return errors.New("not implemented")
+}
+
+func (m *blogRepoMock) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (m *blogRepoMock) IncrementViews(ctx context.Context, id int) error {
+	return nil
+}
+
+func (m *blogRepoMock) Search(ctx context.Context, query string) ([]models.Blog, error) {
+	return nil, nil
+}
+
+func newBlogTestService() (BlogService, *blogRepoMock) {
+	news, howto := 1, 2
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC) }
+	repo := &blogRepoMock{blogs: []models.Blog{
+		{ID: 1, Title: "Alpha", Author: "Bob Smith", CategoryID: &news, Status: models.StatusPublished, Views: 5, CreatedAt: day(1)},
+		{ID: 2, Title: "Bravo", Author: "bob", CategoryID: &news, Status: models.StatusDraft, Views: 9, CreatedAt: day(2)},
+		{ID: 3, Title: "Charlie", Author: "Alice", CategoryID: &news, Status: models.StatusPublished, Views: 1, CreatedAt: day(3)},
+		{ID: 4, Title: "Delta", Author: "Bobby", CategoryID: &howto, Status: models.StatusPublished, Views: 7, CreatedAt: day(4)},
+		{ID: 5, Title: "Echo", Author: "Bob", CategoryID: &news, Status: models.StatusPublished, Views: 3, CreatedAt: day(5)},
+		{ID: 6, Title: "Foxtrot", Author: "Bob", Status: models.StatusPublished, Views: 2, CreatedAt: day(6)},
+	}}
+	return NewBlogService(repo, nil), repo
+}
+
+func blogIDs(blogs []models.Blog) []int {
+	ids := make([]int, len(blogs))
+	for i, blog := range blogs {
+		ids[i] = blog.ID
+	}
+	return ids
+}
+
+func TestBlogServiceGetBlogsCombinesFilters(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newBlogTestService()
+	news := 1
+
+	tests := []struct {
+		name      string
+		query     models.BlogListQuery
+		wantIDs   []int
+		wantTotal int64
+	}{
+		{name: "no filters, newest first", query: models.BlogListQuery{}, wantIDs: []int{6, 5, 4, 3, 2, 1}, wantTotal: 6},
+		{
+			name:      "category, author and status together",
+			query:     models.BlogListQuery{CategoryID: &news, Author: "bob", Status: "published"},
+			wantIDs:   []int{5, 1},
+			wantTotal: 2,
+		},
+		{name: "drafts only", query: models.BlogListQuery{Status: "draft"}, wantIDs: []int{2}, wantTotal: 1},
+		{
+			name:      "inclusive date range",
+			query:     models.BlogListQuery{From: "2026-10-02", To: "2026-10-04", Sort: "title"},
+			wantIDs:   []int{2, 3, 4},
+			wantTotal: 3,
+		},
+		{name: "most viewed first", query: models.BlogListQuery{Sort: "views", Limit: 3}, wantIDs: []int{2, 4, 1}, wantTotal: 6},
+		{name: "offset", query: models.BlogListQuery{Sort: "title", Limit: 2, Offset: 4}, wantIDs: []int{5, 6}, wantTotal: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := svc.GetBlogs(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("GetBlogs() error = %v", err)
+			}
+			if got := blogIDs(page.Blogs); !slices.Equal(got, tt.wantIDs) || page.Total != tt.wantTotal {
+				t.Fatalf("GetBlogs() = %v (total %d), want %v (total %d)", got, page.Total, tt.wantIDs, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBlogServiceGetBlogsPaginates(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newBlogTestService()
+
+	for _, sort := range []string{"created_at", "updated_at", "title", "views"} {
+		t.Run(sort, func(t *testing.T) {
+			query := models.BlogListQuery{Sort: sort, Limit: 4}
+			var ids []int
+			for range 3 {
+				page, err := svc.GetBlogs(ctx, query)
+				if err != nil {
+					t.Fatalf("GetBlogs() error = %v", err)
+				}
+				if page.Total != 6 || page.Limit != 4 {
+					t.Fatalf("GetBlogs() unexpected meta: %+v", page)
+				}
+				ids = append(ids, blogIDs(page.Blogs)...)
+				if !page.HasMore {
+					break
+				}
+				query.Cursor = page.NextCursor
+			}
+			slices.Sort(ids)
+			if !slices.Equal(ids, []int{1, 2, 3, 4, 5, 6}) {
+				t.Fatalf("GetBlogs() pages returned %v, want every blog once", ids)
+			}
+		})
+	}
+
+	page, err := svc.GetBlogs(ctx, models.BlogListQuery{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("GetBlogs() error = %v", err)
+	}
+	if !page.HasMore || page.Offset != 2 {
+		t.Fatalf("GetBlogs() unexpected offset page: %+v", page)
+	}
+	next, err := svc.GetBlogs(ctx, models.BlogListQuery{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("GetBlogs() error = %v", err)
+	}
+	if got := blogIDs(next.Blogs); !slices.Equal(got, []int{2, 1}) || next.HasMore {
+		t.Fatalf("GetBlogs() after offset page = %v, want [2 1]", got)
+	}
+}
+
+func TestBlogServiceGetBlogsRejectsInvalidQuery(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newBlogTestService()
+	first, err := svc.GetBlogs(ctx, models.BlogListQuery{Limit: 1})
+	if err != nil {
+		t.Fatalf("GetBlogs() error = %v", err)
+	}
+	zero := 0
+
+	tests := []struct {
+		name  string
+		query models.BlogListQuery
+	}{
+		{name: "category ID", query: models.BlogListQuery{CategoryID: &zero}},
+		{name: "status", query: models.BlogListQuery{Status: "archived"}},
+		{name: "date", query: models.BlogListQuery{From: "01/10/2026"}},
+		{name: "reversed range", query: models.BlogListQuery{From: "2026-10-05", To: "2026-10-01"}},
+		{name: "sort", query: models.BlogListQuery{Sort: "author"}},
+		{name: "order", query: models.BlogListQuery{Order: "up"}},
+		{name: "limit", query: models.BlogListQuery{Limit: 101}},
+		{name: "negative offset", query: models.BlogListQuery{Offset: -1}},
+		{name: "cursor with offset", query: models.BlogListQuery{Cursor: first.NextCursor, Offset: 1}},
+		{name: "cursor for another order", query: models.BlogListQuery{Cursor: first.NextCursor, Order: "asc"}},
+		{name: "malformed cursor", query: models.BlogListQuery{Cursor: "not-a-cursor"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.GetBlogs(ctx, tt.query); !errors.Is(err, ErrInvalidBlogQuery) {
+				t.Fatalf("GetBlogs() error = %v, want ErrInvalidBlogQuery", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,518 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/ical"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrInvalidCredentials is returned when neither the account password nor an
+// app password matches.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrInvalidCalendarData is returned for PUT bodies that aren't a VTODO.
+var ErrInvalidCalendarData = errors.New("invalid calendar data")
+
+// ErrInvalidResourceName is returned for resource names a client may not create.
+var ErrInvalidResourceName = errors.New("invalid resource name")
+
+// ErrPreconditionFailed is returned when If-Match or If-None-Match doesn't hold.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// ErrInvalidSyncToken is returned for sync tokens this server didn't issue.
+var ErrInvalidSyncToken = errors.New("invalid sync token")
+
+const (
+	calDAVSyncTokenPrefix = "urn:todo-go-echo:sync:"
+	// Todos without a client-chosen name are published as todo-<id>.ics;
+	// clients can't create names of that form.
+	calDAVNamePrefix = "todo-"
+	calDAVNameSuffix = ".ics"
+	// credentialCacheTTL spares a bcrypt comparison on every request of a
+	// sync; each hit still checks the password wasn't changed or revoked.
+	credentialCacheTTL = 5 * time.Minute
+)
+
+// CalDAVObject is a todo rendered as an iCalendar resource.
+type CalDAVObject struct {
+	Name    string
+	ETag    string // quoted, ready for the ETag header
+	Data    []byte
+	VTodo   *ical.Todo
+	Deleted bool // only set in sync results
+}
+
+// CalDAVPrecondition carries the conditional request headers of a write.
+type CalDAVPrecondition struct {
+	IfMatch     string
+	IfNoneMatch string
+}
+
+// CalDAVService publishes each user's own todos as one calendar collection.
+// Shared todos stay out of it so a sync never has to follow role changes.
+type CalDAVService interface {
+	Authenticate(ctx context.Context, email, password string) (int, error)
+	SyncToken(ctx context.Context, userID int) (string, error)
+	List(ctx context.Context, userID int) ([]CalDAVObject, error)
+	Get(ctx context.Context, userID int, name string) (*CalDAVObject, error)
+	Put(ctx context.Context, userID int, name string, data []byte, cond CalDAVPrecondition) (*CalDAVObject, bool, error)
+	Delete(ctx context.Context, userID int, name string, cond CalDAVPrecondition) error
+	Changes(ctx context.Context, userID int, token string) ([]CalDAVObject, string, error)
+}
+
+type calDAVService struct {
+	repo         repository.TodoRepository
+	todos        TodoService
+	users        repository.UserRepository
+	appPasswords repository.AppPasswordRepository
+	now          func() time.Time
+
+	mu          sync.Mutex
+	credentials map[[sha256.Size]byte]cachedCredential
+}
+
+// cachedCredential remembers which stored hash a password matched, so a hit
+// only has to check that the hash is still current: a changed account
+// password or a revoked app password stops working at once.
+type cachedCredential struct {
+	userID        int
+	appPasswordID int // 0 for the account password
+	hash          string
+	expires       time.Time
+}
+
+// dummyPasswordHash is compared against for unknown emails so a failed sign-in
+// takes as long whether or not the account exists.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("no such user"), bcrypt.DefaultCost)
+	return hash
+})
+
+func NewCalDAVService(repo repository.TodoRepository, todos TodoService, users repository.UserRepository, appPasswords repository.AppPasswordRepository) CalDAVService {
+	return &calDAVService{
+		repo:         repo,
+		todos:        todos,
+		users:        users,
+		appPasswords: appPasswords,
+		now:          time.Now,
+		credentials:  make(map[[sha256.Size]byte]cachedCredential),
+	}
+}
+
+// Authenticate accepts the account password or any of the user's app passwords.
+func (s *calDAVService) Authenticate(ctx context.Context, email, password string) (int, error) {
+	key := sha256.Sum256([]byte(email + "\x00" + password))
+	s.mu.Lock()
+	cached, ok := s.credentials[key]
+	s.mu.Unlock()
+	if ok && s.now().Before(cached.expires) {
+		current, err := s.isCurrent(ctx, email, cached)
+		if err != nil {
+			return 0, err
+		}
+		if current {
+			return cached.userID, nil
+		}
+		s.mu.Lock()
+		delete(s.credentials, key)
+		s.mu.Unlock()
+	}
+
+	credential, err := s.checkCredentials(ctx, email, password)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, entry := range s.credentials {
+		if !s.now().Before(entry.expires) {
+			delete(s.credentials, k)
+		}
+	}
+	credential.expires = s.now().Add(credentialCacheTTL)
+	s.credentials[key] = credential
+	return credential.userID, nil
+}
+
+func (s *calDAVService) checkCredentials(ctx context.Context, email, password string) (cachedCredential, error) {
+	user, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return cachedCredential{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return cachedCredential{}, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil {
+		return cachedCredential{userID: user.ID, hash: user.Password}, nil
+	}
+
+	passwords, err := s.appPasswords.GetAll(ctx, user.ID)
+	if err != nil {
+		return cachedCredential{}, err
+	}
+	normalized := normalizeAppPassword(password)
+	for _, appPassword := range passwords {
+		if bcrypt.CompareHashAndPassword([]byte(appPassword.Password), []byte(normalized)) == nil {
+			if err := s.appPasswords.Touch(ctx, appPassword.ID, s.now()); err != nil {
+				return cachedCredential{}, err
+			}
+			return cachedCredential{userID: user.ID, appPasswordID: appPassword.ID, hash: appPassword.Password}, nil
+		}
+	}
+	return cachedCredential{}, ErrInvalidCredentials
+}
+
+// isCurrent reports whether the hash a cached credential matched is still
+// the user's account password or one of their app passwords.
+func (s *calDAVService) isCurrent(ctx context.Context, email string, cached cachedCredential) (bool, error) {
+	user, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if user.ID != cached.userID {
+		return false, nil
+	}
+	if cached.appPasswordID == 0 {
+		return user.Password == cached.hash, nil
+	}
+
+	passwords, err := s.appPasswords.GetAll(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, appPassword := range passwords {
+		if appPassword.ID == cached.appPasswordID && appPassword.Password == cached.hash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SyncToken identifies the collection's state: the newest history entry of
+// the user's todos, which every create, update and delete writes.
+func (s *calDAVService) SyncToken(ctx context.Context, userID int) (string, error) {
+	latest, err := s.repo.LatestEventID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return calDAVSyncTokenPrefix + strconv.Itoa(latest), nil
+}
+
+func (s *calDAVService) List(ctx context.Context, userID int) ([]CalDAVObject, error) {
+	todos, err := s.repo.ListOwned(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]CalDAVObject, 0, len(todos))
+	for i := range todos {
+		objects = append(objects, calDAVObject(&todos[i]))
+	}
+	return objects, nil
+}
+
+func (s *calDAVService) Get(ctx context.Context, userID int, name string) (*CalDAVObject, error) {
+	todo, err := s.find(ctx, s.repo, userID, name)
+	if err != nil {
+		return nil, err
+	}
+	object := calDAVObject(todo)
+	return &object, nil
+}
+
+// Put creates or replaces the todo stored under name. Creations and updates
+// go through the same code as the REST API, so history, recurrences and
+// roll-ups behave the same. CATEGORIES are not mapped back to tags.
+func (s *calDAVService) Put(ctx context.Context, userID int, name string, data []byte, cond CalDAVPrecondition) (*CalDAVObject, bool, error) {
+	vtodo, err := ical.Decode(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidCalendarData, err)
+	}
+
+	var todo *models.Todo
+	created := false
+	err = s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		existing, err := s.find(ctx, repo, userID, name)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err := checkPrecondition(existing, cond); err != nil {
+			return err
+		}
+
+		if existing != nil {
+			todo, err = s.todos.update(ctx, repo, userID, existing.ID, updateRequestFromVTODO(vtodo))
+			return err
+		}
+
+		if err := validateCalDAVName(name); err != nil {
+			return err
+		}
+		if todo, err = s.todos.prepare(ctx, repo, userID, createRequestFromVTODO(vtodo)); err != nil {
+			return err
+		}
+		todo.ICalUID = &vtodo.UID
+		todo.CalDAVName = &name
+		if err := s.todos.create(ctx, repo, todo); err != nil {
+			return err
+		}
+		created = true
+
+		if isCompletedVTODO(vtodo) {
+			completed := true
+			todo, err = s.todos.update(ctx, repo, userID, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	object := calDAVObject(todo)
+	return &object, created, nil
+}
+
+// Delete moves the todo to the trash; its subtasks are promoted, since a
+// client deleting one reminder doesn't know about the others.
+func (s *calDAVService) Delete(ctx context.Context, userID int, name string, cond CalDAVPrecondition) error {
+	return s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		todo, err := s.find(ctx, repo, userID, name)
+		if err != nil {
+			return err
+		}
+		if err := checkPrecondition(todo, cond); err != nil {
+			return err
+		}
+		return s.todos.delete(ctx, repo, userID, todo.ID, models.SubtaskDeletePromote)
+	})
+}
+
+// Changes returns what changed since token, with deleted todos marked, and
+// the token to use next time. An empty token returns every todo. Purged
+// todos take their history with them, so a client that last synced before a
+// purge keeps those todos until it resyncs from scratch.
+func (s *calDAVService) Changes(ctx context.Context, userID int, token string) ([]CalDAVObject, string, error) {
+	latest, err := s.repo.LatestEventID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	next := calDAVSyncTokenPrefix + strconv.Itoa(latest)
+
+	if token == "" {
+		objects, err := s.List(ctx, userID)
+		return objects, next, err
+	}
+
+	raw, ok := strings.CutPrefix(token, calDAVSyncTokenPrefix)
+	since, err := strconv.Atoi(raw)
+	if !ok || err != nil || since < 0 || since > latest {
+		return nil, "", ErrInvalidSyncToken
+	}
+
+	todos, err := s.repo.ChangedSince(ctx, userID, since)
+	if err != nil {
+		return nil, "", err
+	}
+	objects := make([]CalDAVObject, 0, len(todos))
+	for i := range todos {
+		if todos[i].DeletedAt.Valid {
+			objects = append(objects, CalDAVObject{Name: calDAVName(&todos[i]), Deleted: true})
+			continue
+		}
+		objects = append(objects, calDAVObject(&todos[i]))
+	}
+	return objects, next, nil
+}
+
+// find returns sql.ErrNoRows when the user has no todo under name.
+func (s *calDAVService) find(ctx context.Context, repo repository.TodoRepository, userID int, name string) (*models.Todo, error) {
+	id, _ := calDAVNameID(name)
+	todo, err := repo.FindCalDAV(ctx, userID, name, id)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, sql.ErrNoRows
+	}
+	return todo, nil
+}
+
+func checkPrecondition(existing *models.Todo, cond CalDAVPrecondition) error {
+	switch {
+	case cond.IfNoneMatch == "*" && existing != nil:
+		return fmt.Errorf("%w: resource exists", ErrPreconditionFailed)
+	case cond.IfMatch == "":
+		return nil
+	case existing == nil:
+		return fmt.Errorf("%w: resource does not exist", ErrPreconditionFailed)
+	case cond.IfMatch != "*" && cond.IfMatch != calDAVObject(existing).ETag:
+		return fmt.Errorf("%w: resource has changed", ErrPreconditionFailed)
+	}
+	return nil
+}
+
+func calDAVObject(todo *models.Todo) CalDAVObject {
+	vtodo := &ical.Todo{
+		UID:         calDAVUID(todo),
+		Summary:     todo.Title,
+		Description: todo.Description,
+		Status:      ical.StatusNeedsAction,
+		Priority:    icalPriority(todo.Priority),
+		Due:         todo.DueAt,
+		Created:     todo.CreatedAt,
+		Modified:    todo.UpdatedAt,
+		// DTSTAMP follows the todo rather than the clock so the ETag is stable.
+		Stamp: todo.UpdatedAt,
+	}
+	switch {
+	case todo.Completed:
+		vtodo.Status = ical.StatusCompleted
+		vtodo.Completed = &todo.UpdatedAt
+	case todo.Status == models.StatusInProgress:
+		vtodo.Status = ical.StatusInProcess
+	}
+	if todo.RecurrenceRule != nil {
+		vtodo.RRule = *todo.RecurrenceRule
+	}
+	for _, tag := range todo.Tags {
+		vtodo.Categories = append(vtodo.Categories, tag.Name)
+	}
+
+	data := ical.Encode(vtodo)
+	sum := sha256.Sum256(data)
+	return CalDAVObject{
+		Name:  calDAVName(todo),
+		ETag:  `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Data:  data,
+		VTodo: vtodo,
+	}
+}
+
+func createRequestFromVTODO(vtodo *ical.Todo) models.CreateTodoRequest {
+	req := models.CreateTodoRequest{
+		Title:          vtodo.Summary,
+		Description:    vtodo.Description,
+		Priority:       string(todoPriority(vtodo.Priority)),
+		RecurrenceRule: vtodo.RRule,
+	}
+	if vtodo.Due != nil {
+		due := vtodoDue(vtodo)
+		req.DueAt = &due
+	}
+	return req
+}
+
+// updateRequestFromVTODO replaces every mapped field, since a PUT carries
+// the whole resource.
+func updateRequestFromVTODO(vtodo *ical.Todo) models.UpdateTodoRequest {
+	due := ""
+	if vtodo.Due != nil {
+		due = vtodoDue(vtodo)
+	}
+	completed := isCompletedVTODO(vtodo)
+	priority := string(todoPriority(vtodo.Priority))
+	return models.UpdateTodoRequest{
+		Title:          &vtodo.Summary,
+		Description:    &vtodo.Description,
+		Completed:      &completed,
+		DueAt:          &due,
+		Priority:       &priority,
+		RecurrenceRule: &vtodo.RRule,
+	}
+}
+
+// vtodoDue formats DUE the way the REST API accepts due_at, so a date-only
+// DUE also means the end of that day.
+func vtodoDue(vtodo *ical.Todo) string {
+	if vtodo.DueIsDate {
+		return vtodo.Due.Format(models.DueDateLayout)
+	}
+	return vtodo.Due.Format(time.RFC3339)
+}
+
+func isCompletedVTODO(vtodo *ical.Todo) bool {
+	return vtodo.Status == ical.StatusCompleted || (vtodo.Status == "" && vtodo.Completed != nil)
+}
+
+// icalPriority maps onto the 1 (highest) to 9 (lowest) scale; none is 0, "undefined".
+func icalPriority(priority models.TodoPriority) int {
+	switch priority {
+	case models.PriorityUrgent:
+		return 1
+	case models.PriorityHigh:
+		return 3
+	case models.PriorityMedium:
+		return 5
+	case models.PriorityLow:
+		return 9
+	}
+	return 0
+}
+
+func todoPriority(priority int) models.TodoPriority {
+	switch {
+	case priority == 0:
+		return models.PriorityNone
+	case priority <= 2:
+		return models.PriorityUrgent
+	case priority <= 4:
+		return models.PriorityHigh
+	case priority == 5:
+		return models.PriorityMedium
+	}
+	return models.PriorityLow
+}
+
+func calDAVName(todo *models.Todo) string {
+	if todo.CalDAVName != nil {
+		return *todo.CalDAVName
+	}
+	return calDAVNamePrefix + strconv.Itoa(todo.ID) + calDAVNameSuffix
+}
+
+func calDAVUID(todo *models.Todo) string {
+	if todo.ICalUID != nil {
+		return *todo.ICalUID
+	}
+	return fmt.Sprintf("todo-%d@todo-go-echo", todo.ID)
+}
+
+// calDAVNameID parses the todo-<id>.ics names of todos created through the API.
+func calDAVNameID(name string) (int, bool) {
+	raw, ok := strings.CutPrefix(name, calDAVNamePrefix)
+	if !ok {
+		return 0, false
+	}
+	raw, ok = strings.CutSuffix(raw, calDAVNameSuffix)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(raw)
+	return id, err == nil
+}
+
+func validateCalDAVName(name string) error {
+	if _, reserved := calDAVNameID(name); reserved {
+		return fmt.Errorf("%w: %q has the form used for todos created elsewhere", ErrInvalidResourceName, name)
+	}
+	if !strings.HasSuffix(name, calDAVNameSuffix) || len(name) > 255 || strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("%w: %q", ErrInvalidResourceName, name)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/workflow"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type appPasswordRepoMock struct {
+	passwords []models.AppPassword
+	touched   int
+}
+
+func (m *appPasswordRepoMock) GetAll(ctx context.Context, userID int) ([]models.AppPassword, error) {
+	var result []models.AppPassword
+	for _, password := range m.passwords {
+		if password.UserID == userID {
+			result = append(result, password)
+		}
+	}
+	return result, nil
+}
+
+func (m *appPasswordRepoMock) Create(ctx context.Context, password *models.AppPassword) error {
+	password.ID = len(m.passwords) + 1
+	m.passwords = append(m.passwords, *password)
+	return nil
+}
+
+func (m *appPasswordRepoMock) Delete(ctx context.Context, userID, id int) error {
+	for i, password := range m.passwords {
+		if password.ID == id && password.UserID == userID {
+			m.passwords = append(m.passwords[:i], m.passwords[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (m *appPasswordRepoMock) Touch(ctx context.Context, id int, at time.Time) error {
+	m.touched = id
+	return nil
+}
+
+const vtodoFixture = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:phone-1\r\nSUMMARY:%s\r\nSTATUS:%s\r\nPRIORITY:1\r\nDUE;VALUE=DATE:20260301\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+func vtodo(summary, status string) []byte {
+	return []byte(strings.NewReplacer("%s", summary).Replace(strings.Replace(vtodoFixture, "STATUS:%s", "STATUS:"+status, 1)))
+}
+
+func TestCalDAVServicePutGetAndSync(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewCalDAVService(repo, NewTodoService(repo, workflow.Default(), config.TodoConfig{}), &userRepoMock{}, &appPasswordRepoMock{})
+
+	start, err := svc.SyncToken(ctx, 1)
+	if err != nil {
+		t.Fatalf("SyncToken() error = %v", err)
+	}
+
+	created, isNew, err := svc.Put(ctx, 1, "phone-1.ics", vtodo("Buy milk", "NEEDS-ACTION"), CalDAVPrecondition{IfNoneMatch: "*"})
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if !isNew || created.Name != "phone-1.ics" || created.VTodo.UID != "phone-1" {
+		t.Fatalf("Put() = %+v (new %v), want a new phone-1.ics", created, isNew)
+	}
+	todo := repo.todos[1]
+	if todo.Title != "Buy milk" || todo.Priority != models.PriorityUrgent || todo.DueAt == nil {
+		t.Fatalf("stored todo = %+v", todo)
+	}
+
+	got, err := svc.Get(ctx, 1, "phone-1.ics")
+	if err != nil || got.ETag != created.ETag {
+		t.Fatalf("Get() = %+v, %v; want ETag %s", got, err, created.ETag)
+	}
+	if _, err := svc.Get(ctx, 2, "phone-1.ics"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Get() by another user error = %v, want sql.ErrNoRows", err)
+	}
+
+	if _, _, err := svc.Put(ctx, 1, "phone-1.ics", vtodo("Buy oat milk", "COMPLETED"), CalDAVPrecondition{IfMatch: `"stale"`}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("Put() with a stale If-Match error = %v, want ErrPreconditionFailed", err)
+	}
+	updated, isNew, err := svc.Put(ctx, 1, "phone-1.ics", vtodo("Buy oat milk", "COMPLETED"), CalDAVPrecondition{IfMatch: created.ETag})
+	if err != nil || isNew {
+		t.Fatalf("Put() update = %v (new %v)", err, isNew)
+	}
+	if !repo.todos[1].Completed || repo.todos[1].Title != "Buy oat milk" || updated.ETag == created.ETag {
+		t.Fatalf("updated todo = %+v, ETag %s", repo.todos[1], updated.ETag)
+	}
+
+	// A todo created through the API shows up under its derived name.
+	if _, err := NewTodoService(repo, workflow.Default(), config.TodoConfig{}).Create(ctx, 1, models.CreateTodoRequest{Title: "From the web", Description: "Created by REST"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := svc.Get(ctx, 1, "todo-2.ics"); err != nil {
+		t.Fatalf("Get(todo-2.ics) error = %v", err)
+	}
+	if _, _, err := svc.Put(ctx, 1, "todo-99.ics", vtodo("Sneaky", "NEEDS-ACTION"), CalDAVPrecondition{}); !errors.Is(err, ErrInvalidResourceName) {
+		t.Fatalf("Put() to a reserved name error = %v, want ErrInvalidResourceName", err)
+	}
+
+	mid, _ := svc.SyncToken(ctx, 1)
+	if err := svc.Delete(ctx, 1, "phone-1.ics", CalDAVPrecondition{}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	changes, next, err := svc.Changes(ctx, 1, mid)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(changes) != 1 || !changes[0].Deleted || changes[0].Name != "phone-1.ics" {
+		t.Fatalf("Changes() since delete = %+v, want phone-1.ics deleted", changes)
+	}
+	if next == mid {
+		t.Fatal("Changes() did not advance the sync token")
+	}
+
+	all, _, err := svc.Changes(ctx, 1, start)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("Changes() from the start = %+v, %v; want both todos", all, err)
+	}
+	if _, _, err := svc.Changes(ctx, 1, "urn:todo-go-echo:sync:999"); !errors.Is(err, ErrInvalidSyncToken) {
+		t.Fatalf("Changes() with a future token error = %v, want ErrInvalidSyncToken", err)
+	}
+}
+
+func TestCalDAVServiceAuthenticate(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("account-secret"), bcrypt.MinCost)
+	users := &userRepoMock{users: map[string]*models.User{
+		"ada@example.com": {ID: 7, Email: "ada@example.com", Password: string(hash)},
+	}}
+	appPasswords := &appPasswordRepoMock{}
+	issued, err := NewAppPasswordService(appPasswords).Create(context.Background(), 7, models.CreateAppPasswordRequest{Name: "Phone"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewCalDAVService(repo, NewTodoService(repo, workflow.Default(), config.TodoConfig{}), users, appPasswords)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		wantErr  error
+	}{
+		{name: "account password", email: "ada@example.com", password: "account-secret"},
+		{name: "app password", email: "ada@example.com", password: issued.Password},
+		{name: "app password without dashes", email: "ada@example.com", password: strings.ToUpper(strings.ReplaceAll(issued.Password, "-", ""))},
+		{name: "wrong password", email: "ada@example.com", password: "nope", wantErr: ErrInvalidCredentials},
+		{name: "unknown user", email: "bob@example.com", password: "account-secret", wantErr: ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := svc.Authenticate(context.Background(), tt.email, tt.password)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || userID != 7 {
+				t.Fatalf("Authenticate() = %d, %v; want user 7", userID, err)
+			}
+		})
+	}
+	if appPasswords.touched != issued.ID {
+		t.Fatalf("app password %d was not marked as used", issued.ID)
+	}
+}
+
+func TestCalDAVServiceAuthenticateDropsRevokedCredentials(t *testing.T) {
+	ctx := context.Background()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("account-secret"), bcrypt.MinCost)
+	users := &userRepoMock{users: map[string]*models.User{
+		"ada@example.com": {ID: 7, Email: "ada@example.com", Password: string(hash)},
+	}}
+	appPasswords := &appPasswordRepoMock{}
+	appPasswordService := NewAppPasswordService(appPasswords)
+	issued, err := appPasswordService.Create(ctx, 7, models.CreateAppPasswordRequest{Name: "Phone"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewCalDAVService(repo, NewTodoService(repo, workflow.Default(), config.TodoConfig{}), users, appPasswords)
+
+	for _, password := range []string{issued.Password, "account-secret"} {
+		if _, err := svc.Authenticate(ctx, "ada@example.com", password); err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+	}
+
+	if err := appPasswordService.Delete(ctx, 7, issued.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, "ada@example.com", issued.Password); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Authenticate() with a revoked app password error = %v, want ErrInvalidCredentials", err)
+	}
+
+	changed, _ := bcrypt.GenerateFromPassword([]byte("new-secret"), bcrypt.MinCost)
+	users.users["ada@example.com"].Password = string(changed)
+	if _, err := svc.Authenticate(ctx, "ada@example.com", "account-secret"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Authenticate() with the old account password error = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := svc.Authenticate(ctx, "ada@example.com", "new-secret"); err != nil {
+		t.Fatalf("Authenticate() with the new account password error = %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// ErrForbidden is returned when the caller can see a resource but their role
+// doesn't allow the change, e.g. a list viewer editing a shared todo.
+var ErrForbidden = errors.New("insufficient permissions")
+
+// isUniqueViolation reports whether err is a Postgres unique violation (23505)
+// on one of the given constraint or index names.
+func isUniqueViolation(err error, constraints ...string) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code) == "23505" && slices.Contains(constraints, pqErr.Constraint)
+	}
+
+	var pgxErr *pgconn.PgError
+	if errors.As(err, &pgxErr) {
+		return pgxErr.Code == "23505" && slices.Contains(constraints, pgxErr.ConstraintName)
+	}
+
+	// Fallback for wrapped/driver-agnostic errors.
+	if !strings.Contains(err.Error(), "SQLSTATE 23505") {
+		return false
+	}
+	for _, constraint := range constraints {
+		if strings.Contains(err.Error(), constraint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+	"github.com/manish-npx/todo-go-echo/internal/storage"
+)
+
+const (
+	defaultTrashRetentionDays = 30
+	purgeBatchSize            = 500
+)
+
+// PurgeResult reports what one purge run removed.
+type PurgeResult struct {
+	Before time.Time `json:"before"`
+	Todos  int64     `json:"todos"`
+	Blogs  int64     `json:"blogs"`
+	// Attachments counts the files deleted from storage along with their todos.
+	Attachments int64 `json:"attachments"`
+}
+
+// PurgeService permanently removes trashed todos and blogs once they are
+// older than the configured retention.
+type PurgeService interface {
+	Purge(ctx context.Context) (*PurgeResult, error)
+}
+
+type purgeService struct {
+	todoRepo       repository.TodoRepository
+	blogRepo       repository.BlogRepository
+	attachmentRepo repository.TodoAttachmentRepository
+	store          storage.Storage
+	cfg            config.TrashConfig
+	now            func() time.Time
+}
+
+func NewPurgeService(todoRepo repository.TodoRepository, blogRepo repository.BlogRepository, attachmentRepo repository.TodoAttachmentRepository, store storage.Storage, cfg config.TrashConfig) PurgeService {
+	return &purgeService{todoRepo: todoRepo, blogRepo: blogRepo, attachmentRepo: attachmentRepo, store: store, cfg: cfg, now: time.Now}
+}
+
+// Purge works in batches so a large backlog never holds one long transaction.
+func (s *purgeService) Purge(ctx context.Context) (*PurgeResult, error) {
+	days := s.cfg.RetentionDays
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+	result := &PurgeResult{Before: s.now().AddDate(0, 0, -days)}
+
+	// Blobs go first: once their todos are purged nothing points at them.
+	for {
+		attachments, err := s.attachmentRepo.ListPurgeable(ctx, result.Before, purgeBatchSize)
+		if err != nil {
+			return result, err
+		}
+		for _, attachment := range attachments {
+			if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+				return result, err
+			}
+			if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+				return result, err
+			}
+			result.Attachments++
+		}
+		if len(attachments) < purgeBatchSize {
+			break
+		}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		n, err := s.todoRepo.PurgeDeleted(ctx, result.Before, purgeBatchSize)
+		if err != nil {
+			return result, err
+		}
+		result.Todos += n
+		if n < purgeBatchSize {
+			break
+		}
+	}
+
+	for {
+		n, err := s.blogRepo.PurgeDeleted(ctx, result.Before, purgeBatchSize)
+		if err != nil {
+			return result, err
+		}
+		result.Blogs += n
+		if n < purgeBatchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+var ErrTagAlreadyExists = errors.New("tag already exists")
+
+type TagService interface {
+	GetAll(ctx context.Context, userID int) ([]models.Tag, error)
+	GetByID(ctx context.Context, userID, id int) (*models.Tag, error)
+	Create(ctx context.Context, userID int, req models.CreateTagRequest) (*models.Tag, error)
+	Update(ctx context.Context, userID, id int, req models.UpdateTagRequest) (*models.Tag, error)
+	Delete(ctx context.Context, userID, id int) error
+}
+
+type tagService struct {
+	repo repository.TagRepository
+}
+
+func NewTagService(repo repository.TagRepository) TagService {
+	return &tagService{repo: repo}
+}
+
+func (s *tagService) GetAll(ctx context.Context, userID int) ([]models.Tag, error) {
+	return s.repo.GetAll(ctx, userID)
+}
+
+func (s *tagService) GetByID(ctx context.Context, userID, id int) (*models.Tag, error) {
+	return s.repo.GetByID(ctx, userID, id)
+}
+
+// Create and Update store the name as given; the handler has already trimmed
+// and validated it.
+func (s *tagService) Create(ctx context.Context, userID int, req models.CreateTagRequest) (*models.Tag, error) {
+	tag := &models.Tag{
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+	}
+	if err := s.repo.Create(ctx, tag); err != nil {
+		if isDuplicateTagError(err) {
+			return nil, ErrTagAlreadyExists
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) Update(ctx context.Context, userID, id int, req models.UpdateTagRequest) (*models.Tag, error) {
+	tag, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	if req.Name != nil {
+		tag.Name = *req.Name
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+
+	if err := s.repo.Update(ctx, tag); err != nil {
+		if isDuplicateTagError(err) {
+			return nil, ErrTagAlreadyExists
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) Delete(ctx context.Context, userID, id int) error {
+	return s.repo.Delete(ctx, userID, id)
+}
+
+func isDuplicateTagError(err error) bool {
+	return isUniqueViolation(err, "uni_tags_user_name")
+}
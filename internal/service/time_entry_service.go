@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// ErrTimerRunning is returned when starting a timer while another one runs.
+var ErrTimerRunning = errors.New("a timer is already running")
+
+// ErrNoRunningTimer is returned when stopping a timer on a todo that has none
+// of the caller's running.
+var ErrNoRunningTimer = errors.New("no timer running on this todo")
+
+// ErrInvalidTimeEntry is returned for manual entries that end before they
+// start or lie in the future, and for bad report ranges.
+var ErrInvalidTimeEntry = errors.New("invalid time entry")
+
+// MaxTimeReportDays bounds the report range.
+const MaxTimeReportDays = 366
+
+const defaultTimeReportDays = 30
+
+// TimeEntryService tracks time on todos. Everyone who can see a todo can
+// track their own time on it and read everyone's entries; only the author
+// deletes an entry. A user has at most one running timer.
+type TimeEntryService interface {
+	List(ctx context.Context, userID, todoID int) ([]models.TimeEntry, error)
+	Start(ctx context.Context, userID, todoID int, req models.StartTimerRequest) (*models.TimeEntry, error)
+	Stop(ctx context.Context, userID, todoID int) (*models.TimeEntry, error)
+	// Running returns the caller's running timer, or nil.
+	Running(ctx context.Context, userID int) (*models.TimeEntry, error)
+	Create(ctx context.Context, userID, todoID int, req models.CreateTimeEntryRequest) (*models.TimeEntry, error)
+	Delete(ctx context.Context, userID, todoID, id int) error
+	// Report sums the caller's own time over a range of days, by default
+	// the last 30 ending today in the caller's timezone.
+	Report(ctx context.Context, userID int, query models.TimeReportQuery) (*models.TimeReport, error)
+}
+
+type timeEntryService struct {
+	repo  repository.TimeEntryRepository
+	todos repository.TodoRepository
+	now   func() time.Time
+}
+
+func NewTimeEntryService(repo repository.TimeEntryRepository, todos repository.TodoRepository) TimeEntryService {
+	return &timeEntryService{repo: repo, todos: todos, now: time.Now}
+}
+
+func (s *timeEntryService) List(ctx context.Context, userID, todoID int) ([]models.TimeEntry, error) {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.List(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		s.setDuration(&entries[i])
+	}
+	return entries, nil
+}
+
+// Start checks for a running timer first to say which todo it is on; the
+// unique index settles concurrent starts.
+func (s *timeEntryService) Start(ctx context.Context, userID, todoID int, req models.StartTimerRequest) (*models.TimeEntry, error) {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	running, err := s.repo.Running(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return nil, fmt.Errorf("%w on todo %d", ErrTimerRunning, running.TodoID)
+	}
+
+	entry := &models.TimeEntry{
+		UserID:    userID,
+		TodoID:    todoID,
+		StartedAt: s.now(),
+		Note:      strings.TrimSpace(req.Note),
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		if isUniqueViolation(err, "uni_time_entries_running") {
+			return nil, ErrTimerRunning
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Stop only needs the caller's own running entry, not access to the todo:
+// a timer must stay stoppable after its todo is trashed or unshared.
+func (s *timeEntryService) Stop(ctx context.Context, userID, todoID int) (*models.TimeEntry, error) {
+	entry, err := s.repo.Running(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || entry.TodoID != todoID {
+		return nil, ErrNoRunningTimer
+	}
+
+	// Clocks of app instances may disagree a little.
+	endedAt := s.now()
+	if endedAt.Before(entry.StartedAt) {
+		endedAt = entry.StartedAt
+	}
+	entry.EndedAt = &endedAt
+	if err := s.repo.Stop(ctx, entry); err != nil {
+		// Stopped by a concurrent request.
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoRunningTimer
+		}
+		return nil, err
+	}
+	s.setDuration(entry)
+	return entry, nil
+}
+
+func (s *timeEntryService) Running(ctx context.Context, userID int) (*models.TimeEntry, error) {
+	entry, err := s.repo.Running(ctx, userID)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	s.setDuration(entry)
+	return entry, nil
+}
+
+func (s *timeEntryService) Create(ctx context.Context, userID, todoID int, req models.CreateTimeEntryRequest) (*models.TimeEntry, error) {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	if !req.EndedAt.After(req.StartedAt) {
+		return nil, fmt.Errorf("%w: ended_at must be after started_at", ErrInvalidTimeEntry)
+	}
+	if req.EndedAt.After(s.now()) {
+		return nil, fmt.Errorf("%w: ended_at is in the future", ErrInvalidTimeEntry)
+	}
+
+	endedAt := req.EndedAt
+	entry := &models.TimeEntry{
+		UserID:    userID,
+		TodoID:    todoID,
+		StartedAt: req.StartedAt,
+		EndedAt:   &endedAt,
+		Note:      strings.TrimSpace(req.Note),
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	s.setDuration(entry)
+	return entry, nil
+}
+
+func (s *timeEntryService) Delete(ctx context.Context, userID, todoID, id int) error {
+	if err := s.checkTodo(ctx, userID, todoID); err != nil {
+		return err
+	}
+	entry, err := s.repo.GetByID(ctx, todoID, id)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return sql.ErrNoRows
+	}
+	if entry.UserID != userID {
+		return ErrForbidden
+	}
+	return s.repo.Delete(ctx, todoID, id)
+}
+
+func (s *timeEntryService) Report(ctx context.Context, userID int, query models.TimeReportQuery) (*models.TimeReport, error) {
+	filter, err := s.buildReportFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.repo.Report(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	report.From = filter.From
+	report.To = filter.To
+	report.Timezone = filter.Timezone
+	return report, nil
+}
+
+func (s *timeEntryService) buildReportFilter(query models.TimeReportQuery) (models.TimeReportFilter, error) {
+	filter := models.TimeReportFilter{Timezone: query.Timezone, Now: s.now()}
+	if filter.Timezone == "" {
+		filter.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(filter.Timezone)
+	if err != nil {
+		return filter, fmt.Errorf("%w: unknown timezone %q", ErrInvalidTimeEntry, filter.Timezone)
+	}
+
+	now := filter.Now.In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if query.To != "" {
+		if to, err = time.ParseInLocation(models.DueDateLayout, query.To, loc); err != nil {
+			return filter, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidTimeEntry)
+		}
+	}
+	from := to.AddDate(0, 0, 1-defaultTimeReportDays)
+	if query.From != "" {
+		if from, err = time.ParseInLocation(models.DueDateLayout, query.From, loc); err != nil {
+			return filter, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidTimeEntry)
+		}
+	}
+	if from.After(to) {
+		return filter, fmt.Errorf("%w: from must not be after to", ErrInvalidTimeEntry)
+	}
+	if from.AddDate(0, 0, MaxTimeReportDays).Before(to.AddDate(0, 0, 1)) {
+		return filter, fmt.Errorf("%w: the range can span at most %d days", ErrInvalidTimeEntry, MaxTimeReportDays)
+	}
+
+	filter.From = from.Format(models.DueDateLayout)
+	filter.To = to.Format(models.DueDateLayout)
+	filter.Start = from
+	filter.End = to.AddDate(0, 0, 1)
+	return filter, nil
+}
+
+// checkTodo reports sql.ErrNoRows for todos userID can't see.
+func (s *timeEntryService) checkTodo(ctx context.Context, userID, todoID int) error {
+	todo, err := s.todos.GetByID(ctx, userID, todoID)
+	if err != nil {
+		return err
+	}
+	if todo == nil {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// setDuration fills DurationSeconds, counting a running timer up to now.
+func (s *timeEntryService) setDuration(entry *models.TimeEntry) {
+	end := s.now()
+	if entry.EndedAt != nil {
+		end = *entry.EndedAt
+	}
+	entry.DurationSeconds = max(int64(end.Sub(entry.StartedAt)/time.Second), 0)
+}
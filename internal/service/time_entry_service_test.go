@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+type timeEntryRepoMock struct {
+	entries   map[int]*models.TimeEntry
+	nextID    int
+	createErr error
+	filter    models.TimeReportFilter
+}
+
+func (m *timeEntryRepoMock) List(ctx context.Context, todoID int) ([]models.TimeEntry, error) {
+	var result []models.TimeEntry
+	for id := m.nextID; id >= 1; id-- {
+		if entry, ok := m.entries[id]; ok && entry.TodoID == todoID {
+			result = append(result, *entry)
+		}
+	}
+	return result, nil
+}
+
+func (m *timeEntryRepoMock) GetByID(ctx context.Context, todoID, id int) (*models.TimeEntry, error) {
+	entry, ok := m.entries[id]
+	if !ok || entry.TodoID != todoID {
+		return nil, nil
+	}
+	copied := *entry
+	return &copied, nil
+}
+
+func (m *timeEntryRepoMock) Running(ctx context.Context, userID int) (*models.TimeEntry, error) {
+	for _, entry := range m.entries {
+		if entry.UserID == userID && entry.EndedAt == nil {
+			copied := *entry
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *timeEntryRepoMock) Create(ctx context.Context, entry *models.TimeEntry) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.nextID++
+	entry.ID = m.nextID
+	copied := *entry
+	m.entries[entry.ID] = &copied
+	return nil
+}
+
+func (m *timeEntryRepoMock) Stop(ctx context.Context, entry *models.TimeEntry) error {
+	stored, ok := m.entries[entry.ID]
+	if !ok || stored.EndedAt != nil {
+		return sql.ErrNoRows
+	}
+	stored.EndedAt = entry.EndedAt
+	return nil
+}
+
+func (m *timeEntryRepoMock) Delete(ctx context.Context, todoID, id int) error {
+	if _, ok := m.entries[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.entries, id)
+	return nil
+}
+
+func (m *timeEntryRepoMock) Report(ctx context.Context, userID int, filter models.TimeReportFilter) (*models.TimeReport, error) {
+	m.filter = filter
+	return &models.TimeReport{}, nil
+}
+
+func newTimeEntryTest(now time.Time) (*timeEntryService, *timeEntryRepoMock) {
+	listID := 1
+	todos := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Design", ListID: &listID},
+			2: {ID: 2, UserID: 1, Title: "Build", ListID: &listID},
+			3: {ID: 3, UserID: 1, Title: "Private"},
+		},
+		lists:   map[int]models.TodoList{1: {ID: 1, UserID: 1, Name: "Client work"}},
+		members: map[int]map[int]models.ListRole{1: {2: models.ListRoleViewer}},
+	}
+	entries := &timeEntryRepoMock{entries: map[int]*models.TimeEntry{}}
+	svc := NewTimeEntryService(entries, todos).(*timeEntryService)
+	svc.now = func() time.Time { return now }
+	return svc, entries
+}
+
+func TestTimeEntryServiceTimer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	svc, _ := newTimeEntryTest(now)
+
+	// A viewer can track their own time on a shared todo.
+	entry, err := svc.Start(ctx, 2, 1, models.StartTimerRequest{Note: " sketches "})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if entry.UserID != 2 || entry.EndedAt != nil || entry.Note != "sketches" {
+		t.Fatalf("Start() = %+v", entry)
+	}
+	if _, err := svc.Start(ctx, 2, 2, models.StartTimerRequest{}); !errors.Is(err, ErrTimerRunning) || !strings.Contains(err.Error(), "todo 1") {
+		t.Fatalf("Start() second timer error = %v, want ErrTimerRunning on todo 1", err)
+	}
+	if _, err := svc.Start(ctx, 1, 2, models.StartTimerRequest{}); err != nil {
+		t.Fatalf("Start() by another user error = %v", err)
+	}
+	if _, err := svc.Start(ctx, 2, 3, models.StartTimerRequest{}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Start() on an unshared todo error = %v, want sql.ErrNoRows", err)
+	}
+
+	svc.now = func() time.Time { return now.Add(90 * time.Minute) }
+	running, err := svc.Running(ctx, 2)
+	if err != nil || running == nil || running.DurationSeconds != 5400 {
+		t.Fatalf("Running() = %+v, %v, want 5400 seconds so far", running, err)
+	}
+	if _, err := svc.Stop(ctx, 2, 2); !errors.Is(err, ErrNoRunningTimer) {
+		t.Fatalf("Stop() on another todo error = %v, want ErrNoRunningTimer", err)
+	}
+	stopped, err := svc.Stop(ctx, 2, 1)
+	if err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if stopped.EndedAt == nil || stopped.DurationSeconds != 5400 {
+		t.Fatalf("Stop() = %+v, want 5400 seconds", stopped)
+	}
+	if running, err := svc.Running(ctx, 2); err != nil || running != nil {
+		t.Fatalf("Running() after stop = %+v, %v, want none", running, err)
+	}
+	if _, err := svc.Start(ctx, 2, 2, models.StartTimerRequest{}); err != nil {
+		t.Fatalf("Start() after stop error = %v", err)
+	}
+}
+
+func TestTimeEntryServiceStopsTimerAfterLosingAccess(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	svc, _ := newTimeEntryTest(now)
+
+	if _, err := svc.Start(ctx, 2, 1, models.StartTimerRequest{}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	delete(svc.todos.(*todoRepoMock).members[1], 2)
+
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	stopped, err := svc.Stop(ctx, 2, 1)
+	if err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if stopped.DurationSeconds != 3600 {
+		t.Fatalf("Stop() = %+v, want 3600 seconds", stopped)
+	}
+}
+
+func TestTimeEntryServiceConcurrentStart(t *testing.T) {
+	svc, entries := newTimeEntryTest(time.Now())
+	entries.createErr = &pq.Error{Code: "23505", Constraint: "uni_time_entries_running"}
+
+	if _, err := svc.Start(context.Background(), 1, 1, models.StartTimerRequest{}); !errors.Is(err, ErrTimerRunning) {
+		t.Fatalf("Start() error = %v, want ErrTimerRunning", err)
+	}
+}
+
+func TestTimeEntryServiceManualEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	svc, _ := newTimeEntryTest(now)
+
+	tests := []struct {
+		name    string
+		start   time.Time
+		end     time.Time
+		wantErr error
+	}{
+		{name: "two hours", start: now.Add(-3 * time.Hour), end: now.Add(-time.Hour)},
+		{name: "ends before it starts", start: now.Add(-time.Hour), end: now.Add(-2 * time.Hour), wantErr: ErrInvalidTimeEntry},
+		{name: "empty", start: now.Add(-time.Hour), end: now.Add(-time.Hour), wantErr: ErrInvalidTimeEntry},
+		{name: "in the future", start: now, end: now.Add(time.Hour), wantErr: ErrInvalidTimeEntry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := svc.Create(ctx, 1, 1, models.CreateTimeEntryRequest{StartedAt: tt.start, EndedAt: tt.end})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if entry.DurationSeconds != int64(tt.end.Sub(tt.start).Seconds()) {
+				t.Fatalf("Create() duration = %d", entry.DurationSeconds)
+			}
+		})
+	}
+
+	// Manual entries don't count as a running timer.
+	if _, err := svc.Start(ctx, 1, 1, models.StartTimerRequest{}); err != nil {
+		t.Fatalf("Start() after a manual entry error = %v", err)
+	}
+}
+
+func TestTimeEntryServiceDelete(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	svc, _ := newTimeEntryTest(now)
+
+	entry, err := svc.Create(ctx, 2, 1, models.CreateTimeEntryRequest{StartedAt: now.Add(-time.Hour), EndedAt: now})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := svc.Delete(ctx, 1, 1, entry.ID); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Delete() by the todo owner error = %v, want ErrForbidden", err)
+	}
+	if err := svc.Delete(ctx, 2, 2, entry.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Delete() through another todo error = %v, want sql.ErrNoRows", err)
+	}
+	if err := svc.Delete(ctx, 2, 1, entry.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if listed, err := svc.List(ctx, 1, 1); err != nil || len(listed) != 0 {
+		t.Fatalf("List() after delete = %+v, %v, want none", listed, err)
+	}
+}
+
+func TestTimeEntryServiceReportFilter(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	// 23:30 UTC is already the next day in Berlin.
+	now := time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		query     models.TimeReportQuery
+		wantFrom  string
+		wantTo    string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "last 30 days in UTC",
+			wantFrom:  "2026-09-17",
+			wantTo:    "2026-10-16",
+			wantStart: time.Date(2026, 9, 17, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "days in the caller's timezone across a DST change",
+			query:     models.TimeReportQuery{Timezone: "Europe/Berlin", From: "2026-10-24", To: "2026-10-25"},
+			wantFrom:  "2026-10-24",
+			wantTo:    "2026-10-25",
+			wantStart: time.Date(2026, 10, 24, 0, 0, 0, 0, berlin),
+			wantEnd:   time.Date(2026, 10, 26, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:      "today follows the timezone",
+			query:     models.TimeReportQuery{Timezone: "Europe/Berlin", From: "2026-10-17"},
+			wantFrom:  "2026-10-17",
+			wantTo:    "2026-10-17",
+			wantStart: time.Date(2026, 10, 17, 0, 0, 0, 0, berlin),
+			wantEnd:   time.Date(2026, 10, 18, 0, 0, 0, 0, berlin),
+		},
+		{name: "unknown timezone", query: models.TimeReportQuery{Timezone: "Mars/Olympus"}, wantErr: true},
+		{name: "bad date", query: models.TimeReportQuery{From: "16.10.2026"}, wantErr: true},
+		{name: "from after to", query: models.TimeReportQuery{From: "2026-10-10", To: "2026-10-01"}, wantErr: true},
+		{name: "longer than the limit", query: models.TimeReportQuery{From: "2025-01-01", To: "2026-01-02"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, entries := newTimeEntryTest(now)
+			report, err := svc.Report(context.Background(), 1, tt.query)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTimeEntry) {
+					t.Fatalf("Report() error = %v, want ErrInvalidTimeEntry", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+			filter := entries.filter
+			if report.From != tt.wantFrom || report.To != tt.wantTo || filter.From != tt.wantFrom || filter.To != tt.wantTo {
+				t.Fatalf("Report() range = %s..%s, filter %s..%s, want %s..%s", report.From, report.To, filter.From, filter.To, tt.wantFrom, tt.wantTo)
+			}
+			if !filter.Start.Equal(tt.wantStart) || !filter.End.Equal(tt.wantEnd) {
+				t.Fatalf("Report() bounds = %v..%v, want %v..%v", filter.Start, filter.End, tt.wantStart, tt.wantEnd)
+			}
+			if !filter.Now.Equal(now) {
+				t.Fatalf("Report() now = %v, want %v", filter.Now, now)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// ErrInvalidAssignee is returned when assigning a todo to someone outside its
+// scope: the owner of a todo without a list, or the owner and members of its list.
+var ErrInvalidAssignee = errors.New("invalid assignee")
+
+// Assign hands a todo to another user. Like any edit it needs the owner or
+// an editor of the todo's list; the assignee may be a viewer.
+func (s *todoService) Assign(ctx context.Context, userID, id int, req models.AssignTodoRequest) (*models.Todo, error) {
+	return s.setAssignee(ctx, userID, id, &req.UserID)
+}
+
+// Unassign clears the assignee; assigning to nobody twice is not an error.
+func (s *todoService) Unassign(ctx context.Context, userID, id int) (*models.Todo, error) {
+	return s.setAssignee(ctx, userID, id, nil)
+}
+
+func (s *todoService) setAssignee(ctx context.Context, userID, id int, assigneeID *int) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		var err error
+		if todo, err = repo.GetByID(ctx, userID, id); err != nil {
+			return err
+		}
+		if todo == nil {
+			return sql.ErrNoRows
+		}
+		if err := s.authorize(ctx, repo, userID, todo); err != nil {
+			return err
+		}
+		if assigneeID != nil {
+			if err := s.checkAssignee(ctx, repo, todo, *assigneeID); err != nil {
+				return err
+			}
+		}
+
+		before := *todo
+		todo.AssigneeID = assigneeID
+		if err := repo.Update(ctx, todo); err != nil {
+			return err
+		}
+		return s.recordUpdate(ctx, repo, userID, &before, todo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// checkAssignee allows the todo's owner and, for a todo in a list, anyone
+// with a role on that list.
+func (s *todoService) checkAssignee(ctx context.Context, repo repository.TodoRepository, todo *models.Todo, assigneeID int) error {
+	if assigneeID == todo.UserID {
+		return nil
+	}
+	if todo.ListID != nil {
+		list, err := repo.FindList(ctx, assigneeID, *todo.ListID)
+		if err != nil {
+			return err
+		}
+		if list != nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: user %d can't see this todo", ErrInvalidAssignee, assigneeID)
+}
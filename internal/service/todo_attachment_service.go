@@ -0,0 +1,223 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+	"github.com/manish-npx/todo-go-echo/internal/storage"
+)
+
+// ErrAttachmentTooLarge is returned for uploads over the configured size limit.
+var ErrAttachmentTooLarge = errors.New("attachment is too large")
+
+// ErrUnsupportedFileType is returned when the sniffed content isn't an allowed type.
+var ErrUnsupportedFileType = errors.New("unsupported file type")
+
+const defaultAttachmentMaxSizeMB = 10
+
+// sniffLen is how much of an upload http.DetectContentType looks at.
+const sniffLen = 512
+
+// allowedAttachmentTypes are the sniffed media types accepted for receipts
+// and screenshots. What the client claims is ignored.
+var allowedAttachmentTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp",
+	"application/pdf", "text/plain",
+}
+
+// TodoAttachmentService stores files on todos. Everyone who can see a todo
+// can list and download its attachments; uploading and deleting need the
+// same rights as editing the todo.
+type TodoAttachmentService interface {
+	List(ctx context.Context, userID, todoID int) ([]models.TodoAttachment, error)
+	Upload(ctx context.Context, userID, todoID int, fileName string, size int64, r io.Reader) (*models.TodoAttachment, error)
+	// Open returns the attachment with its content; the caller closes it.
+	Open(ctx context.Context, userID, todoID, id int) (*models.TodoAttachment, io.ReadCloser, error)
+	Delete(ctx context.Context, userID, todoID, id int) error
+	// MaxSize is the upload limit in bytes.
+	MaxSize() int64
+}
+
+type todoAttachmentService struct {
+	repo     repository.TodoAttachmentRepository
+	todoRepo repository.TodoRepository
+	todos    TodoService
+	store    storage.Storage
+	maxSize  int64
+}
+
+func NewTodoAttachmentService(repo repository.TodoAttachmentRepository, todoRepo repository.TodoRepository, todos TodoService, store storage.Storage, cfg config.AttachmentConfig) TodoAttachmentService {
+	maxSizeMB := cfg.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultAttachmentMaxSizeMB
+	}
+	return &todoAttachmentService{
+		repo:     repo,
+		todoRepo: todoRepo,
+		todos:    todos,
+		store:    store,
+		maxSize:  int64(maxSizeMB) << 20,
+	}
+}
+
+func (s *todoAttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *todoAttachmentService) List(ctx context.Context, userID, todoID int) ([]models.TodoAttachment, error) {
+	if _, err := s.todo(ctx, userID, todoID, false); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, todoID)
+}
+
+// Upload sniffs the first bytes to decide the content type, then streams
+// the file to storage.
+func (s *todoAttachmentService) Upload(ctx context.Context, userID, todoID int, fileName string, size int64, r io.Reader) (*models.TodoAttachment, error) {
+	if _, err := s.todo(ctx, userID, todoID, true); err != nil {
+		return nil, err
+	}
+	if size > s.maxSize {
+		return nil, fmt.Errorf("%w: at most %d bytes", ErrAttachmentTooLarge, s.maxSize)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrUnsupportedFileType)
+	}
+
+	head := make([]byte, min(size, sniffLen))
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !slices.Contains(allowedAttachmentTypes, mediaType) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFileType, mediaType)
+	}
+
+	key, err := attachmentKey(todoID)
+	if err != nil {
+		return nil, err
+	}
+	body := io.MultiReader(bytes.NewReader(head), r)
+	if err := s.store.Put(ctx, key, body, size, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.TodoAttachment{
+		TodoID:      todoID,
+		UserID:      &userID,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		// Without a row nothing refers to the blob any more.
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (s *todoAttachmentService) Open(ctx context.Context, userID, todoID, id int) (*models.TodoAttachment, io.ReadCloser, error) {
+	attachment, err := s.attachment(ctx, userID, todoID, id, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// Delete removes the blob before the row, so a failure never leaves a blob
+// nothing points to; deleting again finishes the job.
+func (s *todoAttachmentService) Delete(ctx context.Context, userID, todoID, id int) error {
+	attachment, err := s.attachment(ctx, userID, todoID, id, true)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, attachment.ID)
+}
+
+// todo returns the todo if userID can see it and, with write, edit it.
+func (s *todoAttachmentService) todo(ctx context.Context, userID, todoID int, write bool) (*models.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, sql.ErrNoRows
+	}
+	if write {
+		if err := s.todos.authorize(ctx, s.todoRepo, userID, todo); err != nil {
+			return nil, err
+		}
+	}
+	return todo, nil
+}
+
+func (s *todoAttachmentService) attachment(ctx context.Context, userID, todoID, id int, write bool) (*models.TodoAttachment, error) {
+	if _, err := s.todo(ctx, userID, todoID, write); err != nil {
+		return nil, err
+	}
+	attachment, err := s.repo.GetByID(ctx, todoID, id)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, sql.ErrNoRows
+	}
+	return attachment, nil
+}
+
+// attachmentKey is random so keys can't be guessed or collide.
+func attachmentKey(todoID int) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("todos/%d/%s", todoID, hex.EncodeToString(random)), nil
+}
+
+// cleanFileName keeps the base name the client sent, for display and the
+// download's Content-Disposition.
+func cleanFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	for len(name) > 255 {
+		_, width := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-width]
+	}
+	return name
+}
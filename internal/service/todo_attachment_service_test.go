@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/storage"
+	"github.com/manish-npx/todo-go-echo/internal/workflow"
+)
+
+type todoAttachmentRepoMock struct {
+	attachments map[int]*models.TodoAttachment
+	nextID      int
+}
+
+func (m *todoAttachmentRepoMock) List(ctx context.Context, todoID int) ([]models.TodoAttachment, error) {
+	var result []models.TodoAttachment
+	for id := 1; id <= m.nextID; id++ {
+		if attachment, ok := m.attachments[id]; ok && attachment.TodoID == todoID {
+			result = append(result, *attachment)
+		}
+	}
+	return result, nil
+}
+
+func (m *todoAttachmentRepoMock) GetByID(ctx context.Context, todoID, id int) (*models.TodoAttachment, error) {
+	attachment, ok := m.attachments[id]
+	if !ok || attachment.TodoID != todoID {
+		return nil, nil
+	}
+	copied := *attachment
+	return &copied, nil
+}
+
+func (m *todoAttachmentRepoMock) Create(ctx context.Context, attachment *models.TodoAttachment) error {
+	m.nextID++
+	attachment.ID = m.nextID
+	attachment.CreatedAt = time.Now()
+	copied := *attachment
+	m.attachments[attachment.ID] = &copied
+	return nil
+}
+
+func (m *todoAttachmentRepoMock) Delete(ctx context.Context, id int) error {
+	if _, ok := m.attachments[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.attachments, id)
+	return nil
+}
+
+func (m *todoAttachmentRepoMock) ListPurgeable(ctx context.Context, before time.Time, limit int) ([]models.TodoAttachment, error) {
+	return nil, nil
+}
+
+func TestTodoAttachmentServiceUploadOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	listID := 1
+	todos := &todoRepoMock{
+		todos:   map[int]*models.Todo{1: {ID: 1, UserID: 1, Title: "Expenses", ListID: &listID}},
+		lists:   map[int]models.TodoList{1: {ID: 1, UserID: 1, Name: "Work"}},
+		members: map[int]map[int]models.ListRole{1: {2: models.ListRoleViewer}},
+	}
+	attachments := &todoAttachmentRepoMock{attachments: map[int]*models.TodoAttachment{}}
+	store := storage.NewLocal(t.TempDir())
+	svc := NewTodoAttachmentService(attachments, todos, NewTodoService(todos, workflow.Default(), config.TodoConfig{}), store, config.AttachmentConfig{MaxSizeMB: 1})
+
+	receipt := []byte("Taxi 12.50 EUR\n")
+	attachment, err := svc.Upload(ctx, 1, 1, `C:\scans\"receipt".txt`, int64(len(receipt)), bytes.NewReader(receipt))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if attachment.FileName != "receipt.txt" || attachment.ContentType != "text/plain; charset=utf-8" || attachment.Size != int64(len(receipt)) {
+		t.Fatalf("Upload() = %+v", attachment)
+	}
+
+	// Viewers can download but not upload or delete.
+	_, content, err := svc.Open(ctx, 2, 1, attachment.ID)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	got, _ := io.ReadAll(content)
+	content.Close()
+	if !bytes.Equal(got, receipt) {
+		t.Fatalf("Open() content = %q, want %q", got, receipt)
+	}
+	if _, err := svc.Upload(ctx, 2, 1, "x.txt", 1, strings.NewReader("x")); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Upload() by viewer error = %v, want ErrForbidden", err)
+	}
+	if err := svc.Delete(ctx, 2, 1, attachment.ID); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Delete() by viewer error = %v, want ErrForbidden", err)
+	}
+	if _, err := svc.List(ctx, 3, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("List() by stranger error = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := svc.Delete(ctx, 1, 1, attachment.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(ctx, attachment.StorageKey); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("blob after Delete() error = %v, want storage.ErrNotFound", err)
+	}
+	if _, _, err := svc.Open(ctx, 1, 1, attachment.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Open() after Delete() error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestTodoAttachmentServiceRejectsUploads(t *testing.T) {
+	ctx := context.Background()
+	todos := &todoRepoMock{todos: map[int]*models.Todo{1: {ID: 1, UserID: 1, Title: "Expenses"}}}
+	attachments := &todoAttachmentRepoMock{attachments: map[int]*models.TodoAttachment{}}
+	svc := NewTodoAttachmentService(attachments, todos, NewTodoService(todos, workflow.Default(), config.TodoConfig{}), storage.NewLocal(t.TempDir()), config.AttachmentConfig{MaxSizeMB: 1})
+
+	tests := []struct {
+		name    string
+		content []byte
+		size    int64
+		wantErr error
+	}{
+		{name: "html", content: []byte("<html><script>alert(1)</script></html>"), wantErr: ErrUnsupportedFileType},
+		{name: "binary", content: []byte{0x7f, 'E', 'L', 'F', 2, 1, 1, 0}, wantErr: ErrUnsupportedFileType},
+		{name: "empty", content: nil, wantErr: ErrUnsupportedFileType},
+		{name: "too large", content: []byte("plain"), size: 1<<20 + 1, wantErr: ErrAttachmentTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := tt.size
+			if size == 0 {
+				size = int64(len(tt.content))
+			}
+			_, err := svc.Upload(ctx, 1, 1, "file", size, bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Upload() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if len(attachments.attachments) != 0 {
+		t.Fatalf("rejected uploads left %d rows", len(attachments.attachments))
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+// Board returns one column per workflow status, each with its first
+// query.Limit todos in manual order and the status's total. Todos left in a
+// status since dropped from the config aren't on the board.
+func (s *todoService) Board(ctx context.Context, userID int, query models.TodoBoardQuery) (*models.TodoBoard, error) {
+	filter, err := buildTodoFilter(models.TodoListQuery{
+		ListID: query.ListID,
+		Sort:   models.TodoSortPosition,
+		Limit:  query.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := s.flow.Statuses()
+	board := &models.TodoBoard{Columns: make([]models.TodoBoardColumn, 0, len(statuses))}
+	for _, status := range statuses {
+		filter.Status = status
+		todos, err := s.repo.List(ctx, userID, filter)
+		if err != nil {
+			return nil, err
+		}
+		total, err := s.repo.Count(ctx, userID, filter)
+		if err != nil {
+			return nil, err
+		}
+		if todos == nil {
+			todos = []models.Todo{}
+		}
+		board.Columns = append(board.Columns, models.TodoBoardColumn{
+			Status: status,
+			Next:   s.flow.Next(status),
+			Total:  total,
+			Todos:  todos,
+		})
+	}
+	return board, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// ErrInvalidBulk is returned for bulk requests that can't be run at all.
+var ErrInvalidBulk = errors.New("invalid bulk request")
+
+// MaxBulkItems caps the number of todo IDs across all operations of one request.
+const MaxBulkItems = 500
+
+// errBulkAborted unwinds the transaction after an item fails in atomic mode.
+var errBulkAborted = errors.New("bulk request aborted")
+
+// Bulk applies every operation inside one transaction. In atomic mode the
+// first failure rolls everything back; in best-effort mode each item runs in
+// its own savepoint so a failure only undoes that item.
+func (s *todoService) Bulk(ctx context.Context, userID int, req models.BulkTodoRequest) (*models.BulkTodoResult, error) {
+	mode := models.BulkMode(req.Mode)
+	if mode == "" {
+		mode = models.BulkModeAtomic
+	}
+
+	items := 0
+	for _, op := range req.Operations {
+		items += len(op.IDs)
+	}
+	if items > MaxBulkItems {
+		return nil, fmt.Errorf("%w: at most %d todos per request", ErrInvalidBulk, MaxBulkItems)
+	}
+
+	result := &models.BulkTodoResult{Mode: mode, Results: make([]models.BulkTodoItemResult, 0, items)}
+	for _, op := range req.Operations {
+		for _, id := range op.IDs {
+			result.Results = append(result.Results, models.BulkTodoItemResult{Op: models.BulkTodoOp(op.Op), ID: id, Status: models.BulkItemSkipped})
+		}
+	}
+
+	err := s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		i := 0
+		for _, op := range req.Operations {
+			for range op.IDs {
+				item := &result.Results[i]
+				i++
+
+				var todo *models.Todo
+				var err error
+				if mode == models.BulkModeBestEffort {
+					err = repo.Transaction(ctx, func(itemRepo repository.TodoRepository) error {
+						todo, err = s.applyBulk(ctx, itemRepo, userID, item.ID, op)
+						return err
+					})
+				} else {
+					todo, err = s.applyBulk(ctx, repo, userID, item.ID, op)
+				}
+
+				if err != nil {
+					item.Status = models.BulkItemFailed
+					item.Error = bulkItemError(err)
+					if mode == models.BulkModeAtomic {
+						return errBulkAborted
+					}
+					continue
+				}
+				item.Status = models.BulkItemOK
+				item.Todo = todo
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBulkAborted) {
+		return nil, err
+	}
+
+	result.Committed = err == nil
+	for i := range result.Results {
+		item := &result.Results[i]
+		if !result.Committed && item.Status == models.BulkItemOK {
+			item.Status = models.BulkItemRolledBack
+			item.Todo = nil
+		}
+		switch item.Status {
+		case models.BulkItemOK:
+			result.Succeeded++
+		case models.BulkItemFailed:
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// applyBulk runs one operation on one todo through the same code paths as
+// the single-todo endpoints, so roll-ups and recurrences still happen.
+func (s *todoService) applyBulk(ctx context.Context, repo repository.TodoRepository, userID, id int, op models.BulkTodoOperationInput) (*models.Todo, error) {
+	switch models.BulkTodoOp(op.Op) {
+	case models.BulkOpComplete, models.BulkOpUncomplete:
+		completed := models.BulkTodoOp(op.Op) == models.BulkOpComplete
+		return s.update(ctx, repo, userID, id, models.UpdateTodoRequest{Completed: &completed})
+	case models.BulkOpUpdate:
+		if op.Fields == nil {
+			return nil, fmt.Errorf("%w: update needs fields", ErrInvalidBulk)
+		}
+		return s.update(ctx, repo, userID, id, *op.Fields)
+	case models.BulkOpDelete:
+		return nil, s.delete(ctx, repo, userID, id, models.SubtaskDeleteMode(op.Subtasks))
+	}
+	return nil, fmt.Errorf("%w: unsupported op %q", ErrInvalidBulk, op.Op)
+}
+
+// bulkItemError hides sql.ErrNoRows behind the same wording the API uses elsewhere.
+func bulkItemError(err error) string {
+	if errors.Is(err, sql.ErrNoRows) {
+		return "todo not found"
+	}
+	return err.Error()
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// TodoCommentService lets everyone who can see a todo read and add comments.
+// Only the author edits a comment; the author or the todo's owner deletes it.
+// Todos the user can't see are reported as sql.ErrNoRows.
+type TodoCommentService interface {
+	List(ctx context.Context, userID, todoID int) ([]models.TodoComment, error)
+	Create(ctx context.Context, userID, todoID int, req models.CreateTodoCommentRequest) (*models.TodoComment, error)
+	Update(ctx context.Context, userID, todoID, id int, req models.UpdateTodoCommentRequest) (*models.TodoComment, error)
+	Delete(ctx context.Context, userID, todoID, id int) error
+}
+
+type todoCommentService struct {
+	repo  repository.TodoCommentRepository
+	todos repository.TodoRepository
+	now   func() time.Time
+}
+
+func NewTodoCommentService(repo repository.TodoCommentRepository, todos repository.TodoRepository) TodoCommentService {
+	return &todoCommentService{repo: repo, todos: todos, now: time.Now}
+}
+
+func (s *todoCommentService) List(ctx context.Context, userID, todoID int) ([]models.TodoComment, error) {
+	if _, err := s.todo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, todoID)
+}
+
+func (s *todoCommentService) Create(ctx context.Context, userID, todoID int, req models.CreateTodoCommentRequest) (*models.TodoComment, error) {
+	if _, err := s.todo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.TodoComment{
+		TodoID: todoID,
+		UserID: userID,
+		Body:   strings.TrimSpace(req.Body),
+	}
+	if err := s.repo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *todoCommentService) Update(ctx context.Context, userID, todoID, id int, req models.UpdateTodoCommentRequest) (*models.TodoComment, error) {
+	comment, _, err := s.comment(ctx, userID, todoID, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	editedAt := s.now()
+	comment.Body = strings.TrimSpace(req.Body)
+	comment.EditedAt = &editedAt
+	if err := s.repo.Update(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *todoCommentService) Delete(ctx context.Context, userID, todoID, id int) error {
+	comment, todo, err := s.comment(ctx, userID, todoID, id)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID && todo.UserID != userID {
+		return ErrForbidden
+	}
+	return s.repo.Delete(ctx, todoID, id)
+}
+
+// todo returns the todo if userID can see it.
+func (s *todoCommentService) todo(ctx context.Context, userID, todoID int) (*models.Todo, error) {
+	todo, err := s.todos.GetByID(ctx, userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, sql.ErrNoRows
+	}
+	return todo, nil
+}
+
+func (s *todoCommentService) comment(ctx context.Context, userID, todoID, id int) (*models.TodoComment, *models.Todo, error) {
+	todo, err := s.todo(ctx, userID, todoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	comment, err := s.repo.GetByID(ctx, todoID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if comment == nil {
+		return nil, nil, sql.ErrNoRows
+	}
+	return comment, todo, nil
+}
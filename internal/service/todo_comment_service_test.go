@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+type todoCommentRepoMock struct {
+	comments map[int]*models.TodoComment
+	nextID   int
+}
+
+func (m *todoCommentRepoMock) List(ctx context.Context, todoID int) ([]models.TodoComment, error) {
+	var result []models.TodoComment
+	for id := 1; id <= m.nextID; id++ {
+		if comment, ok := m.comments[id]; ok && comment.TodoID == todoID {
+			result = append(result, *comment)
+		}
+	}
+	return result, nil
+}
+
+func (m *todoCommentRepoMock) GetByID(ctx context.Context, todoID, id int) (*models.TodoComment, error) {
+	comment, ok := m.comments[id]
+	if !ok || comment.TodoID != todoID {
+		return nil, nil
+	}
+	copied := *comment
+	return &copied, nil
+}
+
+func (m *todoCommentRepoMock) Create(ctx context.Context, comment *models.TodoComment) error {
+	m.nextID++
+	comment.ID = m.nextID
+	comment.CreatedAt = time.Now()
+	copied := *comment
+	m.comments[comment.ID] = &copied
+	return nil
+}
+
+func (m *todoCommentRepoMock) Update(ctx context.Context, comment *models.TodoComment) error {
+	if _, ok := m.comments[comment.ID]; !ok {
+		return sql.ErrNoRows
+	}
+	copied := *comment
+	m.comments[comment.ID] = &copied
+	return nil
+}
+
+func (m *todoCommentRepoMock) Delete(ctx context.Context, todoID, id int) error {
+	if _, ok := m.comments[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.comments, id)
+	return nil
+}
+
+func TestTodoCommentServicePermissions(t *testing.T) {
+	ctx := context.Background()
+	listID := 1
+	todos := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Plan trip", ListID: &listID},
+			2: {ID: 2, UserID: 1, Title: "Private"},
+		},
+		lists:   map[int]models.TodoList{1: {ID: 1, UserID: 1, Name: "Travel"}},
+		members: map[int]map[int]models.ListRole{1: {2: models.ListRoleViewer}},
+	}
+	comments := &todoCommentRepoMock{comments: map[int]*models.TodoComment{}}
+	svc := NewTodoCommentService(comments, todos)
+
+	// Viewers of a shared todo can join the discussion.
+	comment, err := svc.Create(ctx, 2, 1, models.CreateTodoCommentRequest{Body: "  Book the train?  "})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if comment.UserID != 2 || comment.Body != "Book the train?" || comment.EditedAt != nil {
+		t.Fatalf("Create() = %+v", comment)
+	}
+	if _, err := svc.Create(ctx, 2, 2, models.CreateTodoCommentRequest{Body: "Hi"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Create() on an unshared todo error = %v, want sql.ErrNoRows", err)
+	}
+
+	if _, err := svc.Update(ctx, 1, 1, comment.ID, models.UpdateTodoCommentRequest{Body: "Rewritten"}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Update() by the todo owner error = %v, want ErrForbidden", err)
+	}
+	edited, err := svc.Update(ctx, 2, 1, comment.ID, models.UpdateTodoCommentRequest{Body: "Book the night train?"})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if edited.Body != "Book the night train?" || edited.EditedAt == nil {
+		t.Fatalf("Update() = %+v, want new body and edited_at", edited)
+	}
+	if _, err := svc.Update(ctx, 2, 2, comment.ID, models.UpdateTodoCommentRequest{Body: "x"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Update() through another todo error = %v, want sql.ErrNoRows", err)
+	}
+
+	listed, err := svc.List(ctx, 1, 1)
+	if err != nil || len(listed) != 1 || listed[0].Body != "Book the night train?" {
+		t.Fatalf("List() = %+v, %v", listed, err)
+	}
+
+	// The todo's owner may remove comments from their todo.
+	if err := svc.Delete(ctx, 1, 1, comment.ID); err != nil {
+		t.Fatalf("Delete() by the todo owner error = %v", err)
+	}
+	if listed, _ := svc.List(ctx, 2, 1); len(listed) != 0 {
+		t.Fatalf("List() after delete = %+v, want none", listed)
+	}
+}
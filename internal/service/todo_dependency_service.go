@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// ErrInvalidDependency is returned when the blocker isn't a todo the caller
+// can see, or is the todo itself.
+var ErrInvalidDependency = errors.New("invalid dependency")
+
+// ErrDependencyCycle is returned when the new blocker already waits on the
+// todo, directly or through other todos.
+var ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+// TodoDependencyService manages which todos block which. Everyone who can
+// see a todo can list its dependencies; changing them needs the same rights
+// as editing the blocked todo, and the blocker must be visible to the caller.
+type TodoDependencyService interface {
+	List(ctx context.Context, userID, todoID int) (*models.TodoDependencies, error)
+	Add(ctx context.Context, userID, todoID int, req models.AddTodoDependencyRequest) (*models.TodoDependencies, error)
+	Remove(ctx context.Context, userID, todoID, blockerID int) error
+}
+
+type todoDependencyService struct {
+	repo  repository.TodoRepository
+	todos TodoService
+}
+
+func NewTodoDependencyService(repo repository.TodoRepository, todos TodoService) TodoDependencyService {
+	return &todoDependencyService{repo: repo, todos: todos}
+}
+
+func (s *todoDependencyService) List(ctx context.Context, userID, todoID int) (*models.TodoDependencies, error) {
+	if _, err := s.todo(ctx, s.repo, userID, todoID, false); err != nil {
+		return nil, err
+	}
+	return s.dependencies(ctx, userID, todoID)
+}
+
+// Add is idempotent: adding an existing blocker again changes nothing.
+func (s *todoDependencyService) Add(ctx context.Context, userID, todoID int, req models.AddTodoDependencyRequest) (*models.TodoDependencies, error) {
+	if req.BlockedByID == todoID {
+		return nil, fmt.Errorf("%w: a todo can't block itself", ErrInvalidDependency)
+	}
+
+	err := s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		if _, err := s.todo(ctx, repo, userID, todoID, true); err != nil {
+			return err
+		}
+		blocker, err := repo.GetByID(ctx, userID, req.BlockedByID)
+		if err != nil {
+			return err
+		}
+		if blocker == nil {
+			return fmt.Errorf("%w: todo %d does not exist", ErrInvalidDependency, req.BlockedByID)
+		}
+
+		if err := repo.LockDependencies(ctx); err != nil {
+			return err
+		}
+		cycle, err := repo.IsBlockedBy(ctx, blocker.ID, todoID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return fmt.Errorf("%w: todo %d already waits on todo %d", ErrDependencyCycle, blocker.ID, todoID)
+		}
+		_, err = repo.AddDependency(ctx, todoID, blocker.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.dependencies(ctx, userID, todoID)
+}
+
+func (s *todoDependencyService) Remove(ctx context.Context, userID, todoID, blockerID int) error {
+	if _, err := s.todo(ctx, s.repo, userID, todoID, true); err != nil {
+		return err
+	}
+	return s.repo.RemoveDependency(ctx, todoID, blockerID)
+}
+
+// todo returns the todo if userID can see it and, with write, edit it.
+func (s *todoDependencyService) todo(ctx context.Context, repo repository.TodoRepository, userID, todoID int, write bool) (*models.Todo, error) {
+	todo, err := repo.GetByID(ctx, userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, sql.ErrNoRows
+	}
+	if write {
+		if err := s.todos.authorize(ctx, repo, userID, todo); err != nil {
+			return nil, err
+		}
+	}
+	return todo, nil
+}
+
+func (s *todoDependencyService) dependencies(ctx context.Context, userID, todoID int) (*models.TodoDependencies, error) {
+	blockedBy, err := s.repo.ListBlockers(ctx, userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := s.repo.ListBlocked(ctx, userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+	open, err := s.repo.OpenBlockers(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TodoDependencies{BlockedBy: blockedBy, Blocks: blocks, Ready: open == 0}, nil
+}
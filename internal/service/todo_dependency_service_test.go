@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/workflow"
+)
+
+func newDependencyRepo() *todoRepoMock {
+	listID := 1
+	return &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Pour foundation", ListID: &listID},
+			2: {ID: 2, UserID: 1, Title: "Build walls", ListID: &listID},
+			3: {ID: 3, UserID: 1, Title: "Add roof", ListID: &listID},
+			4: {ID: 4, UserID: 9, Title: "Someone else's"},
+		},
+		lists:   map[int]models.TodoList{1: {ID: 1, UserID: 1, Name: "House"}},
+		members: map[int]map[int]models.ListRole{1: {2: models.ListRoleViewer}},
+	}
+}
+
+func TestTodoDependencyServiceBlocksCompletion(t *testing.T) {
+	ctx := context.Background()
+	repo := newDependencyRepo()
+	todos := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+	deps := NewTodoDependencyService(repo, todos)
+
+	got, err := deps.Add(ctx, 1, 2, models.AddTodoDependencyRequest{BlockedByID: 1})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if len(got.BlockedBy) != 1 || got.BlockedBy[0].ID != 1 || got.Ready {
+		t.Fatalf("Add() = %+v, want todo 2 blocked by todo 1", got)
+	}
+	if _, err := deps.Add(ctx, 1, 2, models.AddTodoDependencyRequest{BlockedByID: 1}); err != nil {
+		t.Fatalf("Add() again error = %v", err)
+	}
+
+	completed := true
+	if _, err := todos.Update(ctx, 1, 2, models.UpdateTodoRequest{Completed: &completed}); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("Update() expected ErrBlocked, got %v", err)
+	}
+	if repo.todos[2].Completed {
+		t.Fatal("blocked todo was completed")
+	}
+
+	if _, err := todos.Update(ctx, 1, 1, models.UpdateTodoRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() blocker error = %v", err)
+	}
+	if got, err := deps.List(ctx, 1, 2); err != nil || !got.Ready {
+		t.Fatalf("List() = %+v, %v, want ready", got, err)
+	}
+	if _, err := todos.Update(ctx, 1, 2, models.UpdateTodoRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() after blocker done error = %v", err)
+	}
+}
+
+func TestTodoDependencyServiceRejectsCycles(t *testing.T) {
+	ctx := context.Background()
+	repo := newDependencyRepo()
+	deps := NewTodoDependencyService(repo, NewTodoService(repo, workflow.Default(), config.TodoConfig{}))
+
+	// Walls wait on the foundation, the roof on the walls.
+	for _, edge := range [][2]int{{2, 1}, {3, 2}} {
+		if _, err := deps.Add(ctx, 1, edge[0], models.AddTodoDependencyRequest{BlockedByID: edge[1]}); err != nil {
+			t.Fatalf("Add(%d blocked by %d) error = %v", edge[0], edge[1], err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		todoID  int
+		blocker int
+		wantErr error
+	}{
+		{name: "direct cycle", todoID: 1, blocker: 2, wantErr: ErrDependencyCycle},
+		{name: "transitive cycle", todoID: 1, blocker: 3, wantErr: ErrDependencyCycle},
+		{name: "self", todoID: 1, blocker: 1, wantErr: ErrInvalidDependency},
+		{name: "invisible blocker", todoID: 1, blocker: 4, wantErr: ErrInvalidDependency},
+		{name: "missing blocker", todoID: 1, blocker: 99, wantErr: ErrInvalidDependency},
+		{name: "invisible todo", todoID: 4, blocker: 1, wantErr: sql.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := deps.Add(ctx, 1, tt.todoID, models.AddTodoDependencyRequest{BlockedByID: tt.blocker})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Add() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if len(repo.blockers[1]) != 0 {
+		t.Fatalf("rejected dependencies were stored: %v", repo.blockers)
+	}
+}
+
+func TestTodoDependencyServicePermissions(t *testing.T) {
+	ctx := context.Background()
+	repo := newDependencyRepo()
+	repo.blockers = map[int][]int{2: {1}}
+	deps := NewTodoDependencyService(repo, NewTodoService(repo, workflow.Default(), config.TodoConfig{}))
+
+	// A viewer of the list sees the dependencies but can't change them.
+	got, err := deps.List(ctx, 2, 1)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got.Blocks) != 1 || got.Blocks[0].ID != 2 || !got.Ready {
+		t.Fatalf("List() = %+v, want todo 1 blocking todo 2", got)
+	}
+	if _, err := deps.Add(ctx, 2, 3, models.AddTodoDependencyRequest{BlockedByID: 1}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Add() by viewer expected ErrForbidden, got %v", err)
+	}
+	if err := deps.Remove(ctx, 2, 2, 1); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Remove() by viewer expected ErrForbidden, got %v", err)
+	}
+	if _, err := deps.List(ctx, 3, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("List() by stranger expected sql.ErrNoRows, got %v", err)
+	}
+
+	if err := deps.Remove(ctx, 1, 2, 1); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := deps.Remove(ctx, 1, 2, 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Remove() again expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestTodoServiceListReady(t *testing.T) {
+	ctx := context.Background()
+	repo := newDependencyRepo()
+	repo.blockers = map[int][]int{2: {1}, 3: {2}}
+	repo.todos[1].SetStatus(models.StatusDone)
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	page, err := svc.List(ctx, 1, models.TodoListQuery{Ready: true})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	// 1 is done and 3 waits on the open 2.
+	if len(page.Todos) != 1 || page.Todos[0].ID != 2 {
+		t.Fatalf("List(ready) = %+v, want only todo 2", page.Todos)
+	}
+}
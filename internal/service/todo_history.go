@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// History returns the change log of a todo the user can see.
+func (s *todoService) History(ctx context.Context, userID, id int) ([]models.TodoEvent, error) {
+	todo, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, sql.ErrNoRows
+	}
+	return s.repo.ListEvents(ctx, id)
+}
+
+// record writes one history entry through repo, which must be the
+// transaction the change itself runs in.
+func (s *todoService) record(ctx context.Context, repo repository.TodoRepository, userID int, todo *models.Todo, action models.TodoEventAction, changes map[string]models.FieldChange) error {
+	return repo.AddEvent(ctx, &models.TodoEvent{
+		TodoID:  todo.ID,
+		UserID:  &userID,
+		Action:  action,
+		Changes: changes,
+	})
+}
+
+// recordCreate logs every field the new todo was created with.
+func (s *todoService) recordCreate(ctx context.Context, repo repository.TodoRepository, userID int, todo *models.Todo) error {
+	return s.record(ctx, repo, userID, todo, models.TodoEventCreated, diffTodo(&models.Todo{}, todo))
+}
+
+// recordUpdate logs the fields that differ between before and after; a
+// change of completed is logged as completing or reopening the todo. Nothing
+// is written when nothing changed.
+func (s *todoService) recordUpdate(ctx context.Context, repo repository.TodoRepository, userID int, before, after *models.Todo) error {
+	changes := diffTodo(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	action := models.TodoEventUpdated
+	if _, ok := changes["completed"]; ok {
+		action = models.TodoEventReopened
+		if after.Completed {
+			action = models.TodoEventCompleted
+		}
+	}
+	return s.record(ctx, repo, userID, after, action, changes)
+}
+
+// diffTodo compares the user-editable fields. Position is left out: rank keys
+// mean nothing to a reader and a drag would flood the history.
+func diffTodo(before, after *models.Todo) map[string]models.FieldChange {
+	changes := make(map[string]models.FieldChange)
+	diffValue(changes, "title", before.Title, after.Title)
+	diffValue(changes, "description", before.Description, after.Description)
+	diffValue(changes, "status", before.Status, after.Status)
+	diffValue(changes, "completed", before.Completed, after.Completed)
+	diffValue(changes, "priority", before.Priority, after.Priority)
+	diffPointer(changes, "parent_id", before.ParentID, after.ParentID)
+	diffPointer(changes, "list_id", before.ListID, after.ListID)
+	diffPointer(changes, "assignee_id", before.AssigneeID, after.AssigneeID)
+	diffPointer(changes, "recurrence_rule", before.RecurrenceRule, after.RecurrenceRule)
+
+	if !sameTime(before.DueAt, after.DueAt) {
+		changes["due_at"] = models.FieldChange{Before: before.DueAt, After: after.DueAt}
+	}
+	if !sameTime(before.RemindAt, after.RemindAt) {
+		changes["remind_at"] = models.FieldChange{Before: before.RemindAt, After: after.RemindAt}
+	}
+	if beforeTags, afterTags := tagIDs(before.Tags), tagIDs(after.Tags); !slices.Equal(beforeTags, afterTags) {
+		changes["tag_ids"] = models.FieldChange{Before: beforeTags, After: afterTags}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func diffValue[T comparable](changes map[string]models.FieldChange, field string, before, after T) {
+	if before != after {
+		changes[field] = models.FieldChange{Before: before, After: after}
+	}
+}
+
+func diffPointer[T comparable](changes map[string]models.FieldChange, field string, before, after *T) {
+	if before == nil && after == nil {
+		return
+	}
+	if before != nil && after != nil && *before == *after {
+		return
+	}
+	changes[field] = models.FieldChange{Before: before, After: after}
+}
+
+// sameTime compares instants, since a due date read back from the database
+// carries a different location than the one parsed from the request.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+func tagIDs(tags []models.Tag) []int {
+	ids := make([]int, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// ErrTodosNotInList is returned when a move names todos that aren't in the source list.
+var ErrTodosNotInList = errors.New("some todos are not in the source list")
+
+// ErrMemberNotFound is returned when an invite names an email with no account.
+var ErrMemberNotFound = errors.New("no registered user with that email")
+
+// ErrNotMember is returned when changing a membership that doesn't exist.
+var ErrNotMember = errors.New("user is not a member of this list")
+
+// ErrAlreadyMember is returned when inviting someone who already has access.
+var ErrAlreadyMember = errors.New("user is already a member of this list")
+
+type TodoListService interface {
+	GetAll(ctx context.Context, userID int, archived bool) ([]models.TodoList, error)
+	GetByID(ctx context.Context, userID, id int) (*models.TodoList, error)
+	Create(ctx context.Context, userID int, req models.CreateTodoListRequest) (*models.TodoList, error)
+	Update(ctx context.Context, userID, id int, req models.UpdateTodoListRequest) (*models.TodoList, error)
+	Delete(ctx context.Context, userID, id int) error
+	SetArchived(ctx context.Context, userID, id int, archived bool) (*models.TodoList, error)
+	CountOpen(ctx context.Context, userID, id int) (int64, error)
+	MoveTodos(ctx context.Context, userID, id int, req models.MoveTodosRequest) error
+	GetMembers(ctx context.Context, userID, id int) ([]models.ListMember, error)
+	AddMember(ctx context.Context, userID, id int, req models.AddListMemberRequest) (*models.ListMember, error)
+	UpdateMember(ctx context.Context, userID, id, memberID int, req models.UpdateListMemberRequest) (*models.ListMember, error)
+	RemoveMember(ctx context.Context, userID, id, memberID int) error
+}
+
+// todoListService lets members read a shared list, editors change its todos
+// and only the owner change the list itself or who it is shared with.
+type todoListService struct {
+	repo     repository.TodoListRepository
+	todoRepo repository.TodoRepository
+	todos    TodoService
+	userRepo repository.UserRepository
+}
+
+func NewTodoListService(repo repository.TodoListRepository, todoRepo repository.TodoRepository, todos TodoService, userRepo repository.UserRepository) TodoListService {
+	return &todoListService{repo: repo, todoRepo: todoRepo, todos: todos, userRepo: userRepo}
+}
+
+func (s *todoListService) GetAll(ctx context.Context, userID int, archived bool) ([]models.TodoList, error) {
+	return s.repo.GetAll(ctx, userID, archived)
+}
+
+func (s *todoListService) GetByID(ctx context.Context, userID, id int) (*models.TodoList, error) {
+	return s.repo.GetByID(ctx, userID, id)
+}
+
+func (s *todoListService) Create(ctx context.Context, userID int, req models.CreateTodoListRequest) (*models.TodoList, error) {
+	list := &models.TodoList{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+	if err := s.repo.Create(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *todoListService) Update(ctx context.Context, userID, id int, req models.UpdateTodoListRequest) (*models.TodoList, error) {
+	list, err := s.ownedList(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		list.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		list.Description = *req.Description
+	}
+
+	if err := s.repo.Update(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *todoListService) Delete(ctx context.Context, userID, id int) error {
+	list, err := s.ownedList(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	// The todos go to the end of their owners' todos without a list, which
+	// the foreign key alone would leave unordered.
+	return s.todoRepo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		todos, err := repo.FindInList(ctx, list.ID, nil)
+		if err != nil {
+			return err
+		}
+		if err := s.moveTodos(ctx, repo, userID, todos, nil); err != nil {
+			return err
+		}
+		return repo.DeleteList(ctx, list.UserID, list.ID)
+	})
+}
+
+// SetArchived archives or restores a list. Archived lists keep their todos
+// but are hidden from the default listing and can't receive new todos.
+func (s *todoListService) SetArchived(ctx context.Context, userID, id int, archived bool) (*models.TodoList, error) {
+	list, err := s.ownedList(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if list.Archived == archived {
+		return list, nil
+	}
+
+	list.Archived = archived
+	list.ArchivedAt = nil
+	if archived {
+		now := time.Now().UTC()
+		list.ArchivedAt = &now
+	}
+	if err := s.repo.Update(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *todoListService) CountOpen(ctx context.Context, userID, id int) (int64, error) {
+	list, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return 0, err
+	}
+	if list == nil {
+		return 0, sql.ErrNoRows
+	}
+	return s.repo.CountOpen(ctx, list.ID)
+}
+
+// MoveTodos moves todos out of list id into req.TargetListID; either all of
+// them move or none do.
+func (s *todoListService) MoveTodos(ctx context.Context, userID, id int, req models.MoveTodosRequest) error {
+	source, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return sql.ErrNoRows
+	}
+	if !source.Role.CanEdit() {
+		return ErrForbidden
+	}
+
+	if req.TargetListID != nil {
+		target, err := s.repo.GetByID(ctx, userID, *req.TargetListID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrInvalidList
+		}
+		if !target.Role.CanEdit() {
+			return ErrForbidden
+		}
+		if target.Archived {
+			return ErrListArchived
+		}
+	}
+
+	todoIDs := slices.Clone(req.TodoIDs)
+	slices.Sort(todoIDs)
+	todoIDs = slices.Compact(todoIDs)
+
+	return s.todoRepo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		todos, err := repo.FindInList(ctx, source.ID, todoIDs)
+		if err != nil {
+			return err
+		}
+		if len(todos) != len(todoIDs) {
+			return ErrTodosNotInList
+		}
+		return s.moveTodos(ctx, repo, userID, todos, req.TargetListID)
+	})
+}
+
+// moveTodos appends todos to listID and logs each todo's new list, and the
+// assignee it may have lost on the way, to its history.
+func (s *todoListService) moveTodos(ctx context.Context, repo repository.TodoRepository, userID int, todos []models.Todo, listID *int) error {
+	moved, err := repo.MoveToList(ctx, todos, listID)
+	if err != nil {
+		return err
+	}
+
+	before := make(map[int]*models.Todo, len(todos))
+	for i := range todos {
+		before[todos[i].ID] = &todos[i]
+	}
+	for i := range moved {
+		if err := s.todos.recordUpdate(ctx, repo, userID, before[moved[i].ID], &moved[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMembers lists who the list is shared with; any member may look.
+func (s *todoListService) GetMembers(ctx context.Context, userID, id int) ([]models.ListMember, error) {
+	list, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		return nil, sql.ErrNoRows
+	}
+	return s.repo.GetMembers(ctx, list.ID)
+}
+
+// AddMember shares the list with the registered user behind req.Email.
+func (s *todoListService) AddMember(ctx context.Context, userID, id int, req models.AddListMemberRequest) (*models.ListMember, error) {
+	list, err := s.ownedList(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(req.Email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == list.UserID {
+		return nil, ErrAlreadyMember
+	}
+
+	member := &models.ListMember{
+		ListID: list.ID,
+		UserID: user.ID,
+		Role:   models.ListRole(req.Role),
+	}
+	if err := s.repo.AddMember(ctx, member); err != nil {
+		if isUniqueViolation(err, "list_members_pkey") {
+			return nil, ErrAlreadyMember
+		}
+		return nil, err
+	}
+	member.User = &models.Author{ID: user.ID, Name: user.Name}
+	return member, nil
+}
+
+func (s *todoListService) UpdateMember(ctx context.Context, userID, id, memberID int, req models.UpdateListMemberRequest) (*models.ListMember, error) {
+	list, err := s.ownedList(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.GetMember(ctx, list.ID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrNotMember
+	}
+
+	member.Role = models.ListRole(req.Role)
+	if err := s.repo.UpdateMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember revokes access. The owner can remove anyone; members can
+// only remove themselves, i.e. leave the list.
+func (s *todoListService) RemoveMember(ctx context.Context, userID, id, memberID int) error {
+	list, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		return sql.ErrNoRows
+	}
+	if list.Role != models.ListRoleOwner && memberID != userID {
+		return ErrForbidden
+	}
+
+	err = s.repo.RemoveMember(ctx, list.ID, memberID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotMember
+	}
+	return err
+}
+
+// ownedList loads a list for a change only its owner may make. Members get
+// ErrForbidden; everyone else sql.ErrNoRows so list IDs don't leak.
+func (s *todoListService) ownedList(ctx context.Context, userID, id int) (*models.TodoList, error) {
+	list, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		return nil, sql.ErrNoRows
+	}
+	if list.Role != models.ListRoleOwner {
+		return nil, ErrForbidden
+	}
+	return list, nil
+}
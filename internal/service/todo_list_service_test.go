@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/workflow"
+)
+
+// todoListRepoMock works on the lists, members and todos of a todoRepoMock,
+// so both services see the same data.
+type todoListRepoMock struct {
+	todos *todoRepoMock
+}
+
+func (m *todoListRepoMock) GetAll(ctx context.Context, userID int, archived bool) ([]models.TodoList, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *todoListRepoMock) GetByID(ctx context.Context, userID, id int) (*models.TodoList, error) {
+	return m.todos.FindList(ctx, userID, id)
+}
+
+func (m *todoListRepoMock) Create(ctx context.Context, list *models.TodoList) error {
+	return errors.New("not implemented")
+}
+
+func (m *todoListRepoMock) Update(ctx context.Context, list *models.TodoList) error {
+	return errors.New("not implemented")
+}
+
+func (m *todoListRepoMock) CountOpen(ctx context.Context, listID int) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (m *todoListRepoMock) GetMembers(ctx context.Context, listID int) ([]models.ListMember, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *todoListRepoMock) GetMember(ctx context.Context, listID, userID int) (*models.ListMember, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *todoListRepoMock) AddMember(ctx context.Context, member *models.ListMember) error {
+	return errors.New("not implemented")
+}
+
+func (m *todoListRepoMock) UpdateMember(ctx context.Context, member *models.ListMember) error {
+	return errors.New("not implemented")
+}
+
+// RemoveMember mirrors the repository: the member also loses the list's
+// todos assigned to them that they don't own.
+func (m *todoListRepoMock) RemoveMember(ctx context.Context, listID, userID int) error {
+	if _, ok := m.todos.members[listID][userID]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.todos.members[listID], userID)
+	for _, todo := range m.todos.todos {
+		if todo.ListID != nil && *todo.ListID == listID && assignedTo(todo, userID) && todo.UserID != userID {
+			todo.AssigneeID = nil
+		}
+	}
+	return nil
+}
+
+func TestTodoListServiceRemoveMemberUnassignsTodos(t *testing.T) {
+	ctx := context.Background()
+	listID, member := 1, 2
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Handed over", ListID: &listID, AssigneeID: &member},
+			2: {ID: 2, UserID: 2, Title: "Their own", ListID: &listID, AssigneeID: &member},
+		},
+		lists:   map[int]models.TodoList{1: {ID: 1, UserID: 1, Name: "Team"}},
+		members: map[int]map[int]models.ListRole{1: {member: models.ListRoleEditor}},
+	}
+	todos := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+	lists := NewTodoListService(&todoListRepoMock{todos: repo}, repo, todos, &userRepoMock{})
+
+	if err := lists.RemoveMember(ctx, 1, listID, member); err != nil {
+		t.Fatalf("RemoveMember() error = %v", err)
+	}
+
+	if todo, err := todos.GetByID(ctx, member, 1); err != nil || todo != nil {
+		t.Fatalf("GetByID() for the removed member = %+v, %v; want not found", todo, err)
+	}
+	if repo.todos[1].AssigneeID != nil {
+		t.Fatalf("todo 1 is still assigned to %d", *repo.todos[1].AssigneeID)
+	}
+	if repo.todos[2].AssigneeID == nil {
+		t.Fatal("todo 2 lost its owner as assignee")
+	}
+}
+
+func TestTodoListServiceMovesAreRecorded(t *testing.T) {
+	ctx := context.Background()
+	teamID, otherID, member := 1, 2, 2
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Handed over", ListID: &teamID, AssigneeID: &member, Position: "a"},
+			2: {ID: 2, UserID: 1, Title: "Stays", ListID: &teamID, Position: "b"},
+		},
+		lists: map[int]models.TodoList{
+			1: {ID: 1, UserID: 1, Name: "Team"},
+			2: {ID: 2, UserID: 1, Name: "Private"},
+		},
+		members: map[int]map[int]models.ListRole{1: {member: models.ListRoleEditor}},
+	}
+	todos := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+	lists := NewTodoListService(&todoListRepoMock{todos: repo}, repo, todos, &userRepoMock{})
+
+	err := lists.MoveTodos(ctx, 1, teamID, models.MoveTodosRequest{TodoIDs: []int{1}, TargetListID: &otherID})
+	if err != nil {
+		t.Fatalf("MoveTodos() error = %v", err)
+	}
+	history, err := todos.History(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("History() = %d events, want 1", len(history))
+	}
+	for _, field := range []string{"list_id", "assignee_id"} {
+		if _, ok := history[0].Changes[field]; !ok {
+			t.Fatalf("move did not record %s: %+v", field, history[0].Changes)
+		}
+	}
+
+	if err := lists.Delete(ctx, 1, teamID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	history, err = todos.History(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 1 || history[0].Changes["list_id"].After != (*int)(nil) {
+		t.Fatalf("deleting the list recorded %+v, want list_id cleared", history)
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/rank"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// ErrInvalidMove is returned when a move's neighbours can't frame the todo.
+var ErrInvalidMove = errors.New("invalid move")
+
+// Move places a todo right after after_id and/or right before before_id. Only
+// the moved todo gets a new key; the scope stays locked until the transaction
+// commits, so concurrent moves in the same list can't pick the same key.
+func (s *todoService) Move(ctx context.Context, userID, id int, req models.MoveTodoRequest) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		var err error
+		if todo, err = repo.GetByID(ctx, userID, id); err != nil {
+			return err
+		}
+		if todo == nil {
+			return sql.ErrNoRows
+		}
+		if err := s.authorize(ctx, repo, userID, todo); err != nil {
+			return err
+		}
+		if err := repo.LockPositions(ctx, todo); err != nil {
+			return err
+		}
+
+		var lower, upper string
+		if req.AfterID != nil {
+			if lower, err = s.neighbourPosition(ctx, repo, userID, todo, *req.AfterID); err != nil {
+				return err
+			}
+		}
+		if req.BeforeID != nil {
+			if upper, err = s.neighbourPosition(ctx, repo, userID, todo, *req.BeforeID); err != nil {
+				return err
+			}
+		}
+
+		// With one neighbour given, the other side is whatever follows or
+		// precedes it now.
+		switch {
+		case req.BeforeID == nil:
+			upper, err = repo.NeighbourPosition(ctx, todo, lower, true)
+		case req.AfterID == nil:
+			lower, err = repo.NeighbourPosition(ctx, todo, upper, false)
+		}
+		if err != nil {
+			return err
+		}
+
+		position, err := rank.Between(lower, upper)
+		if errors.Is(err, rank.ErrOutOfOrder) {
+			return fmt.Errorf("%w: after_id must come before before_id", ErrInvalidMove)
+		}
+		if err != nil {
+			return err
+		}
+		return repo.SetPosition(ctx, todo, position)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// neighbourPosition returns the key of a todo the moved one is placed next
+// to, which must be a different todo in the same list.
+func (s *todoService) neighbourPosition(ctx context.Context, repo repository.TodoRepository, userID int, todo *models.Todo, neighbourID int) (string, error) {
+	if neighbourID == todo.ID {
+		return "", fmt.Errorf("%w: a todo can't be moved next to itself", ErrInvalidMove)
+	}
+	neighbour, err := repo.GetByID(ctx, userID, neighbourID)
+	if err != nil {
+		return "", err
+	}
+	if neighbour == nil || !samePositionScope(todo, neighbour) {
+		return "", fmt.Errorf("%w: todo %d is not in the same list", ErrInvalidMove, neighbourID)
+	}
+	return neighbour.Position, nil
+}
+
+// samePositionScope reports whether two todos are ordered against each other:
+// both in the same list, or both without a list and owned by the same user.
+func samePositionScope(a, b *models.Todo) bool {
+	if a.ListID == nil || b.ListID == nil {
+		return a.ListID == nil && b.ListID == nil && a.UserID == b.UserID
+	}
+	return *a.ListID == *b.ListID
+}
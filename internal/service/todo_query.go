@@ -0,0 +1,126 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+// ErrInvalidTodoQuery is returned for unsupported list parameters or a malformed cursor.
+var ErrInvalidTodoQuery = errors.New("invalid todo query")
+
+const (
+	defaultTodoPageSize = 20
+	maxTodoPageSize     = 100
+)
+
+// todoCursor is the opaque payload behind next_cursor. It pins the sort so
+// a cursor can't be replayed against a differently ordered list.
+type todoCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+func buildTodoFilter(query models.TodoListQuery) (models.TodoFilter, error) {
+	filter := models.TodoFilter{
+		ListID:     query.ListID,
+		AssigneeID: query.AssigneeID,
+		Completed:  query.Completed,
+		Status:     models.TodoStatus(query.Status),
+		Ready:      query.Ready,
+		Priority:   models.TodoPriority(query.Priority),
+		Tag:        query.Tag,
+		Query:      query.Query,
+		Sort:       query.Sort,
+		Desc:       true,
+		Limit:      query.Limit,
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.TodoSortCreatedAt
+	case models.TodoSortCreatedAt, models.TodoSortUpdatedAt, models.TodoSortTitle, models.TodoSortPosition:
+	default:
+		return filter, fmt.Errorf("%w: unsupported sort %q", ErrInvalidTodoQuery, query.Sort)
+	}
+
+	if filter.Priority != "" && !filter.Priority.Valid() {
+		return filter, fmt.Errorf("%w: unsupported priority %q", ErrInvalidTodoQuery, query.Priority)
+	}
+
+	switch query.Order {
+	case "":
+		// Manual order reads top to bottom; everything else newest first.
+		filter.Desc = filter.Sort != models.TodoSortPosition
+	case "desc":
+	case "asc":
+		filter.Desc = false
+	default:
+		return filter, fmt.Errorf("%w: order must be asc or desc", ErrInvalidTodoQuery)
+	}
+
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = defaultTodoPageSize
+	case filter.Limit < 0 || filter.Limit > maxTodoPageSize:
+		return filter, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidTodoQuery, maxTodoPageSize)
+	}
+
+	if query.Cursor != "" {
+		after, err := decodeTodoCursor(query.Cursor, filter)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = after
+	}
+
+	return filter, nil
+}
+
+func encodeTodoCursor(filter models.TodoFilter, last models.Todo) string {
+	cursor := todoCursor{Sort: filter.Sort, Desc: filter.Desc, ID: last.ID}
+	switch filter.Sort {
+	case models.TodoSortTitle:
+		cursor.Value = last.Title
+	case models.TodoSortPosition:
+		cursor.Value = last.Position
+	case models.TodoSortUpdatedAt:
+		cursor.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeTodoCursor(encoded string, filter models.TodoFilter) (*models.TodoCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidTodoQuery)
+	}
+
+	var cursor todoCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidTodoQuery)
+	}
+	if cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+		return nil, fmt.Errorf("%w: cursor does not match sort order", ErrInvalidTodoQuery)
+	}
+
+	if filter.Sort == models.TodoSortTitle || filter.Sort == models.TodoSortPosition {
+		return &models.TodoCursor{Value: cursor.Value, ID: cursor.ID}, nil
+	}
+
+	value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidTodoQuery)
+	}
+	return &models.TodoCursor{Value: value, ID: cursor.ID}, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/quickadd"
+)
+
+// ErrInvalidQuickAdd is returned when the text leaves no usable title.
+var ErrInvalidQuickAdd = errors.New("invalid quick-add text")
+
+// minTitleLength matches the validation of CreateTodoRequest.Title.
+const minTitleLength = 3
+
+// QuickAddResult is the parsed text and, unless it was a dry run, the todo
+// created from it.
+type QuickAddResult struct {
+	Parsed quickadd.Result `json:"parsed"`
+	Todo   *models.Todo    `json:"todo,omitempty"`
+}
+
+// RequestValidator checks a request against its validate tags, like the
+// validator the handlers use.
+type RequestValidator interface {
+	ValidateExcept(i any, fields ...string) error
+}
+
+// TodoQuickAddService turns a line of text into a todo. Tags named in the
+// text are matched by name, ignoring case, and created when missing.
+type TodoQuickAddService interface {
+	QuickAdd(ctx context.Context, userID int, req models.QuickAddTodoRequest) (*QuickAddResult, error)
+}
+
+type todoQuickAddService struct {
+	todos    TodoService
+	validate RequestValidator
+	now      func() time.Time
+}
+
+func NewTodoQuickAddService(todos TodoService, validate RequestValidator) TodoQuickAddService {
+	return &todoQuickAddService{todos: todos, validate: validate, now: time.Now}
+}
+
+func (s *todoQuickAddService) QuickAdd(ctx context.Context, userID int, req models.QuickAddTodoRequest) (*QuickAddResult, error) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidDueDate, timezone)
+	}
+
+	parsed := quickadd.Parse(req.Text, s.now().In(loc))
+	result := &QuickAddResult{Parsed: parsed}
+	if len([]rune(parsed.Title)) < minTitleLength {
+		return nil, fmt.Errorf("%w: the title must be at least %d characters once dates, tags and priority are taken out", ErrInvalidQuickAdd, minTitleLength)
+	}
+	if req.DryRun {
+		return result, nil
+	}
+
+	create := models.CreateTodoRequest{
+		Title:          parsed.Title,
+		Timezone:       timezone,
+		Priority:       parsed.Priority,
+		ListID:         req.ListID,
+		RecurrenceRule: parsed.Recurrence,
+	}
+	if parsed.Due != nil {
+		due := parsed.Due.Format(time.RFC3339)
+		if parsed.AllDay {
+			due = parsed.Due.Format(models.DueDateLayout)
+		}
+		create.DueAt = &due
+	}
+	create.TagNames = parsed.Tags
+	// The text has no room for a description, the one field POST /todos
+	// requires that quick-add leaves empty.
+	if err := s.validate.ValidateExcept(create, "Description"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuickAdd, err)
+	}
+
+	if result.Todo, err = s.todos.Create(ctx, userID, create); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
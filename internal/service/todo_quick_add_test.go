@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/validator"
+	"github.com/manish-npx/todo-go-echo/internal/workflow"
+)
+
+func TestTodoQuickAddServiceCreatesTodo(t *testing.T) {
+	ctx := context.Background()
+	userID := 1
+	tags := map[int]models.Tag{1: {ID: 1, UserID: userID, Name: "Finance"}}
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}, tags: tags}
+	svc := NewTodoQuickAddService(NewTodoService(repo, workflow.Default(), config.TodoConfig{}), validator.New()).(*todoQuickAddService)
+	svc.now = func() time.Time { return time.Date(2026, time.October, 16, 22, 0, 0, 0, time.UTC) }
+
+	// 22:00 UTC is already the 17th in Berlin, so "the 1st" is still November.
+	preview, err := svc.QuickAdd(ctx, userID, models.QuickAddTodoRequest{
+		Text:     "Pay rent every month on the 1st #finance #home !high",
+		Timezone: "Europe/Berlin",
+		DryRun:   true,
+	})
+	if err != nil {
+		t.Fatalf("QuickAdd() dry run error = %v", err)
+	}
+	if preview.Todo != nil || len(repo.todos) != 0 || len(tags) != 1 {
+		t.Fatalf("dry run wrote: todo = %+v, %d todos, %d tags", preview.Todo, len(repo.todos), len(tags))
+	}
+	if len(preview.Parsed.Tokens) != 5 {
+		t.Fatalf("dry run tokens = %+v, want 5", preview.Parsed.Tokens)
+	}
+
+	result, err := svc.QuickAdd(ctx, userID, models.QuickAddTodoRequest{
+		Text:     "Pay rent every month on the 1st #finance #home !high",
+		Timezone: "Europe/Berlin",
+	})
+	if err != nil {
+		t.Fatalf("QuickAdd() error = %v", err)
+	}
+	todo := result.Todo
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	wantDue := time.Date(2026, time.November, 1, 23, 59, 59, 0, berlin)
+	if todo.Title != "Pay rent" || todo.Priority != models.PriorityHigh || todo.DueAt == nil || !todo.DueAt.Equal(wantDue) {
+		t.Fatalf("QuickAdd() todo = %+v, due %v", todo, todo.DueAt)
+	}
+	if todo.RecurrenceRule == nil || *todo.RecurrenceRule != "FREQ=MONTHLY" {
+		t.Fatalf("QuickAdd() recurrence = %v, want FREQ=MONTHLY", todo.RecurrenceRule)
+	}
+	// #finance matches the existing tag; #home is created.
+	if len(todo.Tags) != 2 || todo.Tags[0].ID != 1 || todo.Tags[1].Name != "home" || len(tags) != 2 {
+		t.Fatalf("QuickAdd() tags = %+v, stored %+v", todo.Tags, tags)
+	}
+}
+
+func TestTodoQuickAddServiceRejectsMissingTitle(t *testing.T) {
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoQuickAddService(NewTodoService(repo, workflow.Default(), config.TodoConfig{}), validator.New())
+
+	_, err := svc.QuickAdd(context.Background(), 1, models.QuickAddTodoRequest{Text: "tomorrow at 9am #work"})
+	if !errors.Is(err, ErrInvalidQuickAdd) {
+		t.Fatalf("QuickAdd() error = %v, want ErrInvalidQuickAdd", err)
+	}
+	if len(repo.todos) != 0 {
+		t.Fatalf("QuickAdd() created %d todos", len(repo.todos))
+	}
+}
+
+func TestTodoQuickAddServiceKeepsNoTagsWhenCreateFails(t *testing.T) {
+	ctx := context.Background()
+	tags := map[int]models.Tag{}
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{},
+		tags:  tags,
+		lists: map[int]models.TodoList{1: {ID: 1, UserID: 1, Name: "Done with", Archived: true}},
+	}
+	svc := NewTodoQuickAddService(NewTodoService(repo, workflow.Default(), config.TodoConfig{}), validator.New())
+
+	listID := 1
+	_, err := svc.QuickAdd(ctx, 1, models.QuickAddTodoRequest{Text: "File taxes #finance", ListID: &listID})
+	if !errors.Is(err, ErrListArchived) {
+		t.Fatalf("QuickAdd() error = %v, want ErrListArchived", err)
+	}
+	if len(repo.todos) != 0 || len(tags) != 0 {
+		t.Fatalf("QuickAdd() left %d todos and tags %+v behind", len(repo.todos), tags)
+	}
+}
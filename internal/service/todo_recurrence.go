@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/recurrence"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// ErrInvalidRecurrence is returned for recurrence rules outside the supported RRULE subset.
+var ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+
+// ErrNotRecurring is returned for series operations on a one-off todo.
+var ErrNotRecurring = errors.New("todo is not part of a recurring series")
+
+// GetSeries lists every occurrence in the series the todo belongs to.
+func (s *todoService) GetSeries(ctx context.Context, userID, id int) ([]models.Todo, error) {
+	return s.seriesOf(ctx, s.repo, userID, id, false)
+}
+
+// UpdateSeries applies req to every open occurrence; completed ones are history.
+func (s *todoService) UpdateSeries(ctx context.Context, userID, id int, req models.UpdateTodoSeriesRequest) ([]models.Todo, error) {
+	var updated []models.Todo
+	err := s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		series, err := s.seriesOf(ctx, repo, userID, id, true)
+		if err != nil {
+			return err
+		}
+
+		for _, todo := range series {
+			if todo.Completed {
+				continue
+			}
+			before := todo
+			if req.Title != nil {
+				todo.Title = *req.Title
+			}
+			if req.Description != nil {
+				todo.Description = *req.Description
+			}
+			if req.Priority != nil {
+				todo.Priority = models.TodoPriority(*req.Priority)
+			}
+			if req.RecurrenceRule != nil {
+				if err := setRecurrenceRule(&todo, *req.RecurrenceRule); err != nil {
+					return err
+				}
+			}
+			if err := repo.Update(ctx, &todo); err != nil {
+				return err
+			}
+			if err := s.recordUpdate(ctx, repo, userID, &before, &todo); err != nil {
+				return err
+			}
+			updated = append(updated, todo)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// StopSeries clears the rule on every occurrence so completing any of them,
+// including re-completing an old one, no longer spawns a successor.
+func (s *todoService) StopSeries(ctx context.Context, userID, id int) error {
+	return s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		series, err := s.seriesOf(ctx, repo, userID, id, true)
+		if err != nil {
+			return err
+		}
+
+		for _, todo := range series {
+			if todo.RecurrenceRule == nil {
+				continue
+			}
+			before := todo
+			todo.RecurrenceRule = nil
+			if err := repo.Update(ctx, &todo); err != nil {
+				return err
+			}
+			if err := s.recordUpdate(ctx, repo, userID, &before, &todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// seriesOf loads the series containing todo id; with write set the caller
+// must also be allowed to change it.
+func (s *todoService) seriesOf(ctx context.Context, repo repository.TodoRepository, userID, id int, write bool) ([]models.Todo, error) {
+	todo, err := repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, sql.ErrNoRows
+	}
+	if write {
+		if err := s.authorize(ctx, repo, userID, todo); err != nil {
+			return nil, err
+		}
+	}
+	if todo.RecurrenceRule == nil && todo.SeriesID == nil {
+		return nil, ErrNotRecurring
+	}
+	return repo.GetSeries(ctx, userID, seriesRoot(todo))
+}
+
+// spawnNextOccurrence creates the occurrence that follows a just-completed
+// todo. It returns nil when the rule has run out or the successor already
+// exists (the todo was completed, reopened and completed again).
+func (s *todoService) spawnNextOccurrence(ctx context.Context, repo repository.TodoRepository, todo *models.Todo) (*models.Todo, error) {
+	rule, err := recurrence.Parse(*todo.RecurrenceRule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	rootID := seriesRoot(todo)
+	occurrence := max(todo.Occurrence, 1)
+	// A trashed successor still counts, or restoring it would duplicate it.
+	last, err := repo.LastOccurrence(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+	if last > occurrence {
+		return nil, nil
+	}
+
+	// The rule runs in the todo's zone: due dates come back from the
+	// database in UTC, which would shift weekdays near midnight and wall-clock
+	// times across DST.
+	loc, err := time.LoadLocation(todo.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	// Undated todos recur relative to when they were completed.
+	base := s.now().In(loc)
+	if todo.DueAt != nil {
+		base = todo.DueAt.In(loc)
+	}
+	nextDue, ok := rule.Next(base, occurrence)
+	if !ok {
+		return nil, nil
+	}
+
+	next := &models.Todo{
+		UserID:         todo.UserID,
+		Title:          todo.Title,
+		Description:    todo.Description,
+		Status:         models.StatusTodo,
+		Priority:       todo.Priority,
+		Tags:           todo.Tags,
+		ParentID:       todo.ParentID,
+		ListID:         todo.ListID,
+		DueAt:          &nextDue,
+		RecurrenceRule: todo.RecurrenceRule,
+		Timezone:       todo.Timezone,
+		SeriesID:       &rootID,
+		Occurrence:     occurrence + 1,
+	}
+	// The reminder keeps its distance to the due date.
+	if todo.RemindAt != nil && todo.DueAt != nil {
+		remindAt := nextDue.Add(todo.RemindAt.Sub(*todo.DueAt))
+		next.RemindAt = &remindAt
+	}
+	if err := repo.Create(ctx, next); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// setRecurrenceRule stores value in canonical form; "" stops recurrence.
+func setRecurrenceRule(todo *models.Todo, value string) error {
+	if value == "" {
+		todo.RecurrenceRule = nil
+		return nil
+	}
+
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	canonical := rule.String()
+	todo.RecurrenceRule = &canonical
+	if todo.Occurrence == 0 {
+		todo.Occurrence = 1
+	}
+	return nil
+}
+
+func seriesRoot(todo *models.Todo) int {
+	if todo.SeriesID != nil {
+		return *todo.SeriesID
+	}
+	return todo.ID
+}
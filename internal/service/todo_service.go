@@ -1,51 +1,319 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
 	"github.com/manish-npx/todo-go-echo/internal/models"
 	"github.com/manish-npx/todo-go-echo/internal/repository"
+	"github.com/manish-npx/todo-go-echo/internal/workflow"
 )
 
+// TodoService works on behalf of the authenticated user; todos owned by
+// anyone else are reported as sql.ErrNoRows so their IDs don't leak.
 type TodoService interface {
-	GetAll() ([]models.Todo, error)
-	GetByID(id int) (*models.Todo, error)
-	Create(req models.CreateTodoRequest) (*models.Todo, error)
-	Update(id int, req models.UpdateTodoRequest) (*models.Todo, error)
-	Delete(id int) error
+	List(ctx context.Context, userID int, query models.TodoListQuery) (*models.TodoPage, error)
+	GetByID(ctx context.Context, userID, id int) (*models.Todo, error)
+	Create(ctx context.Context, userID int, req models.CreateTodoRequest) (*models.Todo, error)
+	Update(ctx context.Context, userID, id int, req models.UpdateTodoRequest) (*models.Todo, error)
+	Delete(ctx context.Context, userID, id int, mode models.SubtaskDeleteMode) error
+	Trash(ctx context.Context, userID int) ([]models.Todo, error)
+	Restore(ctx context.Context, userID, id int) (*models.Todo, error)
+	Overdue(ctx context.Context, userID int, loc *time.Location) ([]models.Todo, error)
+	DueToday(ctx context.Context, userID int, loc *time.Location) ([]models.Todo, error)
+	DueWithin(ctx context.Context, userID, days int, loc *time.Location) ([]models.Todo, error)
+	Stats(ctx context.Context, userID int, query models.TodoStatsQuery) (*models.TodoStats, error)
+	Board(ctx context.Context, userID int, query models.TodoBoardQuery) (*models.TodoBoard, error)
+	GetSeries(ctx context.Context, userID, id int) ([]models.Todo, error)
+	UpdateSeries(ctx context.Context, userID, id int, req models.UpdateTodoSeriesRequest) ([]models.Todo, error)
+	StopSeries(ctx context.Context, userID, id int) error
+	Bulk(ctx context.Context, userID int, req models.BulkTodoRequest) (*models.BulkTodoResult, error)
+	Move(ctx context.Context, userID, id int, req models.MoveTodoRequest) (*models.Todo, error)
+	Assign(ctx context.Context, userID, id int, req models.AssignTodoRequest) (*models.Todo, error)
+	Unassign(ctx context.Context, userID, id int) (*models.Todo, error)
+	History(ctx context.Context, userID, id int) ([]models.TodoEvent, error)
+	Export(ctx context.Context, userID int, fn func(todos []models.Todo) error) error
+	Import(ctx context.Context, userID int, rows []models.TodoImportRow, dryRun bool) (*models.TodoImportResult, error)
+
+	// The unexported methods let other services in this package apply the
+	// same rules inside their own transactions. They also keep NewTodoService
+	// the only implementation.
+	authorize(ctx context.Context, repo repository.TodoRepository, userID int, todo *models.Todo) error
+	prepare(ctx context.Context, repo repository.TodoRepository, userID int, req models.CreateTodoRequest) (*models.Todo, error)
+	create(ctx context.Context, repo repository.TodoRepository, todo *models.Todo) error
+	update(ctx context.Context, repo repository.TodoRepository, userID, id int, req models.UpdateTodoRequest) (*models.Todo, error)
+	delete(ctx context.Context, repo repository.TodoRepository, userID, id int, mode models.SubtaskDeleteMode) error
+	recordUpdate(ctx context.Context, repo repository.TodoRepository, userID int, before, after *models.Todo) error
+}
+
+// ErrInvalidDueDate is returned when due_at or its timezone can't be parsed.
+var ErrInvalidDueDate = errors.New("invalid due date")
+
+// ErrInvalidTag is returned when a tag ID doesn't belong to the user.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// ErrInvalidParent is returned when parent_id isn't one of the user's todos.
+var ErrInvalidParent = errors.New("invalid parent todo")
+
+// ErrInvalidList is returned when list_id isn't one of the user's lists.
+var ErrInvalidList = errors.New("invalid list")
+
+// ErrListArchived is returned when adding todos to an archived list.
+var ErrListArchived = errors.New("list is archived")
+
+// ErrHasSubtasks is returned when deleting a parent without saying what to do with its children.
+var ErrHasSubtasks = errors.New("todo has subtasks; choose cascade or promote")
+
+// ErrInvalidStatus is returned for a status the workflow doesn't have.
+var ErrInvalidStatus = errors.New("invalid status")
+
+// ErrInvalidTransition is returned when the workflow doesn't allow moving a
+// todo from its current status to the requested one.
+var ErrInvalidTransition = errors.New("status transition not allowed")
+
+// ErrBlocked is returned when completing a todo while one of its blockers is still open.
+var ErrBlocked = errors.New("todo is blocked by open todos")
+
+// MaxDueWithinDays bounds the upcoming view.
+const MaxDueWithinDays = 365
+
+// IsInvalidTodoInput reports errors caused by bad client input, as opposed to
+// permission, conflict or storage failures.
+func IsInvalidTodoInput(err error) bool {
+	return errors.Is(err, ErrInvalidTodoQuery) ||
+		errors.Is(err, ErrInvalidDueDate) ||
+		errors.Is(err, ErrInvalidTag) ||
+		errors.Is(err, ErrInvalidParent) ||
+		errors.Is(err, ErrInvalidList) ||
+		errors.Is(err, ErrInvalidStatus) ||
+		errors.Is(err, ErrInvalidRecurrence) ||
+		errors.Is(err, ErrNotRecurring) ||
+		errors.Is(err, ErrInvalidBulk) ||
+		errors.Is(err, ErrInvalidMove) ||
+		errors.Is(err, ErrInvalidAssignee)
 }
 
 type todoService struct {
 	repo repository.TodoRepository
+	flow *workflow.Workflow
+	cfg  config.TodoConfig
+	now  func() time.Time
 }
 
-func NewTodoService(repo repository.TodoRepository) TodoService {
-	return &todoService{repo: repo}
+func NewTodoService(repo repository.TodoRepository, flow *workflow.Workflow, cfg config.TodoConfig) TodoService {
+	return &todoService{repo: repo, flow: flow, cfg: cfg, now: time.Now}
 }
 
-func (s *todoService) GetAll() ([]models.Todo, error) {
-	return s.repo.GetAll()
+func (s *todoService) List(ctx context.Context, userID int, query models.TodoListQuery) (*models.TodoPage, error) {
+	filter, err := buildTodoFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Status != "" && !s.flow.Valid(filter.Status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidTodoQuery, query.Status)
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	todos, err := s.repo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.repo.Count(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TodoPage{Todos: todos, TotalEstimate: total}
+	if len(todos) > pageSize {
+		page.Todos = todos[:pageSize]
+		page.HasMore = true
+		page.NextCursor = encodeTodoCursor(filter, page.Todos[pageSize-1])
+	}
+	return page, nil
+}
+
+// GetByID returns the todo with its whole subtask tree and per-node progress.
+func (s *todoService) GetByID(ctx context.Context, userID, id int) (*models.Todo, error) {
+	todo, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil || todo == nil {
+		return todo, err
+	}
+
+	descendants, err := s.repo.GetSubtree(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	byParent := make(map[int][]models.Todo)
+	for _, child := range descendants {
+		if child.ParentID != nil {
+			byParent[*child.ParentID] = append(byParent[*child.ParentID], child)
+		}
+	}
+	attachChildren(todo, byParent)
+
+	return todo, nil
 }
 
-func (s *todoService) GetByID(id int) (*models.Todo, error) {
-	return s.repo.GetByID(id)
+func attachChildren(todo *models.Todo, byParent map[int][]models.Todo) {
+	children := byParent[todo.ID]
+	if len(children) == 0 {
+		return
+	}
+
+	progress := &models.TodoProgress{Total: len(children)}
+	for i := range children {
+		attachChildren(&children[i], byParent)
+		if children[i].Completed {
+			progress.Done++
+		}
+	}
+	todo.Children = children
+	todo.Progress = progress
 }
 
-func (s *todoService) Create(req models.CreateTodoRequest) (*models.Todo, error) {
+func (s *todoService) Create(ctx context.Context, userID int, req models.CreateTodoRequest) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		var err error
+		if todo, err = s.prepare(ctx, repo, userID, req); err != nil {
+			return err
+		}
+		return s.create(ctx, repo, todo)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
 
+// prepare checks req against the user's tags, lists and parents and builds
+// the todo it describes without writing anything.
+func (s *todoService) prepare(ctx context.Context, repo repository.TodoRepository, userID int, req models.CreateTodoRequest) (*models.Todo, error) {
 	todo := &models.Todo{
+		UserID:      userID,
 		Title:       req.Title,
 		Description: req.Description,
+		Priority:    models.PriorityNone,
+	}
+	todo.SetStatus(models.StatusTodo)
+	if req.Status != "" {
+		status := models.TodoStatus(req.Status)
+		if !s.flow.Valid(status) {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidStatus, req.Status)
+		}
+		todo.SetStatus(status)
+	}
+	if req.Priority != "" {
+		todo.Priority = models.TodoPriority(req.Priority)
+	}
+	if len(req.TagIDs) > 0 {
+		tags, err := s.resolveTags(ctx, repo, userID, req.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+		todo.Tags = tags
+	}
+	if len(req.TagNames) > 0 {
+		// Created in the todo's transaction, so a failed create leaves none behind.
+		tags, err := repo.EnsureTags(ctx, userID, req.TagNames)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			if !slices.ContainsFunc(todo.Tags, func(t models.Tag) bool { return t.ID == tag.ID }) {
+				todo.Tags = append(todo.Tags, tag)
+			}
+		}
+	}
+	if req.ParentID != nil {
+		parent, err := repo.GetByID(ctx, userID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("%w: todo %d does not exist", ErrInvalidParent, *req.ParentID)
+		}
+		if err := s.authorize(ctx, repo, userID, parent); err != nil {
+			return nil, err
+		}
+		todo.ParentID = &parent.ID
+		todo.ListID = parent.ListID
+	}
+	if req.ListID != nil {
+		listID, err := s.resolveList(ctx, repo, userID, *req.ListID)
+		if err != nil {
+			return nil, err
+		}
+		todo.ListID = listID
+	}
+	todo.Timezone = req.Timezone
+	if req.DueAt != nil && *req.DueAt != "" {
+		dueAt, err := parseDueAt(*req.DueAt, req.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		todo.DueAt = &dueAt
+	}
+	if req.RemindAt != nil && *req.RemindAt != "" {
+		remindAt, err := parseDueAt(*req.RemindAt, req.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		todo.RemindAt = &remindAt
+	}
+	if req.RecurrenceRule != "" {
+		if err := setRecurrenceRule(todo, req.RecurrenceRule); err != nil {
+			return nil, err
+		}
 	}
 
-	err := s.repo.Create(todo)
-	return todo, err
+	return todo, nil
 }
 
-func (s *todoService) Update(id int, req models.UpdateTodoRequest) (*models.Todo, error) {
+// create stores a prepared todo together with its history entry.
+func (s *todoService) create(ctx context.Context, repo repository.TodoRepository, todo *models.Todo) error {
+	if err := repo.Create(ctx, todo); err != nil {
+		return err
+	}
+	return s.recordCreate(ctx, repo, todo.UserID, todo)
+}
 
-	todo, err := s.repo.GetByID(id)
-	if err != nil || todo == nil {
+func (s *todoService) Update(ctx context.Context, userID, id int, req models.UpdateTodoRequest) (*models.Todo, error) {
+	var todo *models.Todo
+	err := s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		var err error
+		todo, err = s.update(ctx, repo, userID, id, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// update applies req through repo, which is bound to the caller's transaction
+// so follow-up writes (parent roll-up, next occurrence) commit with the change.
+func (s *todoService) update(ctx context.Context, repo repository.TodoRepository, userID, id int, req models.UpdateTodoRequest) (*models.Todo, error) {
+	todo, err := repo.GetByID(ctx, userID, id)
+	if err != nil {
 		return nil, err
 	}
+	if todo == nil {
+		return nil, sql.ErrNoRows
+	}
+	if err := s.authorize(ctx, repo, userID, todo); err != nil {
+		return nil, err
+	}
+	before := *todo
 
 	if req.Title != nil {
 		todo.Title = *req.Title
@@ -53,14 +321,399 @@ func (s *todoService) Update(id int, req models.UpdateTodoRequest) (*models.Todo
 	if req.Description != nil {
 		todo.Description = *req.Description
 	}
+	if req.Completed != nil || req.Status != nil {
+		status, err := s.nextStatus(todo.Status, req)
+		if err != nil {
+			return nil, err
+		}
+		todo.SetStatus(status)
+	}
+	if req.Timezone != "" {
+		todo.Timezone = req.Timezone
+	}
+	if req.DueAt != nil {
+		todo.DueAt = nil
+		if *req.DueAt != "" {
+			dueAt, err := parseDueAt(*req.DueAt, req.Timezone)
+			if err != nil {
+				return nil, err
+			}
+			todo.DueAt = &dueAt
+		}
+	}
+	if req.RemindAt != nil {
+		todo.RemindAt = nil
+		if *req.RemindAt != "" {
+			remindAt, err := parseDueAt(*req.RemindAt, req.Timezone)
+			if err != nil {
+				return nil, err
+			}
+			todo.RemindAt = &remindAt
+		}
+		// A new reminder time is a new reminder.
+		if !sameTime(before.RemindAt, todo.RemindAt) {
+			todo.RemindedAt = nil
+		}
+	}
+	if req.Priority != nil {
+		todo.Priority = models.TodoPriority(*req.Priority)
+	}
+	if req.RecurrenceRule != nil {
+		if err := setRecurrenceRule(todo, *req.RecurrenceRule); err != nil {
+			return nil, err
+		}
+	}
+
+	if todo.Completed && !before.Completed {
+		open, err := repo.OpenBlockers(ctx, todo.ID)
+		if err != nil {
+			return nil, err
+		}
+		if open > 0 {
+			return nil, fmt.Errorf("%w: %d still open", ErrBlocked, open)
+		}
+	}
+
+	if req.ListID != nil && !sameList(todo.ListID, *req.ListID) {
+		if todo.ListID, err = s.resolveList(ctx, repo, userID, *req.ListID); err != nil {
+			return nil, err
+		}
+		// Its old key means nothing in the new list; it goes to the end.
+		if todo.Position, err = repo.NextPosition(ctx, todo); err != nil {
+			return nil, err
+		}
+		// An assignee without access to the new list can't keep it.
+		if todo.AssigneeID != nil {
+			err := s.checkAssignee(ctx, repo, todo, *todo.AssigneeID)
+			if errors.Is(err, ErrInvalidAssignee) {
+				todo.AssigneeID = nil
+			} else if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var tags []models.Tag
+	if req.TagIDs != nil {
+		// Tags are per-user, so a shared todo keeps using its owner's tags.
+		if tags, err = s.resolveTags(ctx, repo, todo.UserID, *req.TagIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if req.TagIDs != nil {
+		if err := repo.SetTags(ctx, todo, tags); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.recordUpdate(ctx, repo, userID, &before, todo); err != nil {
+		return nil, err
+	}
+
+	if todo.Completed && !before.Completed && todo.RecurrenceRule != nil {
+		if todo.NextOccurrence, err = s.spawnNextOccurrence(ctx, repo, todo); err != nil {
+			return nil, err
+		}
+		if todo.NextOccurrence != nil {
+			if err := s.recordCreate(ctx, repo, userID, todo.NextOccurrence); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if todo.Completed && todo.ParentID != nil && s.cfg.AutoCompleteParent {
+		if err := s.completeParentIfDone(ctx, repo, userID, *todo.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	return todo, nil
+}
+
+// nextStatus resolves where req moves a todo now in current. completed is
+// kept for older clients: true means done, false reopens a done todo.
+func (s *todoService) nextStatus(current models.TodoStatus, req models.UpdateTodoRequest) (models.TodoStatus, error) {
+	next := current
 	if req.Completed != nil {
-		todo.Completed = *req.Completed
+		switch {
+		case *req.Completed:
+			next = models.StatusDone
+		case current == models.StatusDone:
+			next = models.StatusTodo
+		}
+	}
+	if req.Status != nil {
+		status := models.TodoStatus(*req.Status)
+		if !s.flow.Valid(status) {
+			return "", fmt.Errorf("%w: unknown status %q", ErrInvalidStatus, *req.Status)
+		}
+		if req.Completed != nil && *req.Completed != (status == models.StatusDone) {
+			return "", fmt.Errorf("%w: status %q contradicts completed", ErrInvalidStatus, status)
+		}
+		next = status
+	}
+	if !s.flow.CanMove(current, next) {
+		return "", fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current, next)
+	}
+	return next, nil
+}
+
+// resolveList checks that listID is an open list userID may add todos to; 0 means no list.
+func (s *todoService) resolveList(ctx context.Context, repo repository.TodoRepository, userID, listID int) (*int, error) {
+	if listID == 0 {
+		return nil, nil
+	}
+	list, err := repo.FindList(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		return nil, fmt.Errorf("%w: list %d does not exist", ErrInvalidList, listID)
+	}
+	if !list.Role.CanEdit() {
+		return nil, ErrForbidden
+	}
+	if list.Archived {
+		return nil, ErrListArchived
+	}
+	return &list.ID, nil
+}
+
+// authorize allows changes to a todo by its owner and by the owner or editors
+// of the list it is in. Callers have already loaded todo as userID, so it is visible.
+func (s *todoService) authorize(ctx context.Context, repo repository.TodoRepository, userID int, todo *models.Todo) error {
+	if todo.UserID == userID {
+		return nil
+	}
+	if todo.ListID == nil {
+		return ErrForbidden
+	}
+	list, err := repo.FindList(ctx, userID, *todo.ListID)
+	if err != nil {
+		return err
+	}
+	if list == nil || !list.Role.CanEdit() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func sameList(current *int, listID int) bool {
+	if current == nil {
+		return listID == 0
+	}
+	return *current == listID
+}
+
+// completeParentIfDone marks a parent done once every direct subtask is done,
+// walking up the tree so grandparents roll up too. A parent the workflow
+// can't move to done, such as a blocked one, or with open blockers of its
+// own is left alone.
+func (s *todoService) completeParentIfDone(ctx context.Context, repo repository.TodoRepository, userID, parentID int) error {
+	progress, err := repo.ChildProgress(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if progress.Total == 0 || progress.Done < progress.Total {
+		return nil
+	}
+
+	parent, err := repo.GetByID(ctx, userID, parentID)
+	if err != nil || parent == nil || parent.Completed || !s.flow.CanMove(parent.Status, models.StatusDone) {
+		return err
+	}
+
+	open, err := repo.OpenBlockers(ctx, parent.ID)
+	if err != nil || open > 0 {
+		return err
+	}
+
+	before := *parent
+	parent.SetStatus(models.StatusDone)
+	if err := repo.Update(ctx, parent); err != nil {
+		return err
+	}
+	if err := s.recordUpdate(ctx, repo, userID, &before, parent); err != nil {
+		return err
+	}
+	if parent.ParentID != nil {
+		return s.completeParentIfDone(ctx, repo, userID, *parent.ParentID)
+	}
+	return nil
+}
+
+// Delete refuses to drop a parent unless mode says whether its subtasks are
+// deleted with it or promoted, so children are never orphaned silently. A
+// cascade also needs edit rights on every subtask.
+func (s *todoService) Delete(ctx context.Context, userID, id int, mode models.SubtaskDeleteMode) error {
+	return s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		return s.delete(ctx, repo, userID, id, mode)
+	})
+}
+
+func (s *todoService) delete(ctx context.Context, repo repository.TodoRepository, userID, id int, mode models.SubtaskDeleteMode) error {
+	switch mode {
+	case "", models.SubtaskDeleteCascade, models.SubtaskDeletePromote:
+	default:
+		return fmt.Errorf("%w: unsupported subtasks mode %q", ErrInvalidTodoQuery, mode)
+	}
+	if mode == models.SubtaskDeleteCascade {
+		// Keeps the checks below valid until the delete commits.
+		if err := repo.LockSubtree(ctx, userID, id); err != nil {
+			return err
+		}
+	}
+
+	todo, err := repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if todo == nil {
+		return sql.ErrNoRows
+	}
+	if err := s.authorize(ctx, repo, userID, todo); err != nil {
+		return err
+	}
+
+	if mode == "" {
+		progress, err := repo.ChildProgress(ctx, id)
+		if err != nil {
+			return err
+		}
+		if progress.Total > 0 {
+			return ErrHasSubtasks
+		}
 	}
 
-	err = s.repo.Update(todo)
-	return todo, err
+	var descendants []models.Todo
+	if mode != "" {
+		if descendants, err = repo.GetSubtree(ctx, userID, id); err != nil {
+			return err
+		}
+	}
+	if mode == models.SubtaskDeleteCascade {
+		if err := s.authorizeSubtree(ctx, repo, userID, id, descendants); err != nil {
+			return err
+		}
+	}
+	if err := repo.Delete(ctx, userID, id, mode); err != nil {
+		return err
+	}
+
+	if err := s.record(ctx, repo, userID, todo, models.TodoEventDeleted, nil); err != nil {
+		return err
+	}
+	for _, child := range descendants {
+		switch {
+		case mode == models.SubtaskDeleteCascade:
+			err = s.record(ctx, repo, userID, &child, models.TodoEventDeleted, nil)
+		case *child.ParentID == id:
+			// Promoted children moved up to the deleted todo's parent.
+			promoted := child
+			promoted.ParentID = todo.ParentID
+			err = s.recordUpdate(ctx, repo, userID, &child, &promoted)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (s *todoService) Delete(id int) error {
-	return s.repo.Delete(id)
+// authorizeSubtree checks that userID may edit every subtask a cascade would
+// delete. A subtask they can't see is one they can't edit either.
+func (s *todoService) authorizeSubtree(ctx context.Context, repo repository.TodoRepository, userID, id int, descendants []models.Todo) error {
+	total, err := repo.CountSubtree(ctx, id)
+	if err != nil {
+		return err
+	}
+	if total > int64(len(descendants)) {
+		return ErrForbidden
+	}
+	for i := range descendants {
+		if err := s.authorize(ctx, repo, userID, &descendants[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Trash lists the user's deleted todos that haven't been purged yet.
+func (s *todoService) Trash(ctx context.Context, userID int) ([]models.Todo, error) {
+	return s.repo.ListTrash(ctx, userID)
+}
+
+// Restore takes a todo out of the trash; the same roles that may delete it may restore it.
+func (s *todoService) Restore(ctx context.Context, userID, id int) (*models.Todo, error) {
+	todo, err := s.repo.GetDeleted(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, sql.ErrNoRows
+	}
+	if err := s.authorize(ctx, s.repo, userID, todo); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		if err := repo.Restore(ctx, todo); err != nil {
+			return err
+		}
+		return s.record(ctx, repo, userID, todo, models.TodoEventRestored, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// Overdue lists open todos whose due date has already passed.
+func (s *todoService) Overdue(ctx context.Context, userID int, loc *time.Location) ([]models.Todo, error) {
+	now := s.now().In(loc)
+	return s.repo.ListDue(ctx, userID, nil, &now)
+}
+
+// DueToday lists open todos due at any point of the current day in loc.
+func (s *todoService) DueToday(ctx context.Context, userID int, loc *time.Location) ([]models.Todo, error) {
+	return s.DueWithin(ctx, userID, 0, loc)
+}
+
+// DueWithin lists open todos due from the start of today through the end of
+// the day that is `days` days away, with day boundaries taken in loc.
+func (s *todoService) DueWithin(ctx context.Context, userID, days int, loc *time.Location) ([]models.Todo, error) {
+	if days < 0 || days > MaxDueWithinDays {
+		return nil, fmt.Errorf("%w: days must be between 0 and %d", ErrInvalidTodoQuery, MaxDueWithinDays)
+	}
+
+	now := s.now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, days+1)
+	return s.repo.ListDue(ctx, userID, &from, &to)
+}
+
+// resolveTags loads the user's tags for ids and fails if any of them is unknown.
+func (s *todoService) resolveTags(ctx context.Context, repo repository.TodoRepository, userID int, ids []int) ([]models.Tag, error) {
+	unique := slices.Compact(slices.Sorted(slices.Values(ids)))
+	tags, err := repo.FindTags(ctx, userID, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(unique) {
+		return nil, fmt.Errorf("%w: one or more tag IDs do not exist", ErrInvalidTag)
+	}
+	return tags, nil
+}
+
+func parseDueAt(value, timezone string) (time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: unknown timezone %q", ErrInvalidDueDate, timezone)
+	}
+	dueAt, err := models.ParseDueDate(value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q is neither RFC 3339 nor YYYY-MM-DD", ErrInvalidDueDate, value)
+	}
+	return dueAt, nil
 }
@@ -1,65 +1,618 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/manish-npx/todo-go-echo/internal/config"
 	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/rank"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+	"github.com/manish-npx/todo-go-echo/internal/workflow"
+	"gorm.io/gorm"
 )
 
 type todoRepoMock struct {
 	todos map[int]*models.Todo
+	tags  map[int]models.Tag
+	lists map[int]models.TodoList
+	// members maps list ID to user ID to role for shared lists.
+	members map[int]map[int]models.ListRole
+	// trash holds soft-deleted todos by ID.
+	trash  map[int]*models.Todo
+	events []models.TodoEvent
+	// stats records the filters passed to Stats.
+	stats []models.TodoStatsFilter
+	// blockers maps a todo ID to the IDs of the todos blocking it.
+	blockers map[int][]int
 }
 
-func (m *todoRepoMock) GetAll() ([]models.Todo, error) {
+// role mirrors the repository's visibility rules: owners see their todos,
+// list owners and members see todos in the list.
+func (m *todoRepoMock) role(userID int, todo *models.Todo) models.ListRole {
+	if todo.UserID == userID {
+		return models.ListRoleOwner
+	}
+	if todo.ListID != nil {
+		if list, err := m.FindList(context.Background(), userID, *todo.ListID); err == nil && list != nil {
+			return list.Role
+		}
+	}
+	if assignedTo(todo, userID) {
+		return models.ListRoleViewer
+	}
+	return ""
+}
+
+func assignedTo(todo *models.Todo, userID int) bool {
+	return todo.AssigneeID != nil && *todo.AssigneeID == userID
+}
+
+func matchesAssignee(todo *models.Todo, filter models.TodoFilter) bool {
+	return filter.AssigneeID == nil || assignedTo(todo, *filter.AssigneeID)
+}
+
+func (m *todoRepoMock) List(ctx context.Context, userID int, filter models.TodoFilter) ([]models.Todo, error) {
 	result := make([]models.Todo, 0, len(m.todos))
-	for _, todo := range m.todos {
+	for id := 1; id <= len(m.todos); id++ {
+		todo, ok := m.todos[id]
+		if !ok || (todo.UserID != userID && !assignedTo(todo, userID)) || !matchesAssignee(todo, filter) {
+			continue
+		}
+		if filter.After != nil && id <= filter.After.ID {
+			continue
+		}
+		if filter.Status != "" && todo.Status != filter.Status {
+			continue
+		}
+		if filter.Ready && (todo.Completed || m.openBlockers(id) > 0) {
+			continue
+		}
 		result = append(result, *todo)
+		if len(result) == filter.Limit {
+			break
+		}
 	}
 	return result, nil
 }
 
-func (m *todoRepoMock) GetByID(id int) (*models.Todo, error) {
+func (m *todoRepoMock) Count(ctx context.Context, userID int, filter models.TodoFilter) (int64, error) {
+	var total int64
+	for _, todo := range m.todos {
+		if (todo.UserID == userID || assignedTo(todo, userID)) && matchesAssignee(todo, filter) &&
+			(filter.Status == "" || todo.Status == filter.Status) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (m *todoRepoMock) Stats(ctx context.Context, userID int, filter models.TodoStatsFilter) (*models.TodoStats, error) {
+	m.stats = append(m.stats, filter)
+	return &models.TodoStats{Completed: []models.TodoStatsBucket{}}, nil
+}
+
+func (m *todoRepoMock) GetByID(ctx context.Context, userID, id int) (*models.Todo, error) {
+	// Like the driver, refuse to run for a context that has ended.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	todo, ok := m.todos[id]
-	if !ok {
+	if !ok || m.role(userID, todo) == "" {
 		return nil, nil
 	}
 	return todo, nil
 }
 
-func (m *todoRepoMock) Create(todo *models.Todo) error {
-	nextID := len(m.todos) + 1
+func (m *todoRepoMock) ListDue(ctx context.Context, userID int, from, to *time.Time) ([]models.Todo, error) {
+	var result []models.Todo
+	for _, todo := range m.todos {
+		if todo.UserID != userID || todo.Completed || todo.DueAt == nil {
+			continue
+		}
+		if (from != nil && todo.DueAt.Before(*from)) || (to != nil && !todo.DueAt.Before(*to)) {
+			continue
+		}
+		result = append(result, *todo)
+	}
+	return result, nil
+}
+
+func (m *todoRepoMock) Create(ctx context.Context, todo *models.Todo) error {
+	nextID := len(m.todos) + len(m.trash) + 1
 	todo.ID = nextID
+	if todo.Position == "" {
+		position, err := m.NextPosition(ctx, todo)
+		if err != nil {
+			return err
+		}
+		todo.Position = position
+	}
 	m.todos[nextID] = todo
 	return nil
 }
 
-func (m *todoRepoMock) Update(todo *models.Todo) error {
-	if _, ok := m.todos[todo.ID]; !ok {
+func (m *todoRepoMock) Update(ctx context.Context, todo *models.Todo) error {
+	existing, ok := m.todos[todo.ID]
+	if !ok || existing.UserID != todo.UserID {
+		return sql.ErrNoRows
+	}
+	m.todos[todo.ID] = todo
+	return nil
+}
+
+func (m *todoRepoMock) Delete(ctx context.Context, userID, id int, mode models.SubtaskDeleteMode) error {
+	todo, ok := m.todos[id]
+	if !ok || m.role(userID, todo) == "" {
 		return sql.ErrNoRows
 	}
+	if m.trash == nil {
+		m.trash = map[int]*models.Todo{}
+	}
+	// One delete stamps the whole cascade with the same time, like the repository.
+	m.trashTree(todo, mode, gorm.DeletedAt{Time: time.Now(), Valid: true})
+	return nil
+}
+
+func (m *todoRepoMock) trashTree(todo *models.Todo, mode models.SubtaskDeleteMode, deletedAt gorm.DeletedAt) {
+	for _, child := range m.todos {
+		if child.ParentID == nil || *child.ParentID != todo.ID {
+			continue
+		}
+		switch mode {
+		case models.SubtaskDeleteCascade:
+			m.trashTree(child, mode, deletedAt)
+		case models.SubtaskDeletePromote:
+			child.ParentID = todo.ParentID
+		}
+	}
+	todo.DeletedAt = deletedAt
+	m.trash[todo.ID] = todo
+	delete(m.todos, todo.ID)
+}
+
+func (m *todoRepoMock) ListTrash(ctx context.Context, userID int) ([]models.Todo, error) {
+	var result []models.Todo
+	for _, todo := range m.trash {
+		if m.role(userID, todo) != "" {
+			result = append(result, *todo)
+		}
+	}
+	return result, nil
+}
+
+func (m *todoRepoMock) GetDeleted(ctx context.Context, userID, id int) (*models.Todo, error) {
+	todo, ok := m.trash[id]
+	if !ok || m.role(userID, todo) == "" {
+		return nil, nil
+	}
+	return todo, nil
+}
+
+func (m *todoRepoMock) Restore(ctx context.Context, todo *models.Todo) error {
+	deletedAt := todo.DeletedAt
+	for id, trashed := range m.trash {
+		if id == todo.ID || trashed.DeletedAt == deletedAt {
+			trashed.DeletedAt = gorm.DeletedAt{}
+			m.todos[id] = trashed
+			delete(m.trash, id)
+		}
+	}
+	return nil
+}
+
+func (m *todoRepoMock) PurgeDeleted(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var purged int64
+	for id, todo := range m.trash {
+		if todo.DeletedAt.Time.Before(before) && purged < int64(limit) {
+			delete(m.trash, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *todoRepoMock) GetSubtree(ctx context.Context, userID, id int) ([]models.Todo, error) {
+	var result []models.Todo
+	for childID := 1; childID <= len(m.todos); childID++ {
+		child, ok := m.todos[childID]
+		if !ok || child.ParentID == nil || *child.ParentID != id {
+			continue
+		}
+		if m.role(userID, child) != "" {
+			result = append(result, *child)
+		}
+		grandchildren, _ := m.GetSubtree(ctx, userID, child.ID)
+		result = append(result, grandchildren...)
+	}
+	return result, nil
+}
+
+func (m *todoRepoMock) CountSubtree(ctx context.Context, id int) (int64, error) {
+	var count int64
+	for _, child := range m.todos {
+		if child.ParentID != nil && *child.ParentID == id {
+			grandchildren, _ := m.CountSubtree(ctx, child.ID)
+			count += 1 + grandchildren
+		}
+	}
+	return count, nil
+}
+
+func (m *todoRepoMock) ChildProgress(ctx context.Context, parentID int) (models.TodoProgress, error) {
+	var progress models.TodoProgress
+	for _, child := range m.todos {
+		if child.ParentID != nil && *child.ParentID == parentID {
+			progress.Total++
+			if child.Completed {
+				progress.Done++
+			}
+		}
+	}
+	return progress, nil
+}
+
+func (m *todoRepoMock) FindTags(ctx context.Context, userID int, ids []int) ([]models.Tag, error) {
+	var tags []models.Tag
+	for _, id := range ids {
+		if tag, ok := m.tags[id]; ok && tag.UserID == userID {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func (m *todoRepoMock) SetTags(ctx context.Context, todo *models.Todo, tags []models.Tag) error {
+	todo.Tags = tags
+	return nil
+}
+
+func (m *todoRepoMock) EnsureTags(ctx context.Context, userID int, names []string) ([]models.Tag, error) {
+	if m.tags == nil {
+		m.tags = map[int]models.Tag{}
+	}
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, tag := range m.tags {
+			if tag.UserID == userID && strings.EqualFold(tag.Name, name) {
+				tags = append(tags, tag)
+				found = true
+				break
+			}
+		}
+		if !found {
+			tag := models.Tag{ID: len(m.tags) + 1, UserID: userID, Name: name}
+			m.tags[tag.ID] = tag
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func (m *todoRepoMock) GetSeries(ctx context.Context, userID, rootID int) ([]models.Todo, error) {
+	var result []models.Todo
+	for id := 1; id <= len(m.todos); id++ {
+		todo, ok := m.todos[id]
+		if ok && todo.UserID == userID && (todo.ID == rootID || (todo.SeriesID != nil && *todo.SeriesID == rootID)) {
+			result = append(result, *todo)
+		}
+	}
+	return result, nil
+}
+
+func (m *todoRepoMock) LastOccurrence(ctx context.Context, rootID int) (int, error) {
+	var last int
+	for _, todos := range []map[int]*models.Todo{m.todos, m.trash} {
+		for _, todo := range todos {
+			if todo.ID == rootID || (todo.SeriesID != nil && *todo.SeriesID == rootID) {
+				last = max(last, todo.Occurrence)
+			}
+		}
+	}
+	return last, nil
+}
+
+func (m *todoRepoMock) LockPositions(ctx context.Context, todo *models.Todo) error {
+	return nil
+}
+
+func (m *todoRepoMock) NextPosition(ctx context.Context, todo *models.Todo) (string, error) {
+	last := ""
+	for _, other := range m.todos {
+		if other.ID != todo.ID && samePositionScope(todo, other) && other.Position > last {
+			last = other.Position
+		}
+	}
+	return rank.Between(last, "")
+}
+
+func (m *todoRepoMock) NeighbourPosition(ctx context.Context, todo *models.Todo, position string, after bool) (string, error) {
+	neighbour := ""
+	for _, other := range m.todos {
+		if other.ID == todo.ID || !samePositionScope(todo, other) {
+			continue
+		}
+		if after && other.Position > position && (neighbour == "" || other.Position < neighbour) {
+			neighbour = other.Position
+		}
+		if !after && other.Position < position && other.Position > neighbour {
+			neighbour = other.Position
+		}
+	}
+	return neighbour, nil
+}
+
+func (m *todoRepoMock) SetPosition(ctx context.Context, todo *models.Todo, position string) error {
+	todo.Position = position
 	m.todos[todo.ID] = todo
 	return nil
 }
 
-func (m *todoRepoMock) Delete(id int) error {
-	if _, ok := m.todos[id]; !ok {
+func (m *todoRepoMock) Stream(ctx context.Context, userID, batchSize int, fn func(todos []models.Todo) error) error {
+	todos, err := m.List(ctx, userID, models.TodoFilter{})
+	if err != nil {
+		return err
+	}
+	for batch := range slices.Chunk(todos, batchSize) {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *todoRepoMock) ListOwned(ctx context.Context, userID int) ([]models.Todo, error) {
+	var result []models.Todo
+	for id := 1; id <= len(m.todos)+len(m.trash); id++ {
+		if todo, ok := m.todos[id]; ok && todo.UserID == userID {
+			result = append(result, *todo)
+		}
+	}
+	return result, nil
+}
+
+func (m *todoRepoMock) FindCalDAV(ctx context.Context, userID int, name string, id int) (*models.Todo, error) {
+	for _, todo := range m.todos {
+		if todo.UserID != userID {
+			continue
+		}
+		if (todo.CalDAVName != nil && *todo.CalDAVName == name) || (todo.CalDAVName == nil && todo.ID == id) {
+			return todo, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *todoRepoMock) ChangedSince(ctx context.Context, userID, eventID int) ([]models.Todo, error) {
+	changed := map[int]bool{}
+	for _, event := range m.events {
+		if event.ID > eventID {
+			changed[event.TodoID] = true
+		}
+	}
+	var result []models.Todo
+	for id := range changed {
+		todo, ok := m.todos[id]
+		if !ok {
+			todo, ok = m.trash[id]
+		}
+		if ok && todo.UserID == userID {
+			result = append(result, *todo)
+		}
+	}
+	return result, nil
+}
+
+func (m *todoRepoMock) LatestEventID(ctx context.Context, userID int) (int, error) {
+	return len(m.events), nil
+}
+
+func (m *todoRepoMock) AddEvent(ctx context.Context, event *models.TodoEvent) error {
+	event.ID = len(m.events) + 1
+	m.events = append(m.events, *event)
+	return nil
+}
+
+func (m *todoRepoMock) ListEvents(ctx context.Context, todoID int) ([]models.TodoEvent, error) {
+	var events []models.TodoEvent
+	for _, event := range m.events {
+		if event.TodoID == todoID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *todoRepoMock) openBlockers(todoID int) int64 {
+	var open int64
+	for _, id := range m.blockers[todoID] {
+		if blocker, ok := m.todos[id]; ok && !blocker.Completed {
+			open++
+		}
+	}
+	return open
+}
+
+func (m *todoRepoMock) ListBlockers(ctx context.Context, userID, todoID int) ([]models.Todo, error) {
+	var result []models.Todo
+	for _, id := range m.blockers[todoID] {
+		if todo, ok := m.todos[id]; ok && m.role(userID, todo) != "" {
+			result = append(result, *todo)
+		}
+	}
+	return result, nil
+}
+
+func (m *todoRepoMock) ListBlocked(ctx context.Context, userID, todoID int) ([]models.Todo, error) {
+	var result []models.Todo
+	for id := 1; id <= len(m.todos); id++ {
+		todo, ok := m.todos[id]
+		if ok && slices.Contains(m.blockers[id], todoID) && m.role(userID, todo) != "" {
+			result = append(result, *todo)
+		}
+	}
+	return result, nil
+}
+
+func (m *todoRepoMock) OpenBlockers(ctx context.Context, todoID int) (int64, error) {
+	return m.openBlockers(todoID), nil
+}
+
+func (m *todoRepoMock) IsBlockedBy(ctx context.Context, todoID, blockerID int) (bool, error) {
+	seen := map[int]bool{}
+	queue := []int{todoID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range m.blockers[id] {
+			if next == blockerID {
+				return true, nil
+			}
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false, nil
+}
+
+func (m *todoRepoMock) LockDependencies(ctx context.Context) error {
+	return nil
+}
+
+func (m *todoRepoMock) LockSubtree(ctx context.Context, userID, id int) error {
+	return nil
+}
+
+func (m *todoRepoMock) AddDependency(ctx context.Context, todoID, blockerID int) (bool, error) {
+	if slices.Contains(m.blockers[todoID], blockerID) {
+		return false, nil
+	}
+	if m.blockers == nil {
+		m.blockers = make(map[int][]int)
+	}
+	m.blockers[todoID] = append(m.blockers[todoID], blockerID)
+	return true, nil
+}
+
+func (m *todoRepoMock) RemoveDependency(ctx context.Context, todoID, blockerID int) error {
+	i := slices.Index(m.blockers[todoID], blockerID)
+	if i < 0 {
+		return sql.ErrNoRows
+	}
+	m.blockers[todoID] = slices.Delete(m.blockers[todoID], i, i+1)
+	return nil
+}
+
+func (m *todoRepoMock) FindList(ctx context.Context, userID, id int) (*models.TodoList, error) {
+	list, ok := m.lists[id]
+	if !ok {
+		return nil, nil
+	}
+	list.Role = models.ListRoleOwner
+	if list.UserID != userID {
+		list.Role = m.members[id][userID]
+	}
+	if list.Role == "" {
+		return nil, nil
+	}
+	return &list, nil
+}
+
+func (m *todoRepoMock) FindInList(ctx context.Context, listID int, ids []int) ([]models.Todo, error) {
+	var todos []models.Todo
+	for _, todo := range m.todos {
+		if todo.ListID != nil && *todo.ListID == listID && (ids == nil || slices.Contains(ids, todo.ID)) {
+			todos = append(todos, *todo)
+		}
+	}
+	slices.SortFunc(todos, func(a, b models.Todo) int { return strings.Compare(a.Position, b.Position) })
+	return todos, nil
+}
+
+// MoveToList mirrors the repository: an assignee who can't see the new list
+// is dropped unless they own the todo.
+func (m *todoRepoMock) MoveToList(ctx context.Context, todos []models.Todo, listID *int) ([]models.Todo, error) {
+	moved := make([]models.Todo, 0, len(todos))
+	for _, todo := range todos {
+		stored := m.todos[todo.ID]
+		stored.ListID = listID
+		if stored.AssigneeID != nil && *stored.AssigneeID != stored.UserID {
+			var list *models.TodoList
+			if listID != nil {
+				list, _ = m.FindList(ctx, *stored.AssigneeID, *listID)
+			}
+			if list == nil {
+				stored.AssigneeID = nil
+			}
+		}
+		position, err := m.NextPosition(ctx, stored)
+		if err != nil {
+			return nil, err
+		}
+		stored.Position = position
+		moved = append(moved, *stored)
+	}
+	return moved, nil
+}
+
+func (m *todoRepoMock) DeleteList(ctx context.Context, userID, id int) error {
+	if list, ok := m.lists[id]; !ok || list.UserID != userID {
 		return sql.ErrNoRows
 	}
-	delete(m.todos, id)
+	delete(m.lists, id)
+	delete(m.members, id)
 	return nil
 }
 
+// Transaction restores the todos, tags and history it started with when fn
+// fails, like a rollback.
+func (m *todoRepoMock) Transaction(ctx context.Context, fn func(repo repository.TodoRepository) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	snapshot := make(map[int]models.Todo, len(m.todos))
+	for id, todo := range m.todos {
+		snapshot[id] = *todo
+	}
+	tags := maps.Clone(m.tags)
+	events := len(m.events)
+
+	err := fn(m)
+	if err != nil {
+		m.todos = make(map[int]*models.Todo, len(snapshot))
+		for id, todo := range snapshot {
+			m.todos[id] = &todo
+		}
+		if m.tags != nil {
+			clear(m.tags)
+			maps.Copy(m.tags, tags)
+		}
+		m.events = m.events[:events]
+	}
+	return err
+}
+
 func TestTodoServiceCreate(t *testing.T) {
+	ctx := context.Background()
 	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
-	svc := NewTodoService(repo)
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
 
 	req := models.CreateTodoRequest{
 		Title:       "Write tests",
 		Description: "Add service unit tests",
 	}
 
-	todo, err := svc.Create(req)
+	todo, err := svc.Create(ctx, 1, req)
 	if err != nil {
 		t.Fatalf("Create() error = %v", err)
 	}
@@ -69,18 +622,22 @@ func TestTodoServiceCreate(t *testing.T) {
 	if todo.ID == 0 {
 		t.Fatalf("Create() expected non-zero ID, got %d", todo.ID)
 	}
+	if todo.UserID != 1 {
+		t.Fatalf("Create() expected owner 1, got %d", todo.UserID)
+	}
 	if todo.Title != req.Title || todo.Description != req.Description {
 		t.Fatalf("Create() returned unexpected todo: %+v", todo)
 	}
 }
 
 func TestTodoServiceUpdate(t *testing.T) {
+	ctx := context.Background()
 	repo := &todoRepoMock{
 		todos: map[int]*models.Todo{
-			1: {ID: 1, Title: "Old", Description: "Old desc", Completed: false},
+			1: {ID: 1, UserID: 1, Title: "Old", Description: "Old desc", Completed: false},
 		},
 	}
-	svc := NewTodoService(repo)
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
 
 	newTitle := "New title"
 	completed := true
@@ -89,7 +646,7 @@ func TestTodoServiceUpdate(t *testing.T) {
 		Completed: &completed,
 	}
 
-	todo, err := svc.Update(1, req)
+	todo, err := svc.Update(ctx, 1, 1, req)
 	if err != nil {
 		t.Fatalf("Update() error = %v", err)
 	}
@@ -103,3 +660,1131 @@ func TestTodoServiceUpdate(t *testing.T) {
 		t.Fatal("Update() expected completed=true")
 	}
 }
+
+func TestTodoServiceStatusTransitions(t *testing.T) {
+	yes, no := true, false
+	status := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		from    models.TodoStatus
+		req     models.UpdateTodoRequest
+		want    models.TodoStatus
+		wantErr error
+	}{
+		{name: "start work", from: models.StatusTodo, req: models.UpdateTodoRequest{Status: status("in_progress")}, want: models.StatusInProgress},
+		{name: "finish work", from: models.StatusInProgress, req: models.UpdateTodoRequest{Status: status("done")}, want: models.StatusDone},
+		{name: "block", from: models.StatusInProgress, req: models.UpdateTodoRequest{Status: status("blocked")}, want: models.StatusBlocked},
+		{name: "completed true", from: models.StatusTodo, req: models.UpdateTodoRequest{Completed: &yes}, want: models.StatusDone},
+		{name: "completed false reopens", from: models.StatusDone, req: models.UpdateTodoRequest{Completed: &no}, want: models.StatusTodo},
+		{name: "completed false keeps open status", from: models.StatusBlocked, req: models.UpdateTodoRequest{Completed: &no}, want: models.StatusBlocked},
+		{name: "same status", from: models.StatusBlocked, req: models.UpdateTodoRequest{Status: status("blocked")}, want: models.StatusBlocked},
+		{name: "blocked to done", from: models.StatusBlocked, req: models.UpdateTodoRequest{Status: status("done")}, wantErr: ErrInvalidTransition},
+		{name: "complete blocked", from: models.StatusBlocked, req: models.UpdateTodoRequest{Completed: &yes}, wantErr: ErrInvalidTransition},
+		{name: "unknown status", from: models.StatusTodo, req: models.UpdateTodoRequest{Status: status("review")}, wantErr: ErrInvalidStatus},
+		{name: "contradicts completed", from: models.StatusTodo, req: models.UpdateTodoRequest{Status: status("done"), Completed: &no}, wantErr: ErrInvalidStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			todo := &models.Todo{ID: 1, UserID: 1, Title: "Ship it"}
+			todo.SetStatus(tt.from)
+			repo := &todoRepoMock{todos: map[int]*models.Todo{1: todo}}
+			svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+			got, err := svc.Update(ctx, 1, 1, tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+				}
+				if repo.todos[1].Status != tt.from {
+					t.Fatalf("Update() changed status to %q on error", repo.todos[1].Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if got.Status != tt.want || got.Completed != (tt.want == models.StatusDone) {
+				t.Fatalf("Update() status = %q completed = %v, want %q", got.Status, got.Completed, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodoServiceCreateStatus(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	todo, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Plan trip", Description: "Pick dates"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if todo.Status != models.StatusTodo || todo.Completed {
+		t.Fatalf("Create() status = %q completed = %v, want todo", todo.Status, todo.Completed)
+	}
+
+	todo, err = svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Book hotel", Description: "Near the station", Status: "in_progress"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if todo.Status != models.StatusInProgress {
+		t.Fatalf("Create() status = %q, want in_progress", todo.Status)
+	}
+
+	if _, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Pack bags", Description: "Night before", Status: "someday"}); !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("Create() expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestTodoServiceBoard(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	for i, status := range []models.TodoStatus{models.StatusTodo, models.StatusInProgress, models.StatusTodo, models.StatusDone, models.StatusTodo} {
+		todo := &models.Todo{ID: i + 1, UserID: 1, Title: fmt.Sprintf("Card %d", i+1)}
+		todo.SetStatus(status)
+		repo.todos[todo.ID] = todo
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	board, err := svc.Board(ctx, 1, models.TodoBoardQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("Board() error = %v", err)
+	}
+
+	want := []struct {
+		status models.TodoStatus
+		total  int64
+		shown  int
+	}{
+		{models.StatusTodo, 3, 2},
+		{models.StatusInProgress, 1, 1},
+		{models.StatusBlocked, 0, 0},
+		{models.StatusDone, 1, 1},
+	}
+	if len(board.Columns) != len(want) {
+		t.Fatalf("Board() returned %d columns, want %d", len(board.Columns), len(want))
+	}
+	for i, column := range board.Columns {
+		if column.Status != want[i].status || column.Total != want[i].total || len(column.Todos) != want[i].shown {
+			t.Fatalf("Board() column %d = %s total %d with %d todos, want %+v", i, column.Status, column.Total, len(column.Todos), want[i])
+		}
+		for _, todo := range column.Todos {
+			if todo.Status != column.Status {
+				t.Fatalf("Board() put a %s todo in the %s column", todo.Status, column.Status)
+			}
+		}
+	}
+	if next := board.Columns[2].Next; !slices.Equal(next, []models.TodoStatus{models.StatusTodo, models.StatusInProgress}) {
+		t.Fatalf("Board() blocked column next = %v", next)
+	}
+
+	if _, err := svc.Board(ctx, 1, models.TodoBoardQuery{Limit: 500}); !errors.Is(err, ErrInvalidTodoQuery) {
+		t.Fatalf("Board() expected ErrInvalidTodoQuery for a huge limit, got %v", err)
+	}
+}
+
+func TestTodoServiceStopsOnCanceledContext(t *testing.T) {
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Old"},
+		},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := svc.GetByID(ctx, 1, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetByID() expected context.Canceled, got %v", err)
+	}
+	newTitle := "New title"
+	if _, err := svc.Update(ctx, 1, 1, models.UpdateTodoRequest{Title: &newTitle}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Update() expected context.Canceled, got %v", err)
+	}
+	if repo.todos[1].Title != "Old" {
+		t.Fatalf("todo was modified after cancellation: %+v", repo.todos[1])
+	}
+}
+
+func TestTodoServiceHidesOtherUsersTodos(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Mine", Description: "Owned by user 1"},
+		},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	todo, err := svc.GetByID(ctx, 2, 1)
+	if err != nil || todo != nil {
+		t.Fatalf("GetByID() expected no todo for another user, got %+v, %v", todo, err)
+	}
+
+	newTitle := "Hijacked"
+	if _, err := svc.Update(ctx, 2, 1, models.UpdateTodoRequest{Title: &newTitle}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Update() expected sql.ErrNoRows for another user, got %v", err)
+	}
+	if err := svc.Delete(ctx, 2, 1, ""); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Delete() expected sql.ErrNoRows for another user, got %v", err)
+	}
+	if repo.todos[1].Title != "Mine" {
+		t.Fatalf("todo was modified by another user: %+v", repo.todos[1])
+	}
+}
+
+func TestTodoServiceListPaginates(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+	for range 3 {
+		if _, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Task", Description: "Paged todo"}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	query := models.TodoListQuery{Sort: models.TodoSortTitle, Order: "asc", Limit: 2}
+	page, err := svc.List(ctx, 1, query)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page.Todos) != 2 || !page.HasMore || page.NextCursor == "" || page.TotalEstimate != 3 {
+		t.Fatalf("List() unexpected first page: %+v", page)
+	}
+
+	query.Cursor = page.NextCursor
+	page, err = svc.List(ctx, 1, query)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page.Todos) != 1 || page.HasMore || page.NextCursor != "" {
+		t.Fatalf("List() unexpected last page: %+v", page)
+	}
+
+	query.Order = "desc"
+	if _, err := svc.List(ctx, 1, query); !errors.Is(err, ErrInvalidTodoQuery) {
+		t.Fatalf("List() expected ErrInvalidTodoQuery for mismatched cursor, got %v", err)
+	}
+}
+
+func TestTodoServiceDueTodayUsesCallerTimezone(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{}).(*todoService)
+	// 03:00 UTC on March 10th is still March 9th in New York.
+	svc.now = func() time.Time { return time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC) }
+
+	for _, due := range []struct{ value, timezone string }{
+		{"2026-03-10T02:00:00Z", ""},
+		{"2026-03-10T05:00:00Z", ""},
+		{"2026-03-09", "America/New_York"},
+	} {
+		value := due.value
+		req := models.CreateTodoRequest{Title: "Due " + value, Description: "Due date test", DueAt: &value, Timezone: due.timezone}
+		if _, err := svc.Create(ctx, 1, req); err != nil {
+			t.Fatalf("Create(%q) error = %v", value, err)
+		}
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed loading timezone: %v", err)
+	}
+	todos, err := svc.DueToday(ctx, 1, newYork)
+	if err != nil {
+		t.Fatalf("DueToday() error = %v", err)
+	}
+	if len(todos) != 2 {
+		t.Fatalf("DueToday() expected 2 todos for New York, got %d: %+v", len(todos), todos)
+	}
+
+	todos, err = svc.DueToday(ctx, 1, time.UTC)
+	if err != nil {
+		t.Fatalf("DueToday() error = %v", err)
+	}
+	if len(todos) != 3 {
+		t.Fatalf("DueToday() expected 3 todos for UTC, got %d: %+v", len(todos), todos)
+	}
+}
+
+func TestTodoServiceRejectsUnparseableDueDate(t *testing.T) {
+	ctx := context.Background()
+	svc := NewTodoService(&todoRepoMock{todos: map[int]*models.Todo{}}, workflow.Default(), config.TodoConfig{})
+
+	value := "next tuesday"
+	_, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Bad due", Description: "Bad due date", DueAt: &value})
+	if !errors.Is(err, ErrInvalidDueDate) {
+		t.Fatalf("Create() expected ErrInvalidDueDate, got %v", err)
+	}
+}
+
+func TestTodoServiceTagsMustBelongToUser(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{},
+		tags: map[int]models.Tag{
+			1: {ID: 1, UserID: 1, Name: "finance"},
+			2: {ID: 2, UserID: 2, Name: "someone-else"},
+		},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	todo, err := svc.Create(ctx, 1, models.CreateTodoRequest{
+		Title:       "Pay rent",
+		Description: "Monthly rent",
+		Priority:    "high",
+		TagIDs:      []int{1, 1},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if todo.Priority != models.PriorityHigh || len(todo.Tags) != 1 || todo.Tags[0].Name != "finance" {
+		t.Fatalf("Create() returned unexpected todo: %+v", todo)
+	}
+
+	tagIDs := []int{2}
+	if _, err := svc.Update(ctx, 1, todo.ID, models.UpdateTodoRequest{TagIDs: &tagIDs}); !errors.Is(err, ErrInvalidTag) {
+		t.Fatalf("Update() expected ErrInvalidTag for another user's tag, got %v", err)
+	}
+}
+
+func TestTodoServiceListMustBeOwnedAndOpen(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{},
+		lists: map[int]models.TodoList{
+			1: {ID: 1, UserID: 1, Name: "Groceries"},
+			2: {ID: 2, UserID: 2, Name: "Someone else's"},
+			3: {ID: 3, UserID: 1, Name: "Old", Archived: true},
+		},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	listID := 2
+	if _, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Milk", ListID: &listID}); !errors.Is(err, ErrInvalidList) {
+		t.Fatalf("Create() expected ErrInvalidList for another user's list, got %v", err)
+	}
+
+	listID = 3
+	if _, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Milk", ListID: &listID}); !errors.Is(err, ErrListArchived) {
+		t.Fatalf("Create() expected ErrListArchived, got %v", err)
+	}
+
+	listID = 1
+	parent, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Shopping", ListID: &listID})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	child, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Milk", ParentID: &parent.ID})
+	if err != nil {
+		t.Fatalf("Create() subtask error = %v", err)
+	}
+	if child.ListID == nil || *child.ListID != 1 {
+		t.Fatalf("Create() subtask list = %v, want 1", child.ListID)
+	}
+
+	noList := 0
+	updated, err := svc.Update(ctx, 1, child.ID, models.UpdateTodoRequest{ListID: &noList})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.ListID != nil {
+		t.Fatalf("Update() list = %d, want cleared", *updated.ListID)
+	}
+}
+
+func TestTodoServiceEnforcesSharedListRoles(t *testing.T) {
+	ctx := context.Background()
+	listID := 1
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Buy milk", ListID: &listID},
+			2: {ID: 2, UserID: 1, Title: "Private"},
+		},
+		lists: map[int]models.TodoList{
+			1: {ID: 1, UserID: 1, Name: "Groceries"},
+		},
+		members: map[int]map[int]models.ListRole{
+			1: {2: models.ListRoleViewer, 3: models.ListRoleEditor},
+		},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	if todo, err := svc.GetByID(ctx, 2, 1); err != nil || todo == nil {
+		t.Fatalf("GetByID() viewer should see shared todo, got %v, %v", todo, err)
+	}
+	if todo, err := svc.GetByID(ctx, 2, 2); err != nil || todo != nil {
+		t.Fatalf("GetByID() viewer should not see unshared todo, got %v, %v", todo, err)
+	}
+
+	title := "Buy oat milk"
+	if _, err := svc.Update(ctx, 2, 1, models.UpdateTodoRequest{Title: &title}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Update() expected ErrForbidden for viewer, got %v", err)
+	}
+	if err := svc.Delete(ctx, 2, 1, ""); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Delete() expected ErrForbidden for viewer, got %v", err)
+	}
+	if _, err := svc.Create(ctx, 2, models.CreateTodoRequest{Title: "Eggs", ListID: &listID}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Create() expected ErrForbidden for viewer, got %v", err)
+	}
+
+	updated, err := svc.Update(ctx, 3, 1, models.UpdateTodoRequest{Title: &title})
+	if err != nil {
+		t.Fatalf("Update() editor error = %v", err)
+	}
+	if updated.Title != title || updated.UserID != 1 {
+		t.Fatalf("Update() returned unexpected todo: %+v", updated)
+	}
+}
+
+func TestTodoServiceBulkAtomicRollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{
+		1: {ID: 1, UserID: 1, Title: "First"},
+		2: {ID: 2, UserID: 1, Title: "Second"},
+		3: {ID: 3, UserID: 2, Title: "Someone else's"},
+	}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	result, err := svc.Bulk(ctx, 1, models.BulkTodoRequest{Operations: []models.BulkTodoOperationInput{
+		{Op: "complete", IDs: []int{1, 3, 2}},
+	}})
+	if err != nil {
+		t.Fatalf("Bulk() error = %v", err)
+	}
+	if result.Committed || result.Failed != 1 || result.Succeeded != 0 {
+		t.Fatalf("Bulk() returned unexpected summary: %+v", result)
+	}
+	want := []models.BulkItemStatus{models.BulkItemRolledBack, models.BulkItemFailed, models.BulkItemSkipped}
+	for i, item := range result.Results {
+		if item.Status != want[i] {
+			t.Fatalf("Bulk() item %d status = %s, want %s", item.ID, item.Status, want[i])
+		}
+	}
+	if repo.todos[1].Completed {
+		t.Fatalf("Bulk() atomic failure should roll back todo 1")
+	}
+}
+
+func TestTodoServiceBulkBestEffortKeepsSuccesses(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{
+		1: {ID: 1, UserID: 1, Title: "First", Completed: true},
+		2: {ID: 2, UserID: 1, Title: "Second"},
+	}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	result, err := svc.Bulk(ctx, 1, models.BulkTodoRequest{
+		Mode: "best_effort",
+		Operations: []models.BulkTodoOperationInput{
+			{Op: "delete", IDs: []int{1, 99}},
+			{Op: "complete", IDs: []int{2}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Bulk() error = %v", err)
+	}
+	if !result.Committed || result.Succeeded != 2 || result.Failed != 1 {
+		t.Fatalf("Bulk() returned unexpected summary: %+v", result)
+	}
+	if result.Results[1].Error != "todo not found" {
+		t.Fatalf("Bulk() item 99 error = %q", result.Results[1].Error)
+	}
+	if _, ok := repo.todos[1]; ok || !repo.todos[2].Completed {
+		t.Fatalf("Bulk() best effort should keep successful items")
+	}
+}
+
+func TestTodoServiceDeleteMovesToTrashAndRestores(t *testing.T) {
+	ctx := context.Background()
+	parentID := 1
+	repo := &todoRepoMock{todos: map[int]*models.Todo{
+		1: {ID: 1, UserID: 1, Title: "Move house"},
+		2: {ID: 2, UserID: 1, Title: "Pack boxes", ParentID: &parentID},
+	}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	if err := svc.Delete(ctx, 1, 1, models.SubtaskDeleteCascade); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if todo, _ := svc.GetByID(ctx, 1, 1); todo != nil {
+		t.Fatalf("GetByID() should not return a trashed todo")
+	}
+	trash, err := svc.Trash(ctx, 1)
+	if err != nil || len(trash) != 2 {
+		t.Fatalf("Trash() = %d todos, %v; want 2", len(trash), err)
+	}
+	if _, err := svc.Restore(ctx, 2, 1); err != sql.ErrNoRows {
+		t.Fatalf("Restore() expected sql.ErrNoRows for another user, got %v", err)
+	}
+
+	restored, err := svc.Restore(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.DeletedAt.Valid {
+		t.Fatalf("Restore() returned a todo still marked deleted")
+	}
+	if todo, _ := svc.GetByID(ctx, 1, 1); todo == nil || len(todo.Children) != 1 {
+		t.Fatalf("Restore() should bring back the subtask deleted with its parent, got %+v", todo)
+	}
+}
+
+func TestTodoServiceSubtasksRollUpAndAutoComplete(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{AutoCompleteParent: true})
+
+	parent, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Move house", Description: "Parent task"})
+	if err != nil {
+		t.Fatalf("Create() parent error = %v", err)
+	}
+	var children []*models.Todo
+	for _, title := range []string{"Pack boxes", "Book van"} {
+		child, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: title, Description: "Subtask", ParentID: &parent.ID})
+		if err != nil {
+			t.Fatalf("Create() child error = %v", err)
+		}
+		children = append(children, child)
+	}
+
+	completed := true
+	if _, err := svc.Update(ctx, 1, children[0].ID, models.UpdateTodoRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	tree, err := svc.GetByID(ctx, 1, parent.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if len(tree.Children) != 2 || tree.Progress == nil || tree.Progress.Done != 1 || tree.Progress.Total != 2 {
+		t.Fatalf("GetByID() unexpected subtree: %+v", tree)
+	}
+	if tree.Completed {
+		t.Fatal("parent completed before all subtasks were done")
+	}
+
+	if _, err := svc.Update(ctx, 1, children[1].ID, models.UpdateTodoRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !repo.todos[parent.ID].Completed {
+		t.Fatal("parent was not auto-completed once all subtasks were done")
+	}
+}
+
+func TestTodoServiceDeleteParentRequiresMode(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	parent, _ := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Parent", Description: "Has a child"})
+	child, _ := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Child", Description: "Subtask", ParentID: &parent.ID})
+
+	if err := svc.Delete(ctx, 1, parent.ID, ""); !errors.Is(err, ErrHasSubtasks) {
+		t.Fatalf("Delete() expected ErrHasSubtasks, got %v", err)
+	}
+	if err := svc.Delete(ctx, 1, parent.ID, models.SubtaskDeletePromote); err != nil {
+		t.Fatalf("Delete() promote error = %v", err)
+	}
+	if promoted := repo.todos[child.ID]; promoted == nil || promoted.ParentID != nil {
+		t.Fatalf("Delete() promote expected root-level child, got %+v", promoted)
+	}
+}
+
+func TestTodoServiceRecurrenceFollowsTheTodosTimezone(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	// Monday 00:30 in Berlin is still Sunday in UTC, and the next Monday
+	// falls after the switch back from summer time.
+	due := "2026-10-19T00:30:00+02:00"
+	first, err := svc.Create(ctx, 1, models.CreateTodoRequest{
+		Title:          "Weekly review",
+		Description:    "Mondays after midnight",
+		DueAt:          &due,
+		Timezone:       "Europe/Berlin",
+		RecurrenceRule: "FREQ=WEEKLY;BYDAY=MO",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	// Postgres hands TIMESTAMPTZ values back in UTC.
+	utc := first.DueAt.UTC()
+	repo.todos[first.ID].DueAt = &utc
+
+	completed := true
+	done, err := svc.Update(ctx, 1, first.ID, models.UpdateTodoRequest{Completed: &completed})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	want := time.Date(2026, 10, 26, 0, 30, 0, 0, berlin)
+	if next := done.NextOccurrence; next == nil || next.DueAt == nil || !next.DueAt.Equal(want) || next.Timezone != "Europe/Berlin" {
+		t.Fatalf("Update() spawned %+v, want due %v in Europe/Berlin", next, want)
+	}
+}
+
+func TestTodoServiceRecompletingKeepsATrashedSuccessor(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	due := "2026-10-16"
+	first, err := svc.Create(ctx, 1, models.CreateTodoRequest{
+		Title:          "Water plants",
+		Description:    "Weekly chore",
+		DueAt:          &due,
+		RecurrenceRule: "FREQ=WEEKLY",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	completed, reopened := true, false
+	done, err := svc.Update(ctx, 1, first.ID, models.UpdateTodoRequest{Completed: &completed})
+	if err != nil || done.NextOccurrence == nil {
+		t.Fatalf("Update() = %+v, %v, want a successor", done, err)
+	}
+	if err := svc.Delete(ctx, 1, done.NextOccurrence.ID, ""); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := svc.Update(ctx, 1, first.ID, models.UpdateTodoRequest{Completed: &reopened}); err != nil {
+		t.Fatalf("Update() reopen error = %v", err)
+	}
+	again, err := svc.Update(ctx, 1, first.ID, models.UpdateTodoRequest{Completed: &completed})
+	if err != nil || again.NextOccurrence != nil {
+		t.Fatalf("Update() re-complete = %+v, %v, want no second successor", again.NextOccurrence, err)
+	}
+}
+
+func TestTodoServiceCascadeDeleteNeedsEditRightsOnSubtasks(t *testing.T) {
+	ctx := context.Background()
+	listID, parentID, editor := 1, 1, 2
+
+	tests := []struct {
+		name    string
+		child   models.Todo
+		wantErr error
+	}{
+		{name: "subtask in the shared list", child: models.Todo{UserID: 1, ListID: &listID}},
+		{name: "subtask the editor can't see", child: models.Todo{UserID: 3}, wantErr: ErrForbidden},
+		{name: "subtask only assigned to the editor", child: models.Todo{UserID: 3, AssigneeID: &editor}, wantErr: ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			child := tt.child
+			child.ID, child.Title, child.ParentID = 2, "Child", &parentID
+			repo := &todoRepoMock{
+				todos: map[int]*models.Todo{
+					1: {ID: 1, UserID: 1, Title: "Parent", ListID: &listID},
+					2: &child,
+				},
+				lists:   map[int]models.TodoList{1: {ID: 1, UserID: 1, Name: "Team"}},
+				members: map[int]map[int]models.ListRole{1: {editor: models.ListRoleEditor}},
+			}
+			svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+			err := svc.Delete(ctx, editor, parentID, models.SubtaskDeleteCascade)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+			if deleted := repo.todos[child.ID] == nil; deleted != (tt.wantErr == nil) {
+				t.Fatalf("Delete() deleted subtask = %v, want %v", deleted, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestTodoServiceCompletingRecurringTodoSpawnsNext(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	due := "2026-10-16T18:00:00Z"
+	first, err := svc.Create(ctx, 1, models.CreateTodoRequest{
+		Title:          "Water plants",
+		Description:    "Weekly chore",
+		DueAt:          &due,
+		RecurrenceRule: "freq=weekly;count=2",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if first.RecurrenceRule == nil || *first.RecurrenceRule != "FREQ=WEEKLY;COUNT=2" {
+		t.Fatalf("Create() expected canonical rule, got %v", first.RecurrenceRule)
+	}
+
+	completed := true
+	done, err := svc.Update(ctx, 1, first.ID, models.UpdateTodoRequest{Completed: &completed})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	next := done.NextOccurrence
+	if next == nil {
+		t.Fatal("Update() did not spawn the next occurrence")
+	}
+	wantDue := time.Date(2026, 10, 23, 18, 0, 0, 0, time.UTC)
+	if next.DueAt == nil || !next.DueAt.Equal(wantDue) || next.Occurrence != 2 || next.SeriesID == nil || *next.SeriesID != first.ID {
+		t.Fatalf("Update() spawned unexpected occurrence: %+v", next)
+	}
+
+	// Reopening and re-completing must not spawn a duplicate.
+	reopened := false
+	if _, err := svc.Update(ctx, 1, first.ID, models.UpdateTodoRequest{Completed: &reopened}); err != nil {
+		t.Fatalf("Update() reopen error = %v", err)
+	}
+	again, err := svc.Update(ctx, 1, first.ID, models.UpdateTodoRequest{Completed: &completed})
+	if err != nil || again.NextOccurrence != nil {
+		t.Fatalf("Update() re-complete expected no new occurrence, got %+v, %v", again.NextOccurrence, err)
+	}
+
+	// COUNT=2 ends the series after the second occurrence.
+	last, err := svc.Update(ctx, 1, next.ID, models.UpdateTodoRequest{Completed: &completed})
+	if err != nil || last.NextOccurrence != nil {
+		t.Fatalf("Update() expected series to end after COUNT, got %+v, %v", last.NextOccurrence, err)
+	}
+
+	if err := svc.StopSeries(ctx, 1, next.ID); err != nil {
+		t.Fatalf("StopSeries() error = %v", err)
+	}
+	for _, todo := range repo.todos {
+		if todo.RecurrenceRule != nil {
+			t.Fatalf("StopSeries() left a rule on todo %d", todo.ID)
+		}
+	}
+}
+
+func TestTodoServiceMoveReordersOneTodo(t *testing.T) {
+	ctx := context.Background()
+	listID := 5
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	for _, title := range []string{"First", "Second", "Third"} {
+		if _, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: title, Description: "Ordering test"}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	repo.todos[4] = &models.Todo{ID: 4, UserID: 1, ListID: &listID, Position: "V"}
+
+	order := func() []int {
+		ids := []int{1, 2, 3}
+		slices.SortFunc(ids, func(a, b int) int {
+			return strings.Compare(repo.todos[a].Position, repo.todos[b].Position)
+		})
+		return ids
+	}
+
+	first, second := 1, 2
+	before := repo.todos[1].Position
+	if _, err := svc.Move(ctx, 1, 3, models.MoveTodoRequest{BeforeID: &first}); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if got := order(); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Fatalf("order after moving 3 to the top = %v, want [3 1 2]", got)
+	}
+	if repo.todos[1].Position != before {
+		t.Fatalf("Move() rewrote the neighbour's position %q -> %q", before, repo.todos[1].Position)
+	}
+
+	if _, err := svc.Move(ctx, 1, 3, models.MoveTodoRequest{AfterID: &first, BeforeID: &second}); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if got := order(); !slices.Equal(got, []int{1, 3, 2}) {
+		t.Fatalf("order after moving 3 between 1 and 2 = %v, want [1 3 2]", got)
+	}
+
+	if _, err := svc.Move(ctx, 1, 2, models.MoveTodoRequest{AfterID: &second}); !errors.Is(err, ErrInvalidMove) {
+		t.Fatalf("Move() next to itself error = %v, want ErrInvalidMove", err)
+	}
+	other := 4
+	if _, err := svc.Move(ctx, 1, 2, models.MoveTodoRequest{AfterID: &other}); !errors.Is(err, ErrInvalidMove) {
+		t.Fatalf("Move() next to a todo in another list error = %v, want ErrInvalidMove", err)
+	}
+	if _, err := svc.Move(ctx, 1, 1, models.MoveTodoRequest{AfterID: &second, BeforeID: &first}); !errors.Is(err, ErrInvalidMove) {
+		t.Fatalf("Move() with reversed neighbours error = %v, want ErrInvalidMove", err)
+	}
+}
+
+func TestTodoServiceRecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	todo, err := svc.Create(ctx, 1, models.CreateTodoRequest{Title: "Draft", Description: "History test"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	title := "Final"
+	if _, err := svc.Update(ctx, 1, todo.ID, models.UpdateTodoRequest{Title: &title}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	completed := true
+	if _, err := svc.Update(ctx, 1, todo.ID, models.UpdateTodoRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	// Saving the same values again is not a change.
+	if _, err := svc.Update(ctx, 1, todo.ID, models.UpdateTodoRequest{Title: &title}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	events, err := svc.History(ctx, 1, todo.ID)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	wantActions := []models.TodoEventAction{models.TodoEventCreated, models.TodoEventUpdated, models.TodoEventCompleted}
+	if len(events) != len(wantActions) {
+		t.Fatalf("History() returned %d events, want %d: %+v", len(events), len(wantActions), events)
+	}
+	for i, event := range events {
+		if event.Action != wantActions[i] {
+			t.Fatalf("event %d action = %q, want %q", i, event.Action, wantActions[i])
+		}
+		if event.UserID == nil || *event.UserID != 1 {
+			t.Fatalf("event %d user = %v, want 1", i, event.UserID)
+		}
+	}
+	if change := events[1].Changes["title"]; change.Before != "Draft" || change.After != "Final" {
+		t.Fatalf("title change = %+v, want Draft -> Final", change)
+	}
+	if len(events[1].Changes) != 1 {
+		t.Fatalf("update recorded unchanged fields: %+v", events[1].Changes)
+	}
+
+	if _, err := svc.History(ctx, 2, todo.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("History() for another user error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestTodoServiceHistoryRollsBackWithChange(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Mine"},
+		},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	completed := true
+	result, err := svc.Bulk(ctx, 1, models.BulkTodoRequest{Operations: []models.BulkTodoOperationInput{
+		{Op: string(models.BulkOpComplete), IDs: []int{1, 99}},
+	}})
+	if err != nil {
+		t.Fatalf("Bulk() error = %v", err)
+	}
+	if result.Committed {
+		t.Fatal("Bulk() committed despite a missing todo")
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("rolled back bulk left %d history events", len(repo.events))
+	}
+
+	if _, err := svc.Update(ctx, 1, 1, models.UpdateTodoRequest{Completed: &completed}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(repo.events) != 1 || repo.events[0].Action != models.TodoEventCompleted {
+		t.Fatalf("events after completing = %+v, want one completed event", repo.events)
+	}
+}
+
+func TestTodoServiceImportReportsRowsAndDryRun(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{},
+		tags:  map[int]models.Tag{1: {ID: 1, UserID: 1, Name: "work"}},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	rows := []models.TodoImportRow{
+		{Line: 2, Request: models.CreateTodoRequest{Title: "Imported", Description: "From a file", TagIDs: []int{1}}},
+		{Line: 3, Request: models.CreateTodoRequest{Title: "Bad tag", Description: "From a file", TagIDs: []int{42}}},
+		{Line: 4, Err: errors.New("list_id must be a number")},
+		{Line: 5, Request: models.CreateTodoRequest{Title: "Bad status", Description: "From a file", Status: "archived"}},
+	}
+
+	preview, err := svc.Import(ctx, 1, rows, true)
+	if err != nil {
+		t.Fatalf("Import(dry run) error = %v", err)
+	}
+	if len(repo.todos) != 0 || len(repo.events) != 0 {
+		t.Fatalf("dry run wrote %d todos and %d events", len(repo.todos), len(repo.events))
+	}
+	if preview.Valid != 1 || preview.Failed != 3 || preview.Created != 0 {
+		t.Fatalf("dry run counts = %+v, want 1 valid and 3 failed", preview)
+	}
+	if preview.Rows[0].Status != models.TodoImportValid || preview.Rows[0].Todo == nil {
+		t.Fatalf("dry run row 2 = %+v, want a valid row with its todo", preview.Rows[0])
+	}
+
+	result, err := svc.Import(ctx, 1, rows, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Created != 1 || result.Failed != 3 || len(repo.todos) != 1 {
+		t.Fatalf("Import() counts = %+v with %d todos stored, want 1 created", result, len(repo.todos))
+	}
+	for i, want := range []models.TodoImportStatus{models.TodoImportCreated, models.TodoImportFailed, models.TodoImportFailed, models.TodoImportFailed} {
+		row := result.Rows[i]
+		if row.Line != rows[i].Line || row.Status != want {
+			t.Fatalf("row %d = %+v, want line %d %s", i, row, rows[i].Line, want)
+		}
+		if want == models.TodoImportFailed && row.Error == "" {
+			t.Fatalf("failed row %d has no error", row.Line)
+		}
+	}
+
+	tooMany := make([]models.TodoImportRow, MaxImportRows+1)
+	if _, err := svc.Import(ctx, 1, tooMany, true); !errors.Is(err, ErrInvalidImport) {
+		t.Fatalf("Import() of %d rows error = %v, want ErrInvalidImport", len(tooMany), err)
+	}
+}
+
+func TestTodoServiceStatsFilter(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{}).(*todoService)
+	// Already Saturday the 17th in Tokyo.
+	svc.now = func() time.Time { return time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		query   models.TodoStatsQuery
+		want    models.TodoStatsFilter
+		wantErr bool
+	}{
+		{
+			name:  "defaults to the last 30 days in UTC",
+			query: models.TodoStatsQuery{},
+			want:  models.TodoStatsFilter{Timezone: "UTC", From: "2026-09-17", To: "2026-10-16", Today: "2026-10-16", Interval: "day"},
+		},
+		{
+			name:  "today follows the timezone",
+			query: models.TodoStatsQuery{Timezone: "Asia/Tokyo", Interval: "week"},
+			want:  models.TodoStatsFilter{Timezone: "Asia/Tokyo", From: "2026-07-26", To: "2026-10-17", Today: "2026-10-17", Interval: "week"},
+		},
+		{
+			name:  "explicit range",
+			query: models.TodoStatsQuery{From: "2026-01-01", To: "2026-01-31"},
+			want:  models.TodoStatsFilter{Timezone: "UTC", From: "2026-01-01", To: "2026-01-31", Today: "2026-10-16", Interval: "day"},
+		},
+		{
+			name:  "from alone ends today",
+			query: models.TodoStatsQuery{From: "2026-10-01"},
+			want:  models.TodoStatsFilter{Timezone: "UTC", From: "2026-10-01", To: "2026-10-16", Today: "2026-10-16", Interval: "day"},
+		},
+		{name: "unknown timezone", query: models.TodoStatsQuery{Timezone: "Mars/Base"}, wantErr: true},
+		{name: "unknown interval", query: models.TodoStatsQuery{Interval: "month"}, wantErr: true},
+		{name: "malformed date", query: models.TodoStatsQuery{From: "01/10/2026"}, wantErr: true},
+		{name: "reversed range", query: models.TodoStatsQuery{From: "2026-10-10", To: "2026-10-01"}, wantErr: true},
+		{name: "range too long", query: models.TodoStatsQuery{From: "2025-01-01", To: "2026-01-02"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.stats = nil
+			stats, err := svc.Stats(ctx, 1, tt.query)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTodoQuery) {
+					t.Fatalf("Stats() error = %v, want ErrInvalidTodoQuery", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Stats() error = %v", err)
+			}
+			if len(repo.stats) != 1 || repo.stats[0] != tt.want {
+				t.Fatalf("Stats() filter = %+v, want %+v", repo.stats, tt.want)
+			}
+			if stats.From != tt.want.From || stats.To != tt.want.To || stats.Timezone != tt.want.Timezone || stats.Interval != tt.want.Interval {
+				t.Fatalf("Stats() = %+v, want the range of %+v", stats, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodoServiceAssign(t *testing.T) {
+	ctx := context.Background()
+	listID := 1
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Shared", ListID: &listID},
+			2: {ID: 2, UserID: 1, Title: "Private"},
+		},
+		lists: map[int]models.TodoList{1: {ID: 1, UserID: 1, Name: "Team"}},
+		members: map[int]map[int]models.ListRole{1: {
+			2: models.ListRoleEditor,
+			3: models.ListRoleViewer,
+		}},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	tests := []struct {
+		name    string
+		userID  int
+		todoID  int
+		assign  int
+		wantErr error
+	}{
+		{name: "owner assigns a viewer", userID: 1, todoID: 1, assign: 3},
+		{name: "editor assigns themselves", userID: 2, todoID: 1, assign: 2},
+		{name: "viewer can't assign", userID: 3, todoID: 1, assign: 3, wantErr: ErrForbidden},
+		{name: "assignee must see the list", userID: 1, todoID: 1, assign: 4, wantErr: ErrInvalidAssignee},
+		{name: "todo without a list stays with its owner", userID: 1, todoID: 2, assign: 2, wantErr: ErrInvalidAssignee},
+		{name: "owner of a todo without a list", userID: 1, todoID: 2, assign: 1},
+		{name: "stranger gets not found", userID: 4, todoID: 1, assign: 4, wantErr: sql.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo, err := svc.Assign(ctx, tt.userID, tt.todoID, models.AssignTodoRequest{UserID: tt.assign})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Assign() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Assign() error = %v", err)
+			}
+			if todo.AssigneeID == nil || *todo.AssigneeID != tt.assign {
+				t.Fatalf("Assign() assignee = %v, want %d", todo.AssigneeID, tt.assign)
+			}
+		})
+	}
+
+	events, err := svc.History(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("History() returned %d events, want 2: %+v", len(events), events)
+	}
+	if after, ok := events[1].Changes["assignee_id"].After.(*int); !ok || *after != 2 {
+		t.Fatalf("assignee change = %+v, want a reassignment to user 2", events[1].Changes)
+	}
+
+	todo, err := svc.Unassign(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("Unassign() error = %v", err)
+	}
+	if todo.AssigneeID != nil {
+		t.Fatalf("Unassign() assignee = %d, want none", *todo.AssigneeID)
+	}
+}
+
+func TestTodoServiceMovingTodoDropsAssigneeWithoutAccess(t *testing.T) {
+	ctx := context.Background()
+	team, other, member := 1, 2, 2
+	repo := &todoRepoMock{
+		todos: map[int]*models.Todo{
+			1: {ID: 1, UserID: 1, Title: "Handed over", ListID: &team, AssigneeID: &member},
+			2: {ID: 2, UserID: 1, Title: "Also handed over", ListID: &team, AssigneeID: &member},
+		},
+		lists: map[int]models.TodoList{
+			1: {ID: 1, UserID: 1, Name: "Team"},
+			2: {ID: 2, UserID: 1, Name: "Other team"},
+		},
+		members: map[int]map[int]models.ListRole{
+			1: {member: models.ListRoleEditor},
+			2: {member: models.ListRoleViewer},
+		},
+	}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	noList := 0
+	todo, err := svc.Update(ctx, 1, 1, models.UpdateTodoRequest{ListID: &noList})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if todo.AssigneeID != nil {
+		t.Fatalf("Update() out of the list kept assignee %d", *todo.AssigneeID)
+	}
+	if got, err := svc.GetByID(ctx, member, 1); err != nil || got != nil {
+		t.Fatalf("GetByID() for the former assignee = %+v, %v; want not found", got, err)
+	}
+
+	todo, err = svc.Update(ctx, 1, 2, models.UpdateTodoRequest{ListID: &other})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if todo.AssigneeID == nil || *todo.AssigneeID != member {
+		t.Fatalf("Update() to a list the assignee is in dropped them: %v", todo.AssigneeID)
+	}
+}
+
+func TestTodoServiceAssigneeSeesTodo(t *testing.T) {
+	ctx := context.Background()
+	assignee := 2
+	repo := &todoRepoMock{todos: map[int]*models.Todo{
+		1: {ID: 1, UserID: 1, Title: "Handed over", AssigneeID: &assignee},
+		2: {ID: 2, UserID: 1, Title: "Kept"},
+		3: {ID: 3, UserID: 2, Title: "Own"},
+	}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	if _, err := svc.GetByID(ctx, 2, 1); err != nil {
+		t.Fatalf("GetByID() for the assignee error = %v", err)
+	}
+	title := "Taken over"
+	if _, err := svc.Update(ctx, 2, 1, models.UpdateTodoRequest{Title: &title}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Update() by the assignee error = %v, want ErrForbidden", err)
+	}
+
+	page, err := svc.List(ctx, 2, models.TodoListQuery{AssigneeID: &assignee})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page.Todos) != 1 || page.Todos[0].ID != 1 {
+		t.Fatalf("List(assigned_to=me) = %+v, want todo 1 only", page.Todos)
+	}
+}
+
+func TestTodoServiceRemindAt(t *testing.T) {
+	ctx := context.Background()
+	repo := &todoRepoMock{todos: map[int]*models.Todo{}}
+	svc := NewTodoService(repo, workflow.Default(), config.TodoConfig{})
+
+	due, remind := "2026-10-16T18:00:00Z", "2026-10-16T17:30:00Z"
+	todo, err := svc.Create(ctx, 1, models.CreateTodoRequest{
+		Title:          "Water plants",
+		Description:    "Weekly chore",
+		DueAt:          &due,
+		RemindAt:       &remind,
+		RecurrenceRule: "FREQ=WEEKLY",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if todo.RemindAt == nil || !todo.RemindAt.Equal(time.Date(2026, 10, 16, 17, 30, 0, 0, time.UTC)) {
+		t.Fatalf("Create() remind_at = %v", todo.RemindAt)
+	}
+
+	sentAt := time.Date(2026, 10, 16, 17, 30, 5, 0, time.UTC)
+	repo.todos[todo.ID].RemindedAt = &sentAt
+	title := "Water all plants"
+	updated, err := svc.Update(ctx, 1, todo.ID, models.UpdateTodoRequest{Title: &title, RemindAt: &remind})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.RemindedAt == nil {
+		t.Fatal("Update() with the same remind_at cleared reminded_at")
+	}
+	later := "2026-10-16T17:45:00Z"
+	if updated, err = svc.Update(ctx, 1, todo.ID, models.UpdateTodoRequest{RemindAt: &later}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.RemindedAt != nil {
+		t.Fatal("Update() with a new remind_at kept reminded_at")
+	}
+
+	completed := true
+	done, err := svc.Update(ctx, 1, todo.ID, models.UpdateTodoRequest{Completed: &completed})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	wantRemind := time.Date(2026, 10, 23, 17, 45, 0, 0, time.UTC)
+	if next := done.NextOccurrence; next == nil || next.RemindAt == nil || !next.RemindAt.Equal(wantRemind) {
+		t.Fatalf("next occurrence remind_at = %+v, want %v", next, wantRemind)
+	}
+}
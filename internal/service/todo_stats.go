@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+const (
+	// defaultStatsDays and defaultStatsWeeks size the range when from is omitted.
+	defaultStatsDays  = 30
+	defaultStatsWeeks = 12
+	// MaxStatsRangeDays bounds the stats range.
+	MaxStatsRangeDays = 366
+)
+
+// Stats reports the caller's completion metrics. The range defaults to the
+// last 30 days, or 12 weeks for the weekly series, ending today in the
+// caller's timezone.
+func (s *todoService) Stats(ctx context.Context, userID int, query models.TodoStatsQuery) (*models.TodoStats, error) {
+	filter, err := s.buildStatsFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.Stats(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	stats.From = filter.From
+	stats.To = filter.To
+	stats.Timezone = filter.Timezone
+	stats.Interval = filter.Interval
+	return stats, nil
+}
+
+func (s *todoService) buildStatsFilter(query models.TodoStatsQuery) (models.TodoStatsFilter, error) {
+	filter := models.TodoStatsFilter{Timezone: query.Timezone, Interval: query.Interval}
+	if filter.Timezone == "" {
+		filter.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(filter.Timezone)
+	if err != nil {
+		return filter, fmt.Errorf("%w: unknown timezone %q", ErrInvalidTodoQuery, filter.Timezone)
+	}
+
+	defaultDays := defaultStatsDays
+	switch filter.Interval {
+	case "", models.StatsIntervalDay:
+		filter.Interval = models.StatsIntervalDay
+	case models.StatsIntervalWeek:
+		defaultDays = 7 * defaultStatsWeeks
+	default:
+		return filter, fmt.Errorf("%w: interval must be day or week", ErrInvalidTodoQuery)
+	}
+
+	now := s.now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := today
+	if query.To != "" {
+		if to, err = time.Parse(models.DueDateLayout, query.To); err != nil {
+			return filter, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrInvalidTodoQuery)
+		}
+	}
+	from := to.AddDate(0, 0, 1-defaultDays)
+	if query.From != "" {
+		if from, err = time.Parse(models.DueDateLayout, query.From); err != nil {
+			return filter, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrInvalidTodoQuery)
+		}
+	}
+	if from.After(to) {
+		return filter, fmt.Errorf("%w: from must not be after to", ErrInvalidTodoQuery)
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > MaxStatsRangeDays {
+		return filter, fmt.Errorf("%w: the range can span at most %d days", ErrInvalidTodoQuery, MaxStatsRangeDays)
+	}
+
+	filter.From = from.Format(models.DueDateLayout)
+	filter.To = to.Format(models.DueDateLayout)
+	filter.Today = today.Format(models.DueDateLayout)
+	return filter, nil
+}
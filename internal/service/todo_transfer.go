@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/repository"
+)
+
+// ErrInvalidImport is returned for uploads that can't be imported at all.
+var ErrInvalidImport = errors.New("invalid import")
+
+// MaxImportRows caps the records of one uploaded file.
+const MaxImportRows = 1000
+
+// exportBatchSize is how many todos are loaded per round trip while exporting.
+const exportBatchSize = 500
+
+// Export hands every todo the user can see to fn in batches, so the caller
+// can stream them out without holding the whole set in memory.
+func (s *todoService) Export(ctx context.Context, userID int, fn func(todos []models.Todo) error) error {
+	return s.repo.Stream(ctx, userID, exportBatchSize, fn)
+}
+
+// Import creates one todo per valid row through the same checks as Create.
+// Rejected rows are reported and skipped; anything else, such as a database
+// error, rolls back the whole file. With dryRun nothing is written and each
+// row reports what it would create.
+func (s *todoService) Import(ctx context.Context, userID int, rows []models.TodoImportRow, dryRun bool) (*models.TodoImportResult, error) {
+	if len(rows) > MaxImportRows {
+		return nil, fmt.Errorf("%w: at most %d rows per file", ErrInvalidImport, MaxImportRows)
+	}
+
+	result := &models.TodoImportResult{DryRun: dryRun, Rows: make([]models.TodoImportRowResult, 0, len(rows))}
+	err := s.repo.Transaction(ctx, func(repo repository.TodoRepository) error {
+		for _, row := range rows {
+			item := models.TodoImportRowResult{Line: row.Line, Status: models.TodoImportFailed}
+
+			var todo *models.Todo
+			err := row.Err
+			if err == nil {
+				// prepare only reads, so a rejected row leaves the transaction usable.
+				todo, err = s.prepare(ctx, repo, userID, row.Request)
+				if err != nil && !isRejectedRow(err) {
+					return err
+				}
+			}
+			if err == nil && !dryRun {
+				if err := s.create(ctx, repo, todo); err != nil {
+					return err
+				}
+			}
+
+			switch {
+			case err != nil:
+				item.Error = err.Error()
+				result.Failed++
+			case dryRun:
+				item.Status = models.TodoImportValid
+				item.Todo = todo
+				result.Valid++
+			default:
+				item.Status = models.TodoImportCreated
+				item.Todo = todo
+				result.Created++
+			}
+			result.Rows = append(result.Rows, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// isRejectedRow reports errors caused by the row's content, as opposed to
+// failures that stop the whole import.
+func isRejectedRow(err error) bool {
+	return IsInvalidTodoInput(err) ||
+		errors.Is(err, ErrInvalidTransition) ||
+		errors.Is(err, ErrListArchived) ||
+		errors.Is(err, ErrForbidden)
+}
@@ -1,14 +1,12 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/lib/pq"
 	"github.com/manish-npx/todo-go-echo/internal/models"
 	"github.com/manish-npx/todo-go-echo/internal/repository"
 	"golang.org/x/crypto/bcrypt"
@@ -19,11 +17,11 @@ var ErrUserNotFound = errors.New("user not found")
 
 // Service CONTRACT
 type UserService interface {
-	Register(req models.RegisterRequest) error
-	CreateUser(req models.CreateUserRequest) error
-	Login(req models.LoginRequest) (string, error)
-	GetUsers() ([]models.User, error)
-	GetProfile(userID int) (*models.User, error)
+	Register(ctx context.Context, req models.RegisterRequest) error
+	CreateUser(ctx context.Context, req models.CreateUserRequest) error
+	Login(ctx context.Context, req models.LoginRequest) (string, error)
+	GetUsers(ctx context.Context) ([]models.User, error)
+	GetProfile(ctx context.Context, userID int) (*models.User, error)
 }
 
 // actual service struct
@@ -41,16 +39,16 @@ func NewUserService(repo repository.UserRepository, secret string) UserService {
 }
 
 // Register handles password hashing + save to DB
-func (s *userService) Register(req models.RegisterRequest) error {
-	return s.createUser(req.Name, req.Email, req.Mobile, req.Password)
+func (s *userService) Register(ctx context.Context, req models.RegisterRequest) error {
+	return s.createUser(ctx, req.Name, req.Email, req.Mobile, req.Password)
 }
 
 // CreateUser is a protected API path to create users.
-func (s *userService) CreateUser(req models.CreateUserRequest) error {
-	return s.createUser(req.Name, req.Email, req.Mobile, req.Password)
+func (s *userService) CreateUser(ctx context.Context, req models.CreateUserRequest) error {
+	return s.createUser(ctx, req.Name, req.Email, req.Mobile, req.Password)
 }
 
-func (s *userService) createUser(name, email, mobile, plainPassword string) error {
+func (s *userService) createUser(ctx context.Context, name, email, mobile, plainPassword string) error {
 	// hash password before storing
 	hashedPassword, err := bcrypt.GenerateFromPassword(
 		[]byte(plainPassword),
@@ -67,7 +65,7 @@ func (s *userService) createUser(name, email, mobile, plainPassword string) erro
 		Password: string(hashedPassword),
 	}
 
-	if err := s.repo.Create(&user); err != nil {
+	if err := s.repo.Create(ctx, &user); err != nil {
 		if isDuplicateEmailError(err) {
 			return ErrEmailAlreadyExists
 		}
@@ -78,9 +76,9 @@ func (s *userService) createUser(name, email, mobile, plainPassword string) erro
 }
 
 // Login verifies password and generates JWT
-func (s *userService) Login(req models.LoginRequest) (string, error) {
+func (s *userService) Login(ctx context.Context, req models.LoginRequest) (string, error) {
 
-	user, err := s.repo.GetByEmail(req.Email)
+	user, err := s.repo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return "", ErrUserNotFound
 	}
@@ -104,13 +102,13 @@ func (s *userService) Login(req models.LoginRequest) (string, error) {
 }
 
 // GetUsers returns all users
-func (s *userService) GetUsers() ([]models.User, error) {
-	return s.repo.GetAll()
+func (s *userService) GetUsers(ctx context.Context) ([]models.User, error) {
+	return s.repo.GetAll(ctx)
 }
 
 // GetProfile fetches currently authenticated user by ID.
-func (s *userService) GetProfile(userID int) (*models.User, error) {
-	user, err := s.repo.GetByID(userID)
+func (s *userService) GetProfile(ctx context.Context, userID int) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -121,19 +119,5 @@ func (s *userService) GetProfile(userID int) (*models.User, error) {
 }
 
 func isDuplicateEmailError(err error) bool {
-	var pqErr *pq.Error
-	if errors.As(err, &pqErr) {
-		return string(pqErr.Code) == "23505" &&
-			(pqErr.Constraint == "uni_users_email" || pqErr.Constraint == "users_email_key")
-	}
-
-	var pgxErr *pgconn.PgError
-	if errors.As(err, &pgxErr) {
-		return pgxErr.Code == "23505" &&
-			(pgxErr.ConstraintName == "uni_users_email" || pgxErr.ConstraintName == "users_email_key")
-	}
-
-	// Fallback for wrapped/driver-agnostic errors.
-	return strings.Contains(err.Error(), "SQLSTATE 23505") &&
-		strings.Contains(err.Error(), "users_email")
+	return isUniqueViolation(err, "uni_users_email", "users_email_key")
 }
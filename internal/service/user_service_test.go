@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -16,7 +17,7 @@ type userRepoMock struct {
 	createErr error
 }
 
-func (m *userRepoMock) Create(user *models.User) error {
+func (m *userRepoMock) Create(ctx context.Context, user *models.User) error {
 	if m.createErr != nil {
 		return m.createErr
 	}
@@ -28,7 +29,7 @@ func (m *userRepoMock) Create(user *models.User) error {
 	return nil
 }
 
-func (m *userRepoMock) GetByEmail(email string) (*models.User, error) {
+func (m *userRepoMock) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	user, ok := m.users[email]
 	if !ok {
 		return nil, sql.ErrNoRows
@@ -36,7 +37,7 @@ func (m *userRepoMock) GetByEmail(email string) (*models.User, error) {
 	return user, nil
 }
 
-func (m *userRepoMock) GetByID(id int) (*models.User, error) {
+func (m *userRepoMock) GetByID(ctx context.Context, id int) (*models.User, error) {
 	for _, user := range m.users {
 		if user.ID == id {
 			return user, nil
@@ -45,7 +46,7 @@ func (m *userRepoMock) GetByID(id int) (*models.User, error) {
 	return nil, sql.ErrNoRows
 }
 
-func (m *userRepoMock) GetAll() ([]models.User, error) {
+func (m *userRepoMock) GetAll(ctx context.Context) ([]models.User, error) {
 	users := make([]models.User, 0, len(m.users))
 	for _, user := range m.users {
 		users = append(users, *user)
@@ -54,6 +55,7 @@ func (m *userRepoMock) GetAll() ([]models.User, error) {
 }
 
 func TestUserServiceRegisterHashesPassword(t *testing.T) {
+	ctx := context.Background()
 	repo := &userRepoMock{users: map[string]*models.User{}}
 	svc := NewUserService(repo, "test-secret")
 
@@ -64,7 +66,7 @@ func TestUserServiceRegisterHashesPassword(t *testing.T) {
 		Password: "plain-password",
 	}
 
-	if err := svc.Register(req); err != nil {
+	if err := svc.Register(ctx, req); err != nil {
 		t.Fatalf("Register() error = %v", err)
 	}
 
@@ -81,12 +83,13 @@ func TestUserServiceRegisterHashesPassword(t *testing.T) {
 }
 
 func TestUserServiceRegisterDuplicateEmail(t *testing.T) {
+	ctx := context.Background()
 	repo := &userRepoMock{
 		createErr: &pq.Error{Code: "23505", Constraint: "uni_users_email"},
 	}
 	svc := NewUserService(repo, "test-secret")
 
-	err := svc.Register(models.RegisterRequest{
+	err := svc.Register(ctx, models.RegisterRequest{
 		Name:     "Manish",
 		Email:    "manish@example.com",
 		Mobile:   "9999999999",
@@ -102,6 +105,7 @@ func TestUserServiceRegisterDuplicateEmail(t *testing.T) {
 }
 
 func TestUserServiceLoginReturnsJWT(t *testing.T) {
+	ctx := context.Background()
 	hash, err := bcrypt.GenerateFromPassword([]byte("secret-pass"), bcrypt.DefaultCost)
 	if err != nil {
 		t.Fatalf("failed to build test hash: %v", err)
@@ -119,7 +123,7 @@ func TestUserServiceLoginReturnsJWT(t *testing.T) {
 	const signingSecret = "test-secret"
 	svc := NewUserService(repo, signingSecret)
 
-	tokenString, err := svc.Login(models.LoginRequest{
+	tokenString, err := svc.Login(ctx, models.LoginRequest{
 		Email:    "manish@example.com",
 		Password: "secret-pass",
 	})
@@ -142,6 +146,7 @@ func TestUserServiceLoginReturnsJWT(t *testing.T) {
 }
 
 func TestUserServiceLoginInvalidPassword(t *testing.T) {
+	ctx := context.Background()
 	hash, err := bcrypt.GenerateFromPassword([]byte("right-pass"), bcrypt.DefaultCost)
 	if err != nil {
 		t.Fatalf("failed to build test hash: %v", err)
@@ -158,7 +163,7 @@ func TestUserServiceLoginInvalidPassword(t *testing.T) {
 	}
 	svc := NewUserService(repo, "test-secret")
 
-	_, err = svc.Login(models.LoginRequest{
+	_, err = svc.Login(ctx, models.LoginRequest{
 		Email:    "manish@example.com",
 		Password: "wrong-pass",
 	})
@@ -0,0 +1,42 @@
+// Package storage keeps uploaded files (blobs) apart from the database rows
+// that describe them. Keys are slash-separated paths chosen by the caller.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+)
+
+// ErrNotFound is returned by Get for keys that hold no blob.
+var ErrNotFound = errors.New("blob not found")
+
+// Storage stores blobs by key.
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any blob there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob for streaming; the caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob; a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+const defaultLocalDir = "data/attachments"
+
+// New builds the backend named in cfg.Storage.
+func New(cfg config.AttachmentConfig) (Storage, error) {
+	switch cfg.Storage {
+	case "", "local":
+		dir := cfg.LocalDir
+		if dir == "" {
+			dir = defaultLocalDir
+		}
+		return NewLocal(dir), nil
+	case "s3":
+		return NewS3(cfg.S3)
+	}
+	return nil, fmt.Errorf("unknown attachment storage %q", cfg.Storage)
+}
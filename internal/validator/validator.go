@@ -1,7 +1,12 @@
 package validator
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+	"github.com/manish-npx/todo-go-echo/internal/recurrence"
 )
 
 // CustomValidator wraps validator
@@ -11,8 +16,14 @@ type CustomValidator struct {
 
 // New creates validator instance
 func New() *CustomValidator {
+	v := validator.New()
+	// Registration only fails for empty tags or nil funcs.
+	_ = v.RegisterValidation("due_date", isDueDate)
+	_ = v.RegisterValidation("rrule", isRecurrenceRule)
+	_ = v.RegisterValidation("notblank", validators.NotBlank)
+
 	return &CustomValidator{
-		validator: validator.New(),
+		validator: v,
 	}
 }
 
@@ -20,3 +31,28 @@ func New() *CustomValidator {
 func (cv *CustomValidator) Validate(i interface{}) error {
 	return cv.validator.Struct(i)
 }
+
+// ValidateExcept validates i like Validate but skips the named fields.
+func (cv *CustomValidator) ValidateExcept(i interface{}, fields ...string) error {
+	return cv.validator.StructExcept(i, fields...)
+}
+
+// isDueDate accepts RFC 3339 or date-only values; empty means "clear".
+func isDueDate(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+	_, err := models.ParseDueDate(value, time.UTC)
+	return err == nil
+}
+
+// isRecurrenceRule accepts the supported RRULE subset; empty means "no recurrence".
+func isRecurrenceRule(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+	_, err := recurrence.Parse(value)
+	return err == nil
+}
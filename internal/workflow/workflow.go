@@ -0,0 +1,121 @@
+// Package workflow holds the statuses a todo moves through and which moves
+// between them are allowed.
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+// ErrInvalidWorkflow is wrapped by every New error.
+var ErrInvalidWorkflow = errors.New("invalid workflow")
+
+// statusName fits the todos.status column.
+var statusName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,19}$`)
+
+// defaultConfig is todo → in_progress → done, with blocked reachable from
+// any open status and done todos reopenable.
+var defaultConfig = config.WorkflowConfig{
+	Statuses: []string{"todo", "in_progress", "blocked", "done"},
+	Transitions: map[string][]string{
+		"todo":        {"in_progress", "blocked", "done"},
+		"in_progress": {"todo", "blocked", "done"},
+		"blocked":     {"todo", "in_progress"},
+		"done":        {"todo", "in_progress"},
+	},
+}
+
+// Workflow is an ordered status set with its allowed transitions.
+type Workflow struct {
+	statuses []models.TodoStatus
+	next     map[models.TodoStatus][]models.TodoStatus
+}
+
+// Default returns the built-in workflow.
+func Default() *Workflow {
+	w, err := New(config.WorkflowConfig{})
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// New builds a workflow from config; without statuses it is the default one.
+// The set must include models.StatusTodo, where new todos start, and
+// models.StatusDone, the only status that counts as completed.
+func New(cfg config.WorkflowConfig) (*Workflow, error) {
+	if len(cfg.Statuses) == 0 {
+		cfg = defaultConfig
+	}
+
+	w := &Workflow{next: make(map[models.TodoStatus][]models.TodoStatus, len(cfg.Statuses))}
+	for _, name := range cfg.Statuses {
+		if !statusName.MatchString(name) {
+			return nil, fmt.Errorf("%w: status %q must be lowercase letters, digits or _ and at most 20 long", ErrInvalidWorkflow, name)
+		}
+		status := models.TodoStatus(name)
+		if slices.Contains(w.statuses, status) {
+			return nil, fmt.Errorf("%w: status %q is listed twice", ErrInvalidWorkflow, name)
+		}
+		w.statuses = append(w.statuses, status)
+	}
+	for _, required := range []models.TodoStatus{models.StatusTodo, models.StatusDone} {
+		if !w.Valid(required) {
+			return nil, fmt.Errorf("%w: status %q is required", ErrInvalidWorkflow, required)
+		}
+	}
+
+	for from, targets := range cfg.Transitions {
+		if !w.Valid(models.TodoStatus(from)) {
+			return nil, fmt.Errorf("%w: transition from unknown status %q", ErrInvalidWorkflow, from)
+		}
+		for _, to := range targets {
+			if !w.Valid(models.TodoStatus(to)) {
+				return nil, fmt.Errorf("%w: transition from %q to unknown status %q", ErrInvalidWorkflow, from, to)
+			}
+			if to != from && !slices.Contains(w.next[models.TodoStatus(from)], models.TodoStatus(to)) {
+				w.next[models.TodoStatus(from)] = append(w.next[models.TodoStatus(from)], models.TodoStatus(to))
+			}
+		}
+	}
+	// Keep Next in board order whatever order the config used.
+	for from := range w.next {
+		slices.SortFunc(w.next[from], func(a, b models.TodoStatus) int {
+			return slices.Index(w.statuses, a) - slices.Index(w.statuses, b)
+		})
+	}
+	return w, nil
+}
+
+// Statuses returns every status in board order.
+func (w *Workflow) Statuses() []models.TodoStatus {
+	return slices.Clone(w.statuses)
+}
+
+// Valid reports whether status is part of the workflow.
+func (w *Workflow) Valid(status models.TodoStatus) bool {
+	return slices.Contains(w.statuses, status)
+}
+
+// Next returns the statuses a todo in from may move to.
+func (w *Workflow) Next(from models.TodoStatus) []models.TodoStatus {
+	return append([]models.TodoStatus{}, w.next[from]...)
+}
+
+// CanMove reports whether a todo may go from one status to another. Staying
+// put is always allowed, and a todo left in a status since dropped from the
+// config may move to any status.
+func (w *Workflow) CanMove(from, to models.TodoStatus) bool {
+	if from == to {
+		return true
+	}
+	if !w.Valid(from) {
+		return w.Valid(to)
+	}
+	return slices.Contains(w.next[from], to)
+}
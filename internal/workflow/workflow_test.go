@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/manish-npx/todo-go-echo/internal/config"
+	"github.com/manish-npx/todo-go-echo/internal/models"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.WorkflowConfig
+		want    []models.TodoStatus
+		wantErr bool
+	}{
+		{
+			name: "default",
+			want: []models.TodoStatus{"todo", "in_progress", "blocked", "done"},
+		},
+		{
+			name: "custom",
+			cfg: config.WorkflowConfig{
+				Statuses:    []string{"todo", "review", "done"},
+				Transitions: map[string][]string{"todo": {"review"}, "review": {"todo", "done"}},
+			},
+			want: []models.TodoStatus{"todo", "review", "done"},
+		},
+		{name: "missing done", cfg: config.WorkflowConfig{Statuses: []string{"todo", "doing"}}, wantErr: true},
+		{name: "missing todo", cfg: config.WorkflowConfig{Statuses: []string{"open", "done"}}, wantErr: true},
+		{name: "duplicate", cfg: config.WorkflowConfig{Statuses: []string{"todo", "done", "todo"}}, wantErr: true},
+		{name: "bad name", cfg: config.WorkflowConfig{Statuses: []string{"todo", "In Review", "done"}}, wantErr: true},
+		{name: "too long", cfg: config.WorkflowConfig{Statuses: []string{"todo", "waiting_for_someone_else", "done"}}, wantErr: true},
+		{
+			name:    "unknown source",
+			cfg:     config.WorkflowConfig{Statuses: []string{"todo", "done"}, Transitions: map[string][]string{"doing": {"done"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown target",
+			cfg:     config.WorkflowConfig{Statuses: []string{"todo", "done"}, Transitions: map[string][]string{"todo": {"doing"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := New(tt.cfg)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidWorkflow) {
+					t.Fatalf("New() error = %v, want ErrInvalidWorkflow", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := w.Statuses(); !slices.Equal(got, tt.want) {
+				t.Fatalf("Statuses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanMove(t *testing.T) {
+	w := Default()
+	tests := []struct {
+		from, to models.TodoStatus
+		want     bool
+	}{
+		{"todo", "in_progress", true},
+		{"todo", "done", true},
+		{"in_progress", "blocked", true},
+		{"blocked", "in_progress", true},
+		{"blocked", "done", false},
+		{"done", "todo", true},
+		{"done", "blocked", false},
+		{"done", "done", true},
+		{"todo", "review", false},
+		{"review", "todo", true}, // dropped from the config
+	}
+
+	for _, tt := range tests {
+		if got := w.CanMove(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanMove(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestNextFollowsBoardOrder(t *testing.T) {
+	w, err := New(config.WorkflowConfig{
+		Statuses:    []string{"todo", "doing", "done"},
+		Transitions: map[string][]string{"todo": {"done", "doing", "todo"}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := w.Next("todo"); !slices.Equal(got, []models.TodoStatus{"doing", "done"}) {
+		t.Fatalf("Next(todo) = %v", got)
+	}
+	if got := w.Next("done"); got == nil || len(got) != 0 {
+		t.Fatalf("Next(done) = %#v, want empty", got)
+	}
+}
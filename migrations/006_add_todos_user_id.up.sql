@@ -0,0 +1,4 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS user_id INT REFERENCES users(id) ON DELETE CASCADE;
+
+-- Legacy rows keep a NULL owner and are no longer visible through the API.
+CREATE INDEX IF NOT EXISTS idx_todos_user_id ON todos(user_id);
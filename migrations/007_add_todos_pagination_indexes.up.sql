@@ -0,0 +1,4 @@
+-- Keyset pagination orders by (column, id) within a single user's todos.
+CREATE INDEX IF NOT EXISTS idx_todos_user_created_at ON todos(user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_todos_user_updated_at ON todos(user_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_todos_user_title ON todos(user_id, title, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_user_due_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS due_at;
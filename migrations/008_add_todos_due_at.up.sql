@@ -0,0 +1,3 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_due_at ON todos(user_id, due_at) WHERE completed = FALSE;
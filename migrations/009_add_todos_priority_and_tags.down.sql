@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS todo_tags;
+DROP TABLE IF EXISTS tags;
+DROP INDEX IF EXISTS idx_todos_user_priority;
+ALTER TABLE todos DROP COLUMN IF EXISTS priority;
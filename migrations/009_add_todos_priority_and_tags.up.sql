@@ -0,0 +1,22 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'none';
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_priority ON todos(user_id, priority);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tag names are unique per user regardless of case.
+CREATE UNIQUE INDEX IF NOT EXISTS uni_tags_user_name ON tags(user_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS todo_tags (
+    todo_id INT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (todo_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_tags_tag_id ON todo_tags(tag_id);
@@ -0,0 +1,5 @@
+-- No ON DELETE action: the service decides between cascading and promoting
+-- subtasks, and the constraint stops a parent from being dropped out from under them.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS parent_id INT NULL REFERENCES todos(id);
+
+CREATE INDEX IF NOT EXISTS idx_todos_parent_id ON todos(parent_id);
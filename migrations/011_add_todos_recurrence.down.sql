@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_todos_series_id;
+ALTER TABLE todos
+    DROP COLUMN IF EXISTS occurrence,
+    DROP COLUMN IF EXISTS series_id,
+    DROP COLUMN IF EXISTS recurrence_rule;
@@ -0,0 +1,8 @@
+-- series_id points at the first todo of a recurring series; that todo keeps
+-- series_id NULL. No FK so a deleted first occurrence doesn't break the series.
+ALTER TABLE todos
+    ADD COLUMN IF NOT EXISTS recurrence_rule TEXT NULL,
+    ADD COLUMN IF NOT EXISTS series_id INT NULL,
+    ADD COLUMN IF NOT EXISTS occurrence INT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_todos_series_id ON todos(series_id);
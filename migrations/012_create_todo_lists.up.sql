@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS todo_lists (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    archived_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_lists_user_id ON todo_lists(user_id);
+
+-- Deleting a list keeps its todos; they fall back to having no list.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS list_id INT NULL REFERENCES todo_lists(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todos_list_id ON todos(list_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_blogs_deleted_at;
+DROP INDEX IF EXISTS idx_todos_deleted_at;
+-- Rows still in the trash would reappear once the column is gone.
+DELETE FROM blogs WHERE deleted_at IS NOT NULL;
+DELETE FROM todos WHERE deleted_at IS NOT NULL;
+ALTER TABLE blogs DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,6 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+
+-- Trash listings and the purge job only ever look at deleted rows.
+CREATE INDEX IF NOT EXISTS idx_todos_deleted_at ON todos(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_blogs_deleted_at ON blogs(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS uni_todos_inbox_position;
+DROP INDEX IF EXISTS uni_todos_list_position;
+ALTER TABLE todos DROP COLUMN IF EXISTS position;
@@ -0,0 +1,24 @@
+-- Manual order. Keys are compared byte-wise, hence the C collation.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS position TEXT COLLATE "C";
+
+-- Backfill in creation order within each list (or each user's todos without
+-- a list). Fixed-width numbers keep the order; the trailing 'V' keeps keys
+-- from ending in '0', which the rank package needs.
+UPDATE todos t
+SET position = ranked.position
+FROM (
+    SELECT id,
+           LPAD(ROW_NUMBER() OVER (
+               PARTITION BY list_id, CASE WHEN list_id IS NULL THEN user_id END
+               ORDER BY created_at, id
+           )::TEXT, 10, '0') || 'V' AS position
+    FROM todos
+) ranked
+WHERE t.id = ranked.id;
+
+ALTER TABLE todos ALTER COLUMN position SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uni_todos_list_position
+    ON todos(list_id, position) WHERE list_id IS NOT NULL AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uni_todos_inbox_position
+    ON todos(user_id, position) WHERE list_id IS NULL AND deleted_at IS NULL;
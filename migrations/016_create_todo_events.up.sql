@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS todo_events (
+    id SERIAL PRIMARY KEY,
+    todo_id INT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    -- The acting user; kept as NULL once that account is gone.
+    user_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated', 'completed', 'reopened', 'deleted', 'restored')),
+    changes JSONB NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_events_todo_id ON todo_events(todo_id, created_at);
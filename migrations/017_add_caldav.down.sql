@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS app_passwords;
+DROP INDEX IF EXISTS uni_todos_caldav_name;
+ALTER TABLE todos DROP COLUMN IF EXISTS caldav_name;
+ALTER TABLE todos DROP COLUMN IF EXISTS ical_uid;
@@ -0,0 +1,20 @@
+-- CalDAV clients pick their own UID and resource name for todos they create.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS ical_uid VARCHAR(255) NULL;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS caldav_name VARCHAR(255) NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uni_todos_caldav_name
+    ON todos(user_id, caldav_name)
+    WHERE caldav_name IS NOT NULL AND deleted_at IS NULL;
+
+-- App passwords let CalDAV clients sign in without the account password.
+CREATE TABLE IF NOT EXISTS app_passwords (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    password_hash TEXT NOT NULL,
+    last_used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_app_passwords_user_id ON app_passwords(user_id);
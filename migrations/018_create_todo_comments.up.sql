@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS todo_comments (
+    id SERIAL PRIMARY KEY,
+    todo_id INT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    -- Set on every edit by the author; NULL while the comment is unchanged.
+    edited_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_comments_todo_id ON todo_comments(todo_id, created_at);
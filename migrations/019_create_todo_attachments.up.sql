@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS todo_attachments (
+    id SERIAL PRIMARY KEY,
+    todo_id INT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    -- The uploader; NULL once that account is gone.
+    user_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    -- Where the blob lives in the configured storage backend.
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_attachments_todo_id ON todo_attachments(todo_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_todos_user_status;
+
+ALTER TABLE todos ADD COLUMN completed_flag BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE todos SET completed_flag = completed;
+ALTER TABLE todos DROP COLUMN completed;
+ALTER TABLE todos RENAME COLUMN completed_flag TO completed;
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_due_at ON todos(user_id, due_at) WHERE completed = FALSE;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS status;
@@ -0,0 +1,12 @@
+-- Workflow status. The set of statuses is configured, so there is no CHECK.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'todo';
+
+UPDATE todos SET status = 'done' WHERE completed;
+
+-- completed stays for existing queries and clients, now derived from status.
+-- Dropping the column drops idx_todos_user_due_at with it.
+ALTER TABLE todos DROP COLUMN completed;
+ALTER TABLE todos ADD COLUMN completed BOOLEAN GENERATED ALWAYS AS (status = 'done') STORED;
+
+CREATE INDEX IF NOT EXISTS idx_todos_user_due_at ON todos(user_id, due_at) WHERE completed = FALSE;
+CREATE INDEX IF NOT EXISTS idx_todos_user_status ON todos(user_id, status) WHERE deleted_at IS NULL;
@@ -0,0 +1,11 @@
+-- todo_id can't be completed until blocked_by_id is done. Cycles are
+-- rejected by the service.
+CREATE TABLE IF NOT EXISTS todo_dependencies (
+    todo_id INT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    blocked_by_id INT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (todo_id, blocked_by_id),
+    CHECK (todo_id <> blocked_by_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_dependencies_blocked_by_id ON todo_dependencies(blocked_by_id);
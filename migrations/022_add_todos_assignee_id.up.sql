@@ -0,0 +1,4 @@
+-- Who does the todo; it stays visible to them whoever owns it.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS assignee_id INT NULL REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todos_assignee_id ON todos(assignee_id) WHERE deleted_at IS NULL;
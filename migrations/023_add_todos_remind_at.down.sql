@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_todos_pending_reminder;
+ALTER TABLE todos DROP COLUMN IF EXISTS reminded_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS remind_at;
@@ -0,0 +1,7 @@
+-- remind_at is when to send a reminder. reminded_at is set when the reminder
+-- is claimed for sending, so it goes out at most once until remind_at changes.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS remind_at TIMESTAMPTZ NULL;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todos_pending_reminder ON todos(remind_at)
+	WHERE reminded_at IS NULL AND deleted_at IS NULL;
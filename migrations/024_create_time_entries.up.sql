@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS time_entries (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    todo_id INT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    -- NULL while the timer runs.
+    ended_at TIMESTAMPTZ NULL,
+    note VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+-- One running timer per user.
+CREATE UNIQUE INDEX IF NOT EXISTS uni_time_entries_running ON time_entries(user_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_started_at ON time_entries(user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_todo_id ON time_entries(todo_id);
@@ -0,0 +1,3 @@
+-- Zone a recurring todo's rule is evaluated in, so weekdays and wall-clock
+-- times follow the user rather than UTC. '' means UTC.
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';